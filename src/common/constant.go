@@ -44,6 +44,9 @@ const (
 
 const GasMagnification = 30
 
+// BlockGasLimit is the gas all the contract txs of a block may use at most since Proposal029
+const BlockGasLimit uint64 = 6000000000
+
 var (
 	hashT    = reflect.TypeOf(Hash{})
 	addressT = reflect.TypeOf(Address{})
@@ -53,6 +56,7 @@ var (
 	ValidatorDBAddress = BigToAddress(big.NewInt(1))
 	ProposerDBAddress  = BigToAddress(big.NewInt(2))
 	DifficultyAddress  = BigToAddress(big.NewInt(3))
+	EvidenceDBAddress  = BigToAddress(big.NewInt(4))
 	TotalWorkingMiners = []byte{0}
 )
 
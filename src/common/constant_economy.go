@@ -49,6 +49,12 @@ const (
 	HeightAfterStake = 300
 )
 
+// the percent of the stake burnt for the evidences since Proposal030
+const (
+	DoubleSignSlashPercent = uint64(10)
+	AbsenceSlashPercent    = uint64(1)
+)
+
 const (
 	BLANCE_NAME = "SYSTEM-RPG"
 )
@@ -31,3 +31,11 @@ func GetGroupWorkDuration() uint64 {
 
 	return groupWorkDuration
 }
+
+// AbsenceMinBlocks is the blocks a group signs in an epoch at least before its members can be reported absent
+const AbsenceMinBlocks = 10
+
+// GetGroupEpoch is the epoch of the height the absence of the group members is counted in
+func GetGroupEpoch(height uint64) uint64 {
+	return height / GetGroupWorkDuration()
+}
@@ -0,0 +1,174 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"fmt"
+	"math"
+)
+
+// ForkID names a rule change of the chain
+type ForkID int
+
+const (
+	Proposal001 ForkID = iota
+	Proposal002
+	Proposal003
+	Proposal004
+	Proposal005
+	Proposal006
+	Proposal007
+	Proposal008
+	Proposal009
+	Proposal010
+	Proposal011
+	Proposal012
+	Proposal013
+	Proposal014
+	Proposal015
+	Proposal016
+	Proposal017
+	Proposal018
+	Proposal019
+	Proposal020
+	Proposal021
+	Proposal022
+	Proposal023
+	Proposal024
+	Proposal025
+	Proposal026
+	Proposal027
+	Proposal028
+	Proposal029
+	Proposal030
+	Proposal031
+
+	forkCount
+)
+
+// NeverForked is the activation height of a fork the chain never takes
+const NeverForked = uint64(math.MaxUint64)
+
+func (id ForkID) String() string {
+	return fmt.Sprintf("Proposal%03d", int(id)+1)
+}
+
+// Fork is a named fork with its activation height
+type Fork struct {
+	Name  string
+	Block uint64
+}
+
+// ForkSchedule keeps the activation height of every fork, indexed by ForkID
+type ForkSchedule [forkCount]uint64
+
+// Forks lists the schedule in ForkID order
+func (s ForkSchedule) Forks() []Fork {
+	forks := make([]Fork, 0, forkCount)
+	for id := ForkID(0); id < forkCount; id++ {
+		forks = append(forks, Fork{Name: id.String(), Block: s[id]})
+	}
+	return forks
+}
+
+// override sets the activation heights by fork name, as the "forks" of genesis.json
+func (s *ForkSchedule) override(forks map[string]uint64) error {
+	ids := make(map[string]ForkID, forkCount)
+	for id := ForkID(0); id < forkCount; id++ {
+		ids[id.String()] = id
+	}
+
+	for name, block := range forks {
+		id, ok := ids[name]
+		if !ok {
+			return fmt.Errorf("unknown fork: %s", name)
+		}
+		s[id] = block
+	}
+	return nil
+}
+
+// Rules tells which forks are active at a height
+type Rules struct {
+	Height uint64
+
+	IsProposal001, IsProposal002, IsProposal003, IsProposal004, IsProposal005 bool
+	IsProposal006, IsProposal007, IsProposal008, IsProposal009, IsProposal010 bool
+	IsProposal011, IsProposal012, IsProposal013, IsProposal014, IsProposal015 bool
+	IsProposal016, IsProposal017, IsProposal018, IsProposal019, IsProposal020 bool
+	IsProposal021, IsProposal022, IsProposal023, IsProposal024, IsProposal025 bool
+	IsProposal026, IsProposal027, IsProposal028, IsProposal029, IsProposal030 bool
+	IsProposal031                                                             bool
+}
+
+func (s ForkSchedule) Rules(height uint64) Rules {
+	isForked := func(id ForkID) bool {
+		return height >= s[id]
+	}
+
+	return Rules{
+		Height: height,
+
+		IsProposal001: isForked(Proposal001),
+		IsProposal002: isForked(Proposal002),
+		IsProposal003: isForked(Proposal003),
+		IsProposal004: isForked(Proposal004),
+		IsProposal005: isForked(Proposal005),
+		// user nonce
+		IsProposal006: isForked(Proposal006),
+		IsProposal007: isForked(Proposal007),
+		IsProposal008: isForked(Proposal008),
+		IsProposal009: isForked(Proposal009),
+		IsProposal010: isForked(Proposal010),
+		IsProposal011: isForked(Proposal011),
+		IsProposal012: isForked(Proposal012),
+		IsProposal013: isForked(Proposal013),
+		IsProposal014: isForked(Proposal014),
+		IsProposal015: isForked(Proposal015),
+		IsProposal016: isForked(Proposal016),
+		IsProposal017: isForked(Proposal017),
+		IsProposal018: isForked(Proposal018),
+		IsProposal019: isForked(Proposal019),
+		IsProposal020: isForked(Proposal020),
+		IsProposal021: isForked(Proposal021),
+		IsProposal022: isForked(Proposal022),
+		// sort for transactions
+		IsProposal023: isForked(Proposal023),
+		IsProposal024: isForked(Proposal024),
+		IsProposal025: isForked(Proposal025),
+		IsProposal026: isForked(Proposal026),
+		IsProposal027: isForked(Proposal027),
+		// PREVRANDAO from the group signature
+		IsProposal028: isForked(Proposal028),
+		// block gas limit, gas used and cumulative receipt gas
+		IsProposal029: isForked(Proposal029),
+		// slashing by the evidences of group members
+		IsProposal030: isForked(Proposal030),
+		// typed transactions and access lists
+		IsProposal031: isForked(Proposal031),
+	}
+}
+
+// Rules of the local chain at a block height
+func (config *ChainConfig) Rules(height uint64) Rules {
+	return config.Forks.Rules(height)
+}
+
+// CurrentRules are the rules at the height of the chain top, as checked by the executors and the txpool
+func CurrentRules() Rules {
+	return LocalChainConfig.Rules(GetBlockHeight())
+}
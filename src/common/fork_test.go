@@ -0,0 +1,64 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"testing"
+)
+
+func TestForkScheduleRules(t *testing.T) {
+	schedule := mainNetChainConfig.Forks
+
+	rules := schedule.Rules(schedule[Proposal026] - 1)
+	if !rules.IsProposal025 || rules.IsProposal026 || rules.IsProposal027 {
+		t.Fatalf("wrong rules before Proposal026: %+v", rules)
+	}
+	rules = schedule.Rules(schedule[Proposal026])
+	if !rules.IsProposal026 || rules.IsProposal027 {
+		t.Fatalf("wrong rules at Proposal026: %+v", rules)
+	}
+	if schedule.Rules(NeverForked - 1).IsProposal010 {
+		t.Fatalf("Proposal010 forked on mainnet")
+	}
+}
+
+func TestForkScheduleOverride(t *testing.T) {
+	schedule := subNetChainConfig.Forks
+	if err := schedule.override(map[string]uint64{"Proposal028": 100, "Proposal029": NeverForked}); err != nil {
+		t.Fatal(err)
+	}
+	if schedule[Proposal028] != 100 || schedule[Proposal029] != NeverForked || schedule[Proposal027] != 0 {
+		t.Fatalf("wrong schedule: %v", schedule.Forks())
+	}
+	if subNetChainConfig.Forks[Proposal028] != 0 {
+		t.Fatalf("default schedule changed")
+	}
+
+	if err := schedule.override(map[string]uint64{"Proposal099": 1}); err == nil {
+		t.Fatalf("unknown fork accepted")
+	}
+}
+
+func TestForkNames(t *testing.T) {
+	forks := devNetChainConfig.Forks.Forks()
+	if len(forks) != 31 || forks[0].Name != "Proposal001" || forks[30].Name != "Proposal031" {
+		t.Fatalf("wrong forks: %v", forks)
+	}
+	if forks[Proposal020].Block != 10 {
+		t.Fatalf("wrong Proposal020 block: %d", forks[Proposal020].Block)
+	}
+}
@@ -45,4 +45,7 @@ type GenesisConf struct {
 	ProposerInfo []string `json:"proposers"`
 
 	Dev byte `json:"dev"`
+
+	// activation height by fork name, e.g. "Proposal028": 1000
+	Forks map[string]uint64 `json:"forks"`
 }
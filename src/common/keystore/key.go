@@ -0,0 +1,145 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	version = 3
+
+	privateKeyLength = 32
+)
+
+// Key is an account key in the keystore
+type Key struct {
+	Id         string
+	Address    common.Address
+	PrivateKey *common.PrivateKey
+}
+
+// NewKey generates a new random key
+func NewKey() *Key {
+	privateKey := common.GenerateKey("")
+	return newKeyFromPrivateKey(&privateKey)
+}
+
+// NewKeyFromHex creates the key of a hex encoded private key
+func NewKeyFromHex(privateKey string) (*Key, error) {
+	if !strings.HasPrefix(privateKey, common.PREFIX) {
+		privateKey = common.PREFIX + privateKey
+	}
+	data := common.FromHex(privateKey)
+	if privateKeyLength != len(data) {
+		return nil, ErrInvalidPrivateKey
+	}
+	sk := common.HexStringToSecKey(privateKey)
+	if nil == sk {
+		return nil, ErrInvalidPrivateKey
+	}
+	return newKeyFromPrivateKey(sk), nil
+}
+
+func newKeyFromPrivateKey(privateKey *common.PrivateKey) *Key {
+	publicKey := privateKey.GetPubKey()
+	return &Key{
+		Id:         newUUID(),
+		Address:    publicKey.GetAddress(),
+		PrivateKey: privateKey,
+	}
+}
+
+// privateKeyBytes returns the 32 bytes private key, left padded
+func (key *Key) privateKeyBytes() []byte {
+	data := make([]byte, privateKeyLength)
+	d := key.PrivateKey.PrivKey.D.Bytes()
+	copy(data[privateKeyLength-len(d):], d)
+	return data
+}
+
+// encryptedKeyJSON is the web3 secret storage format, shared with geth and metamask
+type encryptedKeyJSON struct {
+	Address string     `json:"address"`
+	Crypto  cryptoJSON `json:"crypto"`
+	Id      string     `json:"id"`
+	Version int        `json:"version"`
+}
+
+type cryptoJSON struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams cipherparamsJSON       `json:"cipherparams"`
+	KDF          string                 `json:"kdf"`
+	KDFParams    map[string]interface{} `json:"kdfparams"`
+	MAC          string                 `json:"mac"`
+}
+
+type cipherparamsJSON struct {
+	IV string `json:"iv"`
+}
+
+// keyAddress returns the address of the key json without decrypting it
+func keyAddress(keyJSON []byte) (common.Address, error) {
+	var key struct {
+		Address string `json:"address"`
+	}
+	if err := json.Unmarshal(keyJSON, &key); err != nil {
+		return common.Address{}, err
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(key.Address, common.PREFIX))
+	if err != nil || common.AddressLength != len(data) {
+		return common.Address{}, ErrInvalidKey
+	}
+	return common.BytesToAddress(data), nil
+}
+
+// keyFileName returns the file name of the key, as geth does: UTC--<created_at UTC ISO8601>--<address hex>
+func keyFileName(address common.Address) string {
+	ts := time.Now().UTC()
+	return fmt.Sprintf("UTC--%s--%s", toISO8601(ts), hex.EncodeToString(address.Bytes()))
+}
+
+func toISO8601(t time.Time) string {
+	var tz string
+	name, offset := t.Zone()
+	if name == "UTC" {
+		tz = "Z"
+	} else {
+		tz = fmt.Sprintf("%03d00", offset/3600)
+	}
+	return fmt.Sprintf("%04d-%02d-%02dT%02d-%02d-%02d.%09d%s",
+		t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), tz)
+}
+
+// newUUID returns a random version 4 uuid
+func newUUID() string {
+	var u [16]byte
+	if _, err := io.ReadFull(rand.Reader, u[:]); err != nil {
+		panic("reading random reader failed: " + err.Error())
+	}
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])
+}
@@ -0,0 +1,198 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrNoMatch           = errors.New("no key for given address")
+	ErrDecrypt           = errors.New("could not decrypt key with given password")
+	ErrInvalidKey        = errors.New("invalid key file")
+	ErrInvalidPrivateKey = errors.New("invalid private key")
+	ErrKeyExisted        = errors.New("key already exists")
+)
+
+// KeyStore keeps the keys as web3 secret storage json files in a directory,
+// so that the files can be exchanged with geth and metamask
+type KeyStore struct {
+	dir     string
+	scryptN int
+	scryptP int
+}
+
+func NewKeyStore(dir string, scryptN, scryptP int) (*KeyStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &KeyStore{dir: dir, scryptN: scryptN, scryptP: scryptP}, nil
+}
+
+// Dir returns the directory of the key files
+func (ks *KeyStore) Dir() string {
+	return ks.dir
+}
+
+// Accounts returns the addresses of the key files, sorted by file name
+func (ks *KeyStore) Accounts() []common.Address {
+	files := ks.keyFiles()
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]common.Address, 0, len(names))
+	for _, name := range names {
+		result = append(result, files[name])
+	}
+	return result
+}
+
+// HasAddress reports whether a key of the address exists
+func (ks *KeyStore) HasAddress(address common.Address) bool {
+	_, err := ks.find(address)
+	return err == nil
+}
+
+// NewAccount generates a new key encrypted with the password
+func (ks *KeyStore) NewAccount(password string) (*Key, error) {
+	key := NewKey()
+	if err := ks.StoreKey(key, password); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// StoreKey encrypts the key with the password and writes it into a new file
+func (ks *KeyStore) StoreKey(key *Key, password string) error {
+	if ks.HasAddress(key.Address) {
+		return ErrKeyExisted
+	}
+	keyJSON, err := EncryptKey(key, password, ks.scryptN, ks.scryptP)
+	if err != nil {
+		return err
+	}
+	return writeKeyFile(filepath.Join(ks.dir, keyFileName(key.Address)), keyJSON)
+}
+
+// GetKey decrypts the key of the address with the password
+func (ks *KeyStore) GetKey(address common.Address, password string) (*Key, error) {
+	keyJSON, err := ks.Export(address)
+	if err != nil {
+		return nil, err
+	}
+	key, err := DecryptKey(keyJSON, password)
+	if err != nil {
+		return nil, err
+	}
+	if key.Address != address {
+		return nil, ErrInvalidKey
+	}
+	return key, nil
+}
+
+// Import stores a key json created by another wallet, after checking the password
+func (ks *KeyStore) Import(keyJSON []byte, password string) (*Key, error) {
+	key, err := DecryptKey(keyJSON, password)
+	if err != nil {
+		return nil, err
+	}
+	if ks.HasAddress(key.Address) {
+		return nil, ErrKeyExisted
+	}
+	return key, writeKeyFile(filepath.Join(ks.dir, keyFileName(key.Address)), keyJSON)
+}
+
+// Export returns the encrypted key json of the address
+func (ks *KeyStore) Export(address common.Address) ([]byte, error) {
+	path, err := ks.find(address)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(path)
+}
+
+// Delete removes the key file of the address, the caller should have checked the password
+func (ks *KeyStore) Delete(address common.Address) error {
+	path, err := ks.find(address)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// KeyFile returns the path of the key file of the address
+func (ks *KeyStore) KeyFile(address common.Address) (string, error) {
+	return ks.find(address)
+}
+
+func (ks *KeyStore) find(address common.Address) (string, error) {
+	for name, addr := range ks.keyFiles() {
+		if addr == address {
+			return filepath.Join(ks.dir, name), nil
+		}
+	}
+	return "", ErrNoMatch
+}
+
+// keyFiles returns the address of each key file in the directory, skipping the invalid files
+func (ks *KeyStore) keyFiles() map[string]common.Address {
+	result := make(map[string]common.Address)
+	files, err := ioutil.ReadDir(ks.dir)
+	if err != nil {
+		return result
+	}
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(ks.dir, name))
+		if err != nil {
+			continue
+		}
+		address, err := keyAddress(data)
+		if err != nil {
+			continue
+		}
+		result[name] = address
+	}
+	return result
+}
+
+// writeKeyFile writes the file through a temporary file, so that a key file is never partly written
+func writeKeyFile(file string, content []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(file), "."+filepath.Base(file)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	f.Close()
+	return os.Rename(f.Name(), file)
+}
@@ -0,0 +1,109 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// test vectors of the web3 secret storage definition
+const (
+	vectorPassword   = "testpassword"
+	vectorPrivateKey = "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+	vectorAddress    = "008aeeda4d805471df9b2a5b0f38a0c3bcba786b"
+
+	pbkdf2Vector = `{"address":"008aeeda4d805471df9b2a5b0f38a0c3bcba786b","crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2","kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`
+	scryptVector = `{"address":"008aeeda4d805471df9b2a5b0f38a0c3bcba786b","crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"83dbcc02d8ccb40e466191a123791e0e"},"ciphertext":"d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c","kdf":"scrypt","kdfparams":{"dklen":32,"n":262144,"r":1,"p":8,"salt":"ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},"mac":"2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`
+)
+
+func TestDecryptVectors(t *testing.T) {
+	for _, vector := range []string{pbkdf2Vector, scryptVector} {
+		key, err := DecryptKey([]byte(vector), vectorPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(key.privateKeyBytes()) != vectorPrivateKey {
+			t.Fatalf("private key mismatch: %x", key.privateKeyBytes())
+		}
+		if hex.EncodeToString(key.Address.Bytes()) != vectorAddress {
+			t.Fatalf("address mismatch: %x", key.Address.Bytes())
+		}
+		if _, err := DecryptKey([]byte(vector), "wrong"); err != ErrDecrypt {
+			t.Fatalf("expected %v, got %v", ErrDecrypt, err)
+		}
+	}
+}
+
+func TestKeyStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ks, err := NewKeyStore(dir, LightScryptN, LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ks.NewAccount("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accounts := ks.Accounts(); 1 != len(accounts) || accounts[0] != key.Address {
+		t.Fatalf("unexpected accounts: %v", accounts)
+	}
+
+	loaded, err := ks.GetKey(key.Address, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.PrivateKey.GetHexString() != key.PrivateKey.GetHexString() || loaded.Id != key.Id {
+		t.Fatal("key mismatch")
+	}
+	if _, err := ks.GetKey(key.Address, "wrong"); err != ErrDecrypt {
+		t.Fatalf("expected %v, got %v", ErrDecrypt, err)
+	}
+
+	imported, err := ks.Import([]byte(pbkdf2Vector), vectorPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.Import([]byte(pbkdf2Vector), vectorPassword); err != ErrKeyExisted {
+		t.Fatalf("expected %v, got %v", ErrKeyExisted, err)
+	}
+	if 2 != len(ks.Accounts()) {
+		t.Fatalf("expected 2 accounts, got %d", len(ks.Accounts()))
+	}
+
+	exported, err := ks.Export(imported.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(exported) != pbkdf2Vector {
+		t.Fatal("exported key mismatch")
+	}
+
+	if err := ks.Delete(imported.Address); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.GetKey(imported.Address, vectorPassword); err != ErrNoMatch {
+		t.Fatalf("expected %v, got %v", ErrNoMatch, err)
+	}
+}
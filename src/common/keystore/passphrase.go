@@ -0,0 +1,205 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	"bytes"
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/common/sha3"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"io"
+)
+
+const (
+	keyHeaderKDF = "scrypt"
+
+	// StandardScryptN is the N parameter of Scrypt encryption algorithm, using 256MB
+	// memory and taking approximately 1s CPU time on a modern processor.
+	StandardScryptN = 1 << 18
+
+	// StandardScryptP is the P parameter of Scrypt encryption algorithm, using 256MB
+	// memory and taking approximately 1s CPU time on a modern processor.
+	StandardScryptP = 1
+
+	// LightScryptN is the N parameter of Scrypt encryption algorithm, using 4MB
+	// memory and taking approximately 100ms CPU time on a modern processor.
+	LightScryptN = 1 << 12
+
+	// LightScryptP is the P parameter of Scrypt encryption algorithm, using 4MB
+	// memory and taking approximately 100ms CPU time on a modern processor.
+	LightScryptP = 6
+
+	scryptR     = 8
+	scryptDKLen = 32
+)
+
+// EncryptKey encrypts the key with the password into the web3 secret storage json,
+// using scrypt and aes-128-ctr
+func EncryptKey(key *Key, password string, scryptN, scryptP int) ([]byte, error) {
+	salt := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	derivedKey, err := scrypt.Key([]byte(password), salt, scryptN, scryptR, scryptP, scryptDKLen)
+	if err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+	cipherText, err := aesCTRXOR(derivedKey[:16], key.privateKeyBytes(), iv)
+	if err != nil {
+		return nil, err
+	}
+
+	cryptoStruct := cryptoJSON{
+		Cipher:       "aes-128-ctr",
+		CipherText:   hex.EncodeToString(cipherText),
+		CipherParams: cipherparamsJSON{IV: hex.EncodeToString(iv)},
+		KDF:          keyHeaderKDF,
+		KDFParams: map[string]interface{}{
+			"n":     scryptN,
+			"r":     scryptR,
+			"p":     scryptP,
+			"dklen": scryptDKLen,
+			"salt":  hex.EncodeToString(salt),
+		},
+		MAC: hex.EncodeToString(keccak256(derivedKey[16:32], cipherText)),
+	}
+	return json.Marshal(encryptedKeyJSON{
+		Address: hex.EncodeToString(key.Address.Bytes()),
+		Crypto:  cryptoStruct,
+		Id:      key.Id,
+		Version: version,
+	})
+}
+
+// DecryptKey decrypts the web3 secret storage json with the password
+func DecryptKey(keyJSON []byte, password string) (*Key, error) {
+	var k encryptedKeyJSON
+	if err := json.Unmarshal(keyJSON, &k); err != nil {
+		return nil, err
+	}
+	if version != k.Version {
+		return nil, fmt.Errorf("version not supported: %v", k.Version)
+	}
+	if "aes-128-ctr" != k.Crypto.Cipher {
+		return nil, fmt.Errorf("cipher not supported: %v", k.Crypto.Cipher)
+	}
+
+	mac, err := hex.DecodeString(k.Crypto.MAC)
+	if err != nil {
+		return nil, err
+	}
+	iv, err := hex.DecodeString(k.Crypto.CipherParams.IV)
+	if err != nil {
+		return nil, err
+	}
+	cipherText, err := hex.DecodeString(k.Crypto.CipherText)
+	if err != nil {
+		return nil, err
+	}
+	derivedKey, err := getKDFKey(k.Crypto, password)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(keccak256(derivedKey[16:32], cipherText), mac) {
+		return nil, ErrDecrypt
+	}
+
+	plainText, err := aesCTRXOR(derivedKey[:16], cipherText, iv)
+	if err != nil {
+		return nil, err
+	}
+	if privateKeyLength != len(plainText) {
+		return nil, ErrInvalidPrivateKey
+	}
+	key, err := NewKeyFromHex(common.ToHex(plainText))
+	if err != nil {
+		return nil, err
+	}
+	if 0 != len(k.Id) {
+		key.Id = k.Id
+	}
+	return key, nil
+}
+
+func getKDFKey(cryptoJSON cryptoJSON, password string) ([]byte, error) {
+	salt, err := hex.DecodeString(fmt.Sprint(cryptoJSON.KDFParams["salt"]))
+	if err != nil {
+		return nil, err
+	}
+	dkLen := ensureInt(cryptoJSON.KDFParams["dklen"])
+	if dkLen < 32 {
+		return nil, fmt.Errorf("invalid dklen: %d", dkLen)
+	}
+
+	switch cryptoJSON.KDF {
+	case keyHeaderKDF:
+		n := ensureInt(cryptoJSON.KDFParams["n"])
+		r := ensureInt(cryptoJSON.KDFParams["r"])
+		p := ensureInt(cryptoJSON.KDFParams["p"])
+		return scrypt.Key([]byte(password), salt, n, r, p, dkLen)
+	case "pbkdf2":
+		c := ensureInt(cryptoJSON.KDFParams["c"])
+		if prf, _ := cryptoJSON.KDFParams["prf"].(string); "hmac-sha256" != prf {
+			return nil, fmt.Errorf("unsupported PBKDF2 PRF: %s", prf)
+		}
+		return pbkdf2.Key([]byte(password), salt, c, dkLen, sha256.New), nil
+	}
+	return nil, fmt.Errorf("unsupported KDF: %s", cryptoJSON.KDF)
+}
+
+// ensureInt converts the json number, which is decoded as float64
+func ensureInt(x interface{}) int {
+	switch v := x.(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+func aesCTRXOR(key, inText, iv []byte) ([]byte, error) {
+	aesBlock, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	stream := cipher.NewCTR(aesBlock, iv)
+	outText := make([]byte, len(inText))
+	stream.XORKeyStream(outText, inText)
+	return outText, nil
+}
+
+func keccak256(data ...[]byte) []byte {
+	d := sha3.NewKeccak256()
+	for _, b := range data {
+		d.Write(b)
+	}
+	return d.Sum(nil)
+}
@@ -36,19 +36,24 @@ type Sign struct {
 	recid byte
 }
 
+// Signer signs the data hash with the node key, which may be held by a remote signer
+type Signer interface {
+	Sign(hash []byte) Sign
+}
+
 type SignData struct {
 	DataHash Hash
 	DataSign Sign
 	Id       string
 }
 
-func NewSignData(privateKey PrivateKey, id string, hasher Hasher) SignData {
+func NewSignData(signer Signer, id string, hasher Hasher) SignData {
 	result := SignData{}
 
 	hash := hasher.GenHash()
 	result.DataHash = hash
 	result.Id = id
-	result.DataSign = privateKey.Sign(hash.Bytes())
+	result.DataSign = signer.Sign(hash.Bytes())
 	return result
 }
 
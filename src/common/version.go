@@ -21,7 +21,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"math"
 	"math/big"
 	"os"
 	"strconv"
@@ -39,74 +38,86 @@ const (
 
 var (
 	mainNetChainConfig = ChainConfig{
-		ChainId:          "2025",
-		NetworkId:        "2025",
-		PHub:             "wss://mainnet.rangersprotocol.com/phub",
-		OriginalChainId:  "8888",
-		Proposal001Block: 894116,
-		Proposal002Block: 3353000,
-		Proposal003Block: 3830000,
-		Proposal004Block: 5310000,
-		Proposal005Block: 10293600,
-		Proposal006Block: 16733000,
-		Proposal007Block: 16082000,
-		Proposal008Block: 16082000,
-		Proposal009Block: 16733000,
-		Proposal010Block: math.MaxUint64, //mainnet never use proporal010
-		Proposal011Block: 11750354,
-		Proposal012Block: 22815000,
-		Proposal013Block: 28998000,
-		Proposal014Block: 48081000,
-		Proposal015Block: 53015000,
-		Proposal016Block: 54038500,
-		Proposal017Block: 54038500,
-		Proposal018Block: 55959500,
-		Proposal019Block: math.MaxUint64, //mainnet never use proporal010
-		Proposal020Block: 61794000,
-		Proposal021Block: 61202000,
-		Proposal022Block: 62606000,
-		Proposal023Block: 63100000,
-		Proposal024Block: 62575384,
-		Proposal025Block: 63311000,
-		Proposal026Block: 64666400,
-		Proposal027Block: 69329000,
+		ChainId:         "2025",
+		NetworkId:       "2025",
+		PHub:            "wss://mainnet.rangersprotocol.com/phub",
+		OriginalChainId: "8888",
+		Forks: ForkSchedule{
+			Proposal001: 894116,
+			Proposal002: 3353000,
+			Proposal003: 3830000,
+			Proposal004: 5310000,
+			Proposal005: 10293600,
+			Proposal006: 16733000,
+			Proposal007: 16082000,
+			Proposal008: 16082000,
+			Proposal009: 16733000,
+			Proposal010: NeverForked, //mainnet never use proporal010
+			Proposal011: 11750354,
+			Proposal012: 22815000,
+			Proposal013: 28998000,
+			Proposal014: 48081000,
+			Proposal015: 53015000,
+			Proposal016: 54038500,
+			Proposal017: 54038500,
+			Proposal018: 55959500,
+			Proposal019: NeverForked, //mainnet never use proporal010
+			Proposal020: 61794000,
+			Proposal021: 61202000,
+			Proposal022: 62606000,
+			Proposal023: 63100000,
+			Proposal024: 62575384,
+			Proposal025: 63311000,
+			Proposal026: 64666400,
+			Proposal027: 69329000,
+			Proposal028: NeverForked,
+			Proposal029: NeverForked,
+			Proposal030: NeverForked,
+			Proposal031: NeverForked,
+		},
 		mainNodeContract: HexToAddress("0x74448149F549CD819b7173b6D67DbBEAFd2909a7"),
 		MysqlDSN:         "rpservice:!890rpService@#$@tcp(172.16.0.60:6666)/service?charset=utf8&parseTime=true&loc=Asia%2FShanghai",
 		JsonRPCUrl:       "https://gateway.rangersprotocol.com/api/jsonrpc",
 	}
 
 	robinChainConfig = ChainConfig{
-		ChainId:          "9527",
-		NetworkId:        "9527",
-		PHub:             "wss://robin.rangersprotocol.com/phub",
-		OriginalChainId:  "9527",
-		Proposal001Block: 0,
-		Proposal002Block: 2802000,
-		Proposal003Block: 3380000,
-		Proposal004Block: 5310000,
-		Proposal005Block: 10003000,
-		Proposal006Block: 12582000,
-		Proposal007Block: 14261000,
-		Proposal008Block: 16058000,
-		Proposal009Block: 16740000,
-		Proposal010Block: 19632000,
-		Proposal011Block: math.MaxUint64, //robin never use Proposal011
-		Proposal012Block: 23120000,
-		Proposal013Block: 29063000,
-		Proposal014Block: 0,
-		Proposal015Block: 61205000,
-		Proposal016Block: 62320000,
-		Proposal017Block: 62997000,
-		Proposal018Block: 65795000,
-		Proposal019Block: 66114000,
-		Proposal020Block: 75248100,
-		Proposal021Block: 74312000,
-		Proposal022Block: 76005000,
-		Proposal023Block: 77826000,
-		Proposal024Block: 0,
-		Proposal025Block: 77920000,
-		Proposal026Block: 79365500,
-		Proposal027Block: 84150000,
+		ChainId:         "9527",
+		NetworkId:       "9527",
+		PHub:            "wss://robin.rangersprotocol.com/phub",
+		OriginalChainId: "9527",
+		Forks: ForkSchedule{
+			Proposal001: 0,
+			Proposal002: 2802000,
+			Proposal003: 3380000,
+			Proposal004: 5310000,
+			Proposal005: 10003000,
+			Proposal006: 12582000,
+			Proposal007: 14261000,
+			Proposal008: 16058000,
+			Proposal009: 16740000,
+			Proposal010: 19632000,
+			Proposal011: NeverForked, //robin never use Proposal011
+			Proposal012: 23120000,
+			Proposal013: 29063000,
+			Proposal014: 0,
+			Proposal015: 61205000,
+			Proposal016: 62320000,
+			Proposal017: 62997000,
+			Proposal018: 65795000,
+			Proposal019: 66114000,
+			Proposal020: 75248100,
+			Proposal021: 74312000,
+			Proposal022: 76005000,
+			Proposal023: 77826000,
+			Proposal024: 0,
+			Proposal025: 77920000,
+			Proposal026: 79365500,
+			Proposal027: 84150000,
+			Proposal028: NeverForked,
+			Proposal029: NeverForked,
+			Proposal030: NeverForked,
+			Proposal031: NeverForked,
+		},
 		mainNodeContract: HexToAddress("0x3a8467bEcb0B702c5c6343c8A3Ccb11acE0e8816"),
 
 		MysqlDSN:   "rpservice_v2:oJ2*bA0:hB3%@tcp(192.168.0.172:5555)/rpservice_v2?charset=utf8&parseTime=true&loc=Asia%2FShanghai",
@@ -121,33 +132,39 @@ var (
 		OriginalChainId:  "9500",
 		mainNodeContract: HexToAddress("0x27B01A9E699F177634f480Cc2150425009Edc5fD"),
 
-		Proposal001Block: 0,
-		Proposal002Block: 0,
-		Proposal003Block: 0,
-		Proposal004Block: 0,
-		Proposal005Block: 0,
-		Proposal006Block: 0,
-		Proposal007Block: 0,
-		Proposal008Block: 0,
-		Proposal009Block: 0,
-		Proposal010Block: 0,
-		Proposal011Block: 0,
-		Proposal012Block: 0,
-		Proposal013Block: 0,
-		Proposal014Block: 0,
-		Proposal015Block: 0,
-		Proposal016Block: 0,
-		Proposal017Block: 0,
-		Proposal018Block: 0,
-		Proposal019Block: 0,
-		Proposal020Block: 10,
-		Proposal021Block: 0,
-		Proposal022Block: 0,
-		Proposal023Block: 12,
-		Proposal024Block: 0,
-		Proposal025Block: 1000000000,
-		Proposal026Block: 0,
-		Proposal027Block: 0,
+		Forks: ForkSchedule{
+			Proposal001: 0,
+			Proposal002: 0,
+			Proposal003: 0,
+			Proposal004: 0,
+			Proposal005: 0,
+			Proposal006: 0,
+			Proposal007: 0,
+			Proposal008: 0,
+			Proposal009: 0,
+			Proposal010: 0,
+			Proposal011: 0,
+			Proposal012: 0,
+			Proposal013: 0,
+			Proposal014: 0,
+			Proposal015: 0,
+			Proposal016: 0,
+			Proposal017: 0,
+			Proposal018: 0,
+			Proposal019: 0,
+			Proposal020: 10,
+			Proposal021: 0,
+			Proposal022: 0,
+			Proposal023: 12,
+			Proposal024: 0,
+			Proposal025: 1000000000,
+			Proposal026: 0,
+			Proposal027: 0,
+			Proposal028: 0,
+			Proposal029: 0,
+			Proposal030: 0,
+			Proposal031: 0,
+		},
 	}
 
 	subNetChainConfig = ChainConfig{
@@ -157,32 +174,38 @@ var (
 		OriginalChainId:  "9500",
 		mainNodeContract: HexToAddress("0x27B01A9E699F177634f480Cc2150425009Edc5fD"),
 
-		Proposal001Block: 0,
-		Proposal002Block: 0,
-		Proposal003Block: 0,
-		Proposal004Block: 0,
-		Proposal005Block: 0,
-		Proposal006Block: 0,
-		Proposal007Block: 0,
-		Proposal008Block: 0,
-		Proposal009Block: 0,
-		Proposal010Block: 0,
-		Proposal011Block: 0,
-		Proposal012Block: 0,
-		Proposal013Block: 0,
-		Proposal014Block: 0,
-		Proposal015Block: 0,
-		Proposal016Block: 0,
-		Proposal017Block: 0,
-		Proposal018Block: 0,
-		Proposal019Block: 0,
-		Proposal020Block: 0,
-		Proposal021Block: 0,
-		Proposal022Block: 0,
-		Proposal023Block: 0,
-		Proposal024Block: 0,
-		Proposal026Block: 0,
-		Proposal027Block: 0,
+		Forks: ForkSchedule{
+			Proposal001: 0,
+			Proposal002: 0,
+			Proposal003: 0,
+			Proposal004: 0,
+			Proposal005: 0,
+			Proposal006: 0,
+			Proposal007: 0,
+			Proposal008: 0,
+			Proposal009: 0,
+			Proposal010: 0,
+			Proposal011: 0,
+			Proposal012: 0,
+			Proposal013: 0,
+			Proposal014: 0,
+			Proposal015: 0,
+			Proposal016: 0,
+			Proposal017: 0,
+			Proposal018: 0,
+			Proposal019: 0,
+			Proposal020: 0,
+			Proposal021: 0,
+			Proposal022: 0,
+			Proposal023: 0,
+			Proposal024: 0,
+			Proposal026: 0,
+			Proposal027: 0,
+			Proposal028: 0,
+			Proposal029: 0,
+			Proposal030: 0,
+			Proposal031: 0,
+		},
 	}
 
 	LocalChainConfig ChainConfig
@@ -198,34 +221,8 @@ type ChainConfig struct {
 
 	PHub string
 
-	OriginalChainId  string
-	Proposal001Block uint64
-	Proposal002Block uint64
-	Proposal003Block uint64
-	Proposal004Block uint64
-	Proposal005Block uint64
-	Proposal006Block uint64
-	Proposal007Block uint64
-	Proposal008Block uint64
-	Proposal009Block uint64
-	Proposal010Block uint64
-	Proposal011Block uint64
-	Proposal012Block uint64
-	Proposal013Block uint64
-	Proposal014Block uint64
-	Proposal015Block uint64
-	Proposal016Block uint64
-	Proposal017Block uint64
-	Proposal018Block uint64
-	Proposal019Block uint64
-	Proposal020Block uint64
-	Proposal021Block uint64
-	Proposal022Block uint64
-	Proposal023Block uint64
-	Proposal024Block uint64
-	Proposal025Block uint64
-	Proposal026Block uint64
-	Proposal027Block uint64
+	OriginalChainId string
+	Forks           ForkSchedule
 
 	mainNodeContract Address
 
@@ -253,10 +250,17 @@ func initChainConfig(env string) {
 	Genesis = getGenesisConf(filename)
 	if nil == Genesis {
 		fmt.Println("no genesisConf, using default")
-	} else if 0 != len(Genesis.ChainId) {
+		return
+	}
+
+	if 0 != len(Genesis.ChainId) {
 		LocalChainConfig.NetworkId = Genesis.ChainId
 		LocalChainConfig.ChainId = Genesis.ChainId
 	}
+	// a sub chain may take its own fork set, the ones not given keep the defaults
+	if err := LocalChainConfig.Forks.override(Genesis.Forks); err != nil {
+		panic("genesis forks error: " + err.Error())
+	}
 }
 
 // reading genesis info
@@ -300,7 +304,7 @@ func GetChainId(height uint64) *big.Int {
 }
 
 func ChainId(height uint64) string {
-	if IsProposal001(height) {
+	if LocalChainConfig.Rules(height).IsProposal001 {
 		return LocalChainConfig.ChainId
 	} else {
 		return LocalChainConfig.OriginalChainId
@@ -323,92 +327,6 @@ func IsSub() bool {
 	return Genesis != nil
 }
 
-func IsProposal001(height uint64) bool {
-	return isForked(LocalChainConfig.Proposal001Block, height)
-}
-
-func IsProposal002() bool {
-	return isForked(LocalChainConfig.Proposal002Block, GetBlockHeight())
-}
-
-func IsProposal003() bool {
-	return isForked(LocalChainConfig.Proposal003Block, GetBlockHeight())
-}
-
-func IsProposal004() bool {
-	return isForked(LocalChainConfig.Proposal004Block, GetBlockHeight())
-}
-
-func IsProposal005() bool {
-	return isForked(LocalChainConfig.Proposal005Block, GetBlockHeight())
-}
-
-// user nonce
-func IsProposal006() bool {
-	return isForked(LocalChainConfig.Proposal006Block, GetBlockHeight())
-}
-
-func IsProposal007() bool {
-	return isForked(LocalChainConfig.Proposal007Block, GetBlockHeight())
-}
-
-func IsProposal008() bool {
-	return isForked(LocalChainConfig.Proposal008Block, GetBlockHeight())
-}
-
-func IsProposal009() bool {
-	return isForked(LocalChainConfig.Proposal009Block, GetBlockHeight())
-}
-
-func IsProposal012() bool {
-	return isForked(LocalChainConfig.Proposal012Block, GetBlockHeight())
-}
-
-func IsProposal013() bool {
-	return isForked(LocalChainConfig.Proposal013Block, GetBlockHeight())
-}
-
-func IsProposal015() bool {
-	return isForked(LocalChainConfig.Proposal015Block, GetBlockHeight())
-}
-
-func IsProposal016() bool {
-	return isForked(LocalChainConfig.Proposal016Block, GetBlockHeight())
-}
-
-func IsProposal017() bool {
-	return isForked(LocalChainConfig.Proposal017Block, GetBlockHeight())
-}
-
-func IsProposal018() bool {
-	return isForked(LocalChainConfig.Proposal018Block, GetBlockHeight())
-}
-
-func IsProposal020() bool {
-	return isForked(LocalChainConfig.Proposal020Block, GetBlockHeight())
-}
-
-func IsProposal021() bool {
-	return isForked(LocalChainConfig.Proposal021Block, GetBlockHeight())
-}
-
-// sort for transactions
-func IsProposal023() bool {
-	return isForked(LocalChainConfig.Proposal023Block, GetBlockHeight())
-}
-
-func IsProposal026() bool {
-	return isForked(LocalChainConfig.Proposal026Block, GetBlockHeight())
-}
-
-func IsProposal027() bool {
-	return isForked(LocalChainConfig.Proposal027Block, GetBlockHeight())
-}
-
-func isForked(base uint64, height uint64) bool {
-	return height >= base
-}
-
 func MainNodeContract() Address {
 	return LocalChainConfig.mainNodeContract
 }
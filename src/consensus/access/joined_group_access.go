@@ -117,7 +117,7 @@ func (storage *JoinedGroupStorage) BelongGroup(groupId groupsig.ID) bool {
 //	gid : group ID (not dummy id)
 //	sk: user's group member signature private key
 func (storage *JoinedGroupStorage) JoinGroup(joinedGroupInfo *model.JoinedGroupInfo, selfMinerId groupsig.ID) {
-	logger.Infof("(%v):join group,group id=%v,secKey:%v\n", selfMinerId.GetHexString(), joinedGroupInfo.GroupID.ShortS(), joinedGroupInfo.SignSecKey.GetHexString())
+	logger.Infof("(%v):join group,group id=%v\n", selfMinerId.GetHexString(), joinedGroupInfo.GroupID.ShortS())
 	if !storage.BelongGroup(joinedGroupInfo.GroupID) {
 		storage.addJoinedGroupInfo(joinedGroupInfo)
 	}
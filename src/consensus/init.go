@@ -28,7 +28,7 @@ import (
 
 var Proc logical.Processor
 
-func InitConsensus(mi model.SelfMinerInfo, conf common.ConfManager) bool {
+func InitConsensus(mi model.SelfMinerInfo, signer common.Signer, conf common.ConfManager) bool {
 	start := time.Now()
 	common.DefaultLogger.Infof("start InitConsensus")
 	defer func() {
@@ -39,7 +39,7 @@ func InitConsensus(mi model.SelfMinerInfo, conf common.ConfManager) bool {
 	joinedGroupStorage := initJoinedGroupStorage()
 
 	group_create.GroupCreateProcessor.Init(mi, joinedGroupStorage)
-	ret := Proc.Init(mi, conf, joinedGroupStorage)
+	ret := Proc.Init(mi, signer, conf, joinedGroupStorage)
 	net.MessageHandler.Init(&group_create.GroupCreateProcessor, &Proc)
 
 	return ret
@@ -55,10 +55,15 @@ func StartMiner() bool {
 	return Proc.Start()
 }
 
+// StopMiner stops mining, StartMiner resumes it
 func StopMiner() {
 	Proc.Stop()
+}
+
+// CloseMiner stops mining and releases the joined group storage on exit
+func CloseMiner() {
+	Proc.Stop()
 	Proc.Finalize()
-	return
 }
 
 func initJoinedGroupStorage() *access.JoinedGroupStorage {
@@ -0,0 +1,328 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package logical
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/consensus/access"
+	"com.tuntun.rangers/node/src/consensus/groupsig"
+	"com.tuntun.rangers/node/src/consensus/model"
+	"com.tuntun.rangers/node/src/consensus/net"
+	"com.tuntun.rangers/node/src/middleware"
+	"com.tuntun.rangers/node/src/middleware/log"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"com.tuntun.rangers/node/src/service"
+	"com.tuntun.rangers/node/src/utility"
+	"encoding/json"
+	lru "github.com/hashicorp/golang-lru"
+	"sync"
+)
+
+// evidencePool collects the evidences of the members of the groups the miner belongs to,
+// and submits them as transactions since Proposal030
+type evidencePool struct {
+	mi           *model.SelfMinerInfo
+	signer       common.Signer
+	minerReader  *access.MinerPoolReader
+	globalGroups *access.GroupAccessor
+	belongGroups *access.JoinedGroupStorage
+	netServer    net.NetworkServer
+	logger       log.Logger
+
+	lock sync.Mutex
+	// the blocks voted by the miner itself, key: proposal id
+	voted *lru.Cache
+	// the votes of the group members, key: proposal id and member
+	votes *lru.Cache
+	// the members sent pieces for the blocks, key: block hash
+	signers *lru.Cache
+	// the blocks signed by the belonging groups in the current epoch, key: group id
+	epochs map[string]*groupEpoch
+	// the evidences waiting to be executed, in the order found
+	pending []*pendingEvidence
+}
+
+type groupEpoch struct {
+	groupId []byte
+	epoch   uint64
+	blocks  []common.Hash
+}
+
+type pendingEvidence struct {
+	hash     common.Hash
+	evidence *types.Evidence
+	data     string
+	time     string
+}
+
+type voteHasher common.Hash
+
+func (h voteHasher) GenHash() common.Hash {
+	return common.Hash(h)
+}
+
+func newEvidencePool(p *Processor) *evidencePool {
+	return &evidencePool{
+		mi:           p.mi,
+		signer:       p.signer,
+		minerReader:  p.minerReader,
+		globalGroups: p.globalGroups,
+		belongGroups: p.belongGroups,
+		netServer:    p.NetServer,
+		logger:       p.logger,
+		voted:        common.CreateLRUCache(100),
+		votes:        common.CreateLRUCache(1000),
+		signers:      common.CreateLRUCache(10000),
+		epochs:       make(map[string]*groupEpoch),
+		pending:      make([]*pendingEvidence, 0),
+	}
+}
+
+func proposalIdOf(bh *types.BlockHeader) common.Hash {
+	return types.ProposalId(bh.Height, bh.Castor, bh.PreHash, bh.CurTime.UnixNano())
+}
+
+// vote signs the block with the miner key.
+// It refuses to vote for another block of a proposal voted, which is a double sign
+func (pool *evidencePool) vote(bh *types.BlockHeader) ([]byte, bool) {
+	proposalId := proposalIdOf(bh)
+
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	if voted, ok := pool.voted.Get(proposalId); ok && voted.(common.Hash) != bh.Hash {
+		pool.logger.Warnf("refuse to vote, height: %d, hash: %s, voted: %s", bh.Height, bh.Hash.String(), voted.(common.Hash).String())
+		return nil, false
+	}
+	pool.voted.Add(proposalId, bh.Hash)
+
+	signInfo, ok := pool.mi.Sign(voteHasher(types.VoteHash(proposalId, bh.Hash)))
+	if !ok {
+		pool.logger.Errorf("fail to sign vote, height: %d, hash: %s", bh.Height, bh.Hash.String())
+		return nil, true
+	}
+	return signInfo.GetSignature().Serialize(), true
+}
+
+// addVote keeps the vote of the member for the block, and finds the double sign of the member
+func (pool *evidencePool) addVote(bh *types.BlockHeader, member groupsig.ID, sign []byte) {
+	if 0 == len(sign) {
+		return
+	}
+
+	proposalId := proposalIdOf(bh)
+	pubkey, err := pool.minerReader.GetPubkey(member)
+	if nil != err {
+		pool.logger.Errorf("fail to get pubkey, member: %s, error: %s", member.GetHexString(), err.Error())
+		return
+	}
+	if !groupsig.VerifySig(groupsig.ByteToPublicKey(pubkey), types.VoteHash(proposalId, bh.Hash).Bytes(), *groupsig.DeserializeSign(sign)) {
+		pool.logger.Errorf("fail to verify vote, member: %s, height: %d, hash: %s", member.GetHexString(), bh.Height, bh.Hash.String())
+		return
+	}
+	vote := &types.Vote{BlockHash: bh.Hash, Sign: sign}
+
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	key := proposalId.String() + member.GetHexString()
+	existed, ok := pool.votes.Get(key)
+	if !ok {
+		pool.votes.Add(key, vote)
+		return
+	}
+	first := existed.(*types.Vote)
+	if first.BlockHash == bh.Hash {
+		return
+	}
+
+	pool.logger.Warnf("double sign, member: %s, height: %d, hash: %s vs %s", member.GetHexString(), bh.Height, first.BlockHash.String(), bh.Hash.String())
+	evidence := &types.Evidence{Type: types.EvidenceTypeDoubleSign, Member: member.Serialize(),
+		Height: bh.Height, Castor: bh.Castor, PreHash: bh.PreHash, CastTime: bh.CurTime.UnixNano(),
+		First: first, Second: vote, Reporter: pool.mi.ID.Serialize()}
+	pool.addPending(evidence)
+}
+
+// addSigner marks the member sent its piece for the block
+func (pool *evidencePool) addSigner(blockHash common.Hash, member groupsig.ID) {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	var signers map[string]byte
+	if existed, ok := pool.signers.Get(blockHash); ok {
+		signers = existed.(map[string]byte)
+	} else {
+		signers = make(map[string]byte)
+		pool.signers.Add(blockHash, signers)
+	}
+	signers[member.GetHexString()] = 0
+}
+
+// onBlockAdded counts the blocks signed by the belonging groups,
+// and reports the absent members when the epoch is over
+func (pool *evidencePool) onBlockAdded(bh *types.BlockHeader) {
+	epoch := common.GetGroupEpoch(bh.Height)
+
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	for key, item := range pool.epochs {
+		if item.epoch < epoch {
+			pool.reportAbsence(item)
+			delete(pool.epochs, key)
+		}
+	}
+
+	gid := groupsig.DeserializeID(bh.GroupId)
+	if !pool.belongGroups.BelongGroup(gid) {
+		return
+	}
+	// the pieces of the block not seen, such as the blocks synced
+	if _, ok := pool.signers.Get(bh.Hash); !ok {
+		return
+	}
+
+	key := gid.GetHexString()
+	item := pool.epochs[key]
+	if nil == item {
+		item = &groupEpoch{groupId: bh.GroupId, epoch: epoch, blocks: make([]common.Hash, 0)}
+		pool.epochs[key] = item
+	}
+	item.blocks = append(item.blocks, bh.Hash)
+}
+
+func (pool *evidencePool) reportAbsence(item *groupEpoch) {
+	group, err := pool.globalGroups.GetGroupByID(groupsig.DeserializeID(item.groupId))
+	if nil != err {
+		pool.logger.Errorf("fail to get group: %s, epoch: %d", common.ToHex(item.groupId), item.epoch)
+		return
+	}
+
+	signersList := make([]map[string]byte, 0, len(item.blocks))
+	for _, hash := range item.blocks {
+		if signers, ok := pool.signers.Get(hash); ok {
+			signersList = append(signersList, signers.(map[string]byte))
+		}
+	}
+	blocks := uint64(len(signersList))
+	if blocks < common.AbsenceMinBlocks {
+		return
+	}
+
+	for i := 0; i < group.GetMemberCount(); i++ {
+		member := group.GetMemberID(i)
+		if member.IsEqual(pool.mi.ID) {
+			continue
+		}
+
+		missed := uint64(0)
+		for _, signers := range signersList {
+			if _, ok := signers[member.GetHexString()]; !ok {
+				missed++
+			}
+		}
+		if missed*2 <= blocks {
+			continue
+		}
+
+		evidence := &types.Evidence{Type: types.EvidenceTypeAbsence, Member: member.Serialize(),
+			GroupId: item.groupId, Epoch: item.epoch, Blocks: blocks, Missed: missed, Reporter: pool.mi.ID.Serialize()}
+		signInfo, ok := pool.mi.Sign(evidence)
+		if !ok {
+			pool.logger.Errorf("fail to sign absence, member: %s, group: %s, epoch: %d", member.GetHexString(), common.ToHex(item.groupId), item.epoch)
+			continue
+		}
+		evidence.Sign = signInfo.GetSignature().Serialize()
+
+		pool.logger.Warnf("absence, member: %s, group: %s, epoch: %d, blocks: %d, missed: %d", member.GetHexString(), common.ToHex(item.groupId), item.epoch, blocks, missed)
+		pool.addPending(evidence)
+	}
+}
+
+func (pool *evidencePool) addPending(evidence *types.Evidence) {
+	hash := evidence.ReportHash()
+	for _, item := range pool.pending {
+		if item.hash == hash {
+			return
+		}
+	}
+
+	data, _ := json.Marshal(evidence)
+	pool.pending = append(pool.pending, &pendingEvidence{hash: hash, evidence: evidence, data: string(data), time: utility.GetTime().String()})
+}
+
+// submit sends the pending evidences as transactions signed by the account of the miner.
+// The evidences are dropped once they can not be executed any more, such as punished or reported already
+func (pool *evidencePool) submit(height uint64) {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	if 0 == len(pool.pending) {
+		return
+	}
+	accountDB := middleware.AccountDBManagerInstance.GetLatestStateDB()
+	if nil == accountDB {
+		return
+	}
+
+	source := common.HexToAddress(pool.mi.ID.GetHexString())
+	nonce := accountDB.GetNonce(source)
+	pending := make([]*pendingEvidence, 0, len(pool.pending))
+	txs := make([]*types.Transaction, 0)
+	for _, item := range pool.pending {
+		if err := service.EvidenceManagerImpl.Verify(item.evidence, source.GetHexString(), height+1, "", accountDB); nil != err {
+			pool.logger.Infof("drop evidence: %s, %s", item.hash.String(), err.Error())
+			continue
+		}
+		pending = append(pending, item)
+
+		tx := &types.Transaction{Type: types.TransactionTypeEvidence, Source: source.GetHexString(), Data: item.data, Time: item.time,
+			Nonce: nonce, ChainId: common.ChainId(height + 1)}
+		tx.Hash = tx.GenHash()
+		sign := pool.signer.Sign(tx.Hash.Bytes())
+		tx.Sign = &sign
+		nonce++
+		if ok, _ := service.GetTransactionPool().AddTransaction(tx); ok {
+			txs = append(txs, tx)
+		}
+	}
+	pool.pending = pending
+
+	if 0 != len(txs) {
+		pool.logger.Infof("submit evidences, height: %d, size: %d", height, len(txs))
+		pool.netServer.BroadcastTransactions(txs)
+	}
+}
+
+func (pool *evidencePool) pendingEvidences() []*types.Evidence {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	result := make([]*types.Evidence, 0, len(pool.pending))
+	for _, item := range pool.pending {
+		result = append(result, item.evidence)
+	}
+	return result
+}
+
+// PendingEvidences returns the evidences found by the miner but not executed yet
+func (p *Processor) PendingEvidences() []*types.Evidence {
+	if nil == p.evidences {
+		return []*types.Evidence{}
+	}
+	return p.evidences.pendingEvidences()
+}
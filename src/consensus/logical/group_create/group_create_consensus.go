@@ -64,7 +64,8 @@ func (p *groupCreateProcessor) OnMessageGroupInit(msg *model.GroupInitMessage) {
 
 	groupInitContext = p.groupInitContextCache.GetOrNewContext(&groupInitInfo, candidates, &p.minerInfo)
 	if groupInitContext == nil {
-		panic("Processor::OMGI failed, ConfirmGroupFromRaw return nil.")
+		groupCreateLogger.Errorf("Processor::OMGI failed, ConfirmGroupFromRaw return nil. groupHash:%s", groupHash.ShortS())
+		return
 	}
 
 	// join group by group hash
@@ -86,12 +87,12 @@ func (p *groupCreateProcessor) OnMessageGroupInit(msg *model.GroupInitMessage) {
 				sharePieceMessage.ReceiverId.SetHexString(id)
 				sharePieceMessage.Share = piece
 
-				if signInfo, ok := model.NewSignInfo(p.minerInfo.SecKey, p.minerInfo.ID, sharePieceMessage); ok {
+				if signInfo, ok := p.minerInfo.Sign(sharePieceMessage); ok {
 					sharePieceMessage.SignInfo = signInfo
 					groupCreateLogger.Debugf("piece to ID(%v), gHash=%v, share=%v, pub=%v.", sharePieceMessage.ReceiverId.ShortS(), groupHash.ShortS(), sharePieceMessage.Share.Share.ShortS(), sharePieceMessage.Share.Pub.ShortS())
 					p.NetServer.SendKeySharePiece(sharePieceMessage)
 				} else {
-					groupCreateLogger.Errorf("genSign fail, id=%v, sk=%v", p.minerInfo.ID, p.minerInfo.SecKey.ShortS())
+					groupCreateLogger.Errorf("genSign fail, id=%v", p.minerInfo.ID)
 				}
 			} else {
 				groupCreateLogger.Errorf("GenSharePieces data is not Valid.")
@@ -256,7 +257,7 @@ func (p *groupCreateProcessor) handleSharePieceMessage(groupHash common.Hash, sh
 					groupCreateLogger.Debugf("(%V)Send Sign PubKey.Group id:%s", p.minerInfo.ID.ShortS(), joinedGroupInfo.GroupID.ShortS())
 					p.NetServer.SendSignPubKey(signPubKeyMessage)
 				} else {
-					err = fmt.Errorf("genSign fail, id=%v, sk=%v", p.minerInfo.ID.ShortS(), p.minerInfo.SecKey.ShortS())
+					err = fmt.Errorf("genSign fail, id=%v", p.minerInfo.ID.ShortS())
 					return
 				}
 			}
@@ -272,12 +273,12 @@ func (p *groupCreateProcessor) handleSharePieceMessage(groupHash common.Hash, sh
 					MemberMask:      context.generateMemberMask(),
 				}
 
-				if signInfo, ok := model.NewSignInfo(p.minerInfo.SecKey, p.minerInfo.ID, groupInitedMessage); ok {
+				if signInfo, ok := p.minerInfo.Sign(groupInitedMessage); ok {
 					groupInitedMessage.SignInfo = signInfo
 					groupCreateLogger.Debugf("Broadcast group inited message:%v", joinedGroupInfo.GroupID.ShortS())
 					p.NetServer.BroadcastGroupInfo(groupInitedMessage)
 				} else {
-					err = fmt.Errorf("genSign fail, id=%v, sk=%v", p.minerInfo.ID.ShortS(), p.minerInfo.SecKey.ShortS())
+					err = fmt.Errorf("genSign fail, id=%v", p.minerInfo.ID.ShortS())
 					return
 				}
 			}
@@ -81,7 +81,12 @@ func newGroupInitContext(groupInitInfo *model.GroupInitInfo, candidates []groups
 	context.groupInitInfo = groupInitInfo
 	context.candidates = candidates
 
-	context.nodeInfo = NewGroupNodeInfo(minerInfo, groupInitInfo.GroupHash(), len(groupInitInfo.GroupMembers))
+	nodeInfo, err := NewGroupNodeInfo(minerInfo, groupInitInfo.GroupHash(), len(groupInitInfo.GroupMembers))
+	if err != nil {
+		groupCreateLogger.Errorf("NewGroupInitContext gen secret failed! groupHash=%v, err=%v.", groupInitInfo.GroupHash().ShortS(), err)
+		return nil
+	}
+	context.nodeInfo = nodeInfo
 	return context
 }
 
@@ -189,7 +189,7 @@ func (p *groupCreateProcessor) selectCandidates(theBH *types.BlockHeader) (enoug
 			}
 		}
 
-		if common.IsProposal009() {
+		if common.CurrentRules().IsProposal009 {
 			if joinedNum < int(cand.Stake/common.ValidatorStake) && joinedNum < common.MAXGROUP {
 				candidates = append(candidates, cand)
 			}
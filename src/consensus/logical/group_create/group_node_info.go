@@ -36,15 +36,19 @@ type groupNodeInfo struct {
 }
 
 // InitForMiner+InitForGroup
-func NewGroupNodeInfo(mi *model.SelfMinerInfo, groupHash common.Hash, groupMemberNum int) *groupNodeInfo {
+func NewGroupNodeInfo(mi *model.SelfMinerInfo, groupHash common.Hash, groupMemberNum int) (*groupNodeInfo, error) {
 	var nodeInfo = groupNodeInfo{}
-	nodeInfo.secretSeed = mi.GenSecretForGroup(groupHash) // Generate a private seed for the group
+	secretSeed, err := mi.GenSecretForGroup(groupHash) // Generate a private seed for the group
+	if err != nil {
+		return nil, err
+	}
+	nodeInfo.secretSeed = secretSeed
 	nodeInfo.groupMemberNum = groupMemberNum
 	nodeInfo.receivedSharePiece = make(map[string]model.SharePiece)
 
 	nodeInfo.minerSignSeckey = groupsig.Seckey{} // initialization
 	nodeInfo.groupPubKey = groupsig.Pubkey{}
-	return &nodeInfo
+	return &nodeInfo, nil
 }
 
 // GenSharePiece generate secret sharing for all members of the group
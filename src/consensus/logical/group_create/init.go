@@ -204,14 +204,14 @@ func (p *groupCreateProcessor) tryReqSharePiece(gc *groupInitContext) {
 		GroupHash: gc.groupInitInfo.GroupHash(),
 	}
 	groupCreateLogger.Infof("reqSharePieceRoutine:req size %v, ghash=%v", len(waitIds), gc.groupInitInfo.GroupHash().ShortS())
-	if signInfo, ok := model.NewSignInfo(p.minerInfo.SecKey, p.minerInfo.ID, msg); ok {
+	if signInfo, ok := p.minerInfo.Sign(msg); ok {
 		msg.SignInfo = signInfo
 		for _, receiver := range waitIds {
 			groupCreateLogger.Infof("reqSharePieceRoutine:req share piece msg from %v, ghash=%v", receiver, gc.groupInitInfo.GroupHash().ShortS())
 			p.NetServer.ReqSharePiece(msg, receiver)
 		}
 	} else {
-		groupCreateLogger.Infof("gen req sharepiece sign fail, ski=%v %v", p.minerInfo.ID.ShortS(), p.minerInfo.SecKey.ShortS())
+		groupCreateLogger.Infof("gen req sharepiece sign fail, ski=%v", p.minerInfo.ID.ShortS())
 	}
 
 }
@@ -52,7 +52,7 @@ func (p *groupCreateProcessor) OnMessageSharePieceReq(msg *model.ReqSharePieceMe
 		GroupHash: msg.GroupHash,
 		Share:     piece,
 	}
-	if signInfo, ok := model.NewSignInfo(p.minerInfo.SecKey, p.minerInfo.ID, pieceMsg); ok {
+	if signInfo, ok := p.minerInfo.Sign(pieceMsg); ok {
 		pieceMsg.SignInfo = signInfo
 		groupCreateLogger.Debugf("response share piece to %v, gHash=%v, share=%v", msg.SignInfo.GetSignerID().ShortS(), msg.GroupHash.ShortS(), piece.Share.ShortS())
 		p.NetServer.ResponseSharePiece(pieceMsg, msg.SignInfo.GetSignerID())
@@ -72,7 +72,7 @@ func (p *groupCreateProcessor) askSignPK(minerId groupsig.ID, groupId groupsig.I
 	msg := &model.SignPubkeyReqMessage{
 		GroupID: groupId,
 	}
-	if signInfo, ok := model.NewSignInfo(p.minerInfo.SecKey, p.minerInfo.ID, msg); ok {
+	if signInfo, ok := p.minerInfo.Sign(msg); ok {
 		msg.SignInfo = signInfo
 		groupCreateLogger.Debugf("ask sign pk message, receiver %v, gid %v", minerId, groupId)
 		p.NetServer.AskSignPkMessage(msg, minerId)
@@ -105,7 +105,7 @@ func (p *groupCreateProcessor) OnMessageSignPKReq(msg *model.SignPubkeyReqMessag
 		return
 	}
 	if !joinedGroupInfo.SignSecKey.IsValid() {
-		err = fmt.Errorf("invalid sign secKey, id=%v, sk=%v", p.minerInfo.ID.ShortS(), joinedGroupInfo.SignSecKey.ShortS())
+		err = fmt.Errorf("invalid sign secKey, id=%v", p.minerInfo.ID.ShortS())
 		return
 	}
 
@@ -114,12 +114,12 @@ func (p *groupCreateProcessor) OnMessageSignPKReq(msg *model.SignPubkeyReqMessag
 		GroupID:   msg.GroupID,
 		SignPK:    *groupsig.GeneratePubkey(joinedGroupInfo.SignSecKey),
 	}
-	if signInfo, ok := model.NewSignInfo(p.minerInfo.SecKey, p.minerInfo.ID, msg); ok {
+	if signInfo, ok := p.minerInfo.Sign(msg); ok {
 		resp.SignInfo = signInfo
 		groupCreateLogger.Debugf("answer signPKReq Message, receiver %v, groupId:%v,groupHash:%v,signPK:%s,msg hash:%s", sender.ShortS(), msg.GroupID.GetHexString())
 		p.NetServer.AnswerSignPkMessage(resp, sender)
 	} else {
-		err = fmt.Errorf("gen Sign fail, ski=%v,%v", p.minerInfo.ID.ShortS(), p.minerInfo.SecKey.GetHexString())
+		err = fmt.Errorf("gen Sign fail, ski=%v", p.minerInfo.ID.ShortS())
 	}
 }
 
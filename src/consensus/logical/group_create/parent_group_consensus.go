@@ -37,7 +37,7 @@ func (p *groupCreateProcessor) pingNodes() {
 		PingID:      ctx.pingID,
 		BaseHeight:  ctx.baseBlockHeader.Height,
 	}
-	if signInfo, ok := model.NewSignInfo(p.minerInfo.SecKey, p.minerInfo.ID, msg); ok {
+	if signInfo, ok := p.minerInfo.Sign(msg); ok {
 		msg.SignInfo = signInfo
 		for _, id := range ctx.candidates {
 			groupCreateLogger.Debugf("Send ping to id=%v,baseHeight=%v, pingID=%v, ", id.ShortS(), ctx.baseBlockHeader.Height, msg.PingID)
@@ -73,7 +73,7 @@ func (p *groupCreateProcessor) OnMessageCreateGroupPing(msg *model.CreateGroupPi
 			Timestamp: utility.GetTime(),
 		}
 
-		if signInfo, ok := model.NewSignInfo(p.minerInfo.SecKey, p.minerInfo.ID, pongMsg); ok {
+		if signInfo, ok := p.minerInfo.Sign(pongMsg); ok {
 			pongMsg.SignInfo = signInfo
 			var belongGroup = false
 			if p.joinedGroupStorage.BelongGroup(msg.FromGroupID) {
@@ -166,7 +166,7 @@ func (p *groupCreateProcessor) tryStartParentConsensus(topHeight uint64) bool {
 	}
 	inGroupSignSecKey := p.getInGroupSignSecKey(gInfo.ParentGroupID())
 	if signInfo, ok := model.NewSignInfo(inGroupSignSecKey, p.minerInfo.ID, msg); !ok {
-		desc = fmt.Sprintf("genSign fail, id=%v, sk=%v", p.minerInfo.ID.ShortS(), p.minerInfo.SecKey.ShortS())
+		desc = fmt.Sprintf("genSign fail, id=%v", p.minerInfo.ID.ShortS())
 		return false
 	} else {
 		msg.SignInfo = signInfo
@@ -225,7 +225,7 @@ func (p *groupCreateProcessor) OnMessageParentGroupConsensus(msg *model.ParentGr
 			p.NetServer.SendCreateGroupSignMessage(signMsg, parentGid)
 			groupCreateLogger.Debugf("Send create group sign to: sender=%v,groupHash=%v", msg.SignInfo.GetSignerID().ShortS(), gh.Hash.ShortS())
 		} else {
-			groupCreateLogger.Errorf("ParentGroupConsensusSignMessage sign fail, signer id=%v,seckey=%v", p.minerInfo.ID.ShortS(), p.minerInfo.SecKey.ShortS())
+			groupCreateLogger.Errorf("ParentGroupConsensusSignMessage sign fail, signer id=%v", p.minerInfo.ID.ShortS())
 		}
 	} else {
 		groupCreateLogger.Errorf("validate create group info failed , err:%v", err.Error())
@@ -295,7 +295,7 @@ func (p *groupCreateProcessor) OnMessageParentGroupConsensusSign(msg *model.Pare
 			GroupInitInfo: *ctx.groupInitInfo,
 		}
 
-		if signInfo, ok := model.NewSignInfo(p.minerInfo.SecKey, p.minerInfo.ID, initMsg); ok && ctx.getStatus() != sendInit {
+		if signInfo, ok := p.minerInfo.Sign(initMsg); ok && ctx.getStatus() != sendInit {
 			initMsg.SignInfo = signInfo
 			p.NetServer.SendGroupInitMessage(initMsg)
 			ctx.setStatus(sendInit)
@@ -305,7 +305,7 @@ func (p *groupCreateProcessor) OnMessageParentGroupConsensusSign(msg *model.Pare
 				groupCreateLogger.Debugf(id.GetHexString())
 			}
 		} else {
-			groupCreateLogger.Errorf("GroupInitMessage sign failed, signer id=%v,seckey=%v", p.minerInfo.ID.ShortS(), p.minerInfo.SecKey.ShortS())
+			groupCreateLogger.Errorf("GroupInitMessage sign failed, signer id=%v", p.minerInfo.ID.ShortS())
 		}
 	} else {
 		groupCreateLogger.Errorf("recover parent group sig failed, err=%v", err)
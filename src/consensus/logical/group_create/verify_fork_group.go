@@ -152,7 +152,7 @@ func (p *groupCreateProcessor) selectCandidatesForForkGroup(theBH *types.BlockHe
 			}
 		}
 
-		if common.IsProposal009() {
+		if common.CurrentRules().IsProposal009 {
 			if joinedNum < int(cand.Stake/common.ValidatorStake) && joinedNum < common.MAXGROUP {
 				candidates = append(candidates, cand)
 			}
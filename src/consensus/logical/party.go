@@ -8,12 +8,17 @@ import (
 	"com.tuntun.rangers/node/src/consensus/net"
 	"com.tuntun.rangers/node/src/core"
 	"com.tuntun.rangers/node/src/middleware/log"
+	"com.tuntun.rangers/node/src/middleware/metrics"
 	"errors"
 	"fmt"
 	"runtime/debug"
+	"strconv"
 	"sync"
+	"time"
 )
 
+var roundTimer = metrics.NewHistogramVec("consensus_round_seconds", "Time of the sign party rounds", metrics.DefBuckets, "round")
+
 type Party interface {
 	Start() *Error
 	Close()
@@ -44,6 +49,8 @@ type baseParty struct {
 
 	mtx sync.Mutex
 	rnd Round
+	// start time of the current round
+	rndStart time.Time
 
 	logger         log.Logger
 	futureMessages map[string]model.ConsensusMessage
@@ -69,6 +76,7 @@ func (p *baseParty) setRound(round Round) *Error {
 		return NewError(errors.New("a round is already set on this party"), "setRound", p.rnd.RoundNumber(), "", nil)
 	}
 	p.rnd = round
+	p.rndStart = time.Now()
 	return nil
 }
 
@@ -77,7 +85,9 @@ func (p *baseParty) round() Round {
 }
 
 func (p *baseParty) advance() {
+	roundTimer.WithLabelValues(strconv.Itoa(p.rnd.RoundNumber())).UpdateSince(p.rndStart)
 	p.rnd = p.rnd.NextRound()
+	p.rndStart = time.Now()
 }
 
 func (p *baseParty) lock() {
@@ -159,6 +169,7 @@ type SignParty struct {
 	ChangedId    chan string
 	mi           groupsig.ID
 	netServer    net.NetworkServer
+	evidences    *evidencePool
 }
 
 func (p *SignParty) Start() *Error {
@@ -182,5 +193,5 @@ func (p *SignParty) FirstRound() Round {
 	return &round0{baseRound: &baseRound{partyId: p.id, futureMessages: p.futureMessages, errChan: p.Err, done: p.Done, logger: p.logger},
 		belongGroups: p.belongGroups, blockchain: p.blockchain,
 		minerReader: p.minerReader, globalGroups: p.globalGroups,
-		changedId: p.ChangedId, mi: p.mi, netServer: p.netServer}
+		changedId: p.ChangedId, mi: p.mi, netServer: p.netServer, evidences: p.evidences}
 }
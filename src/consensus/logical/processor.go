@@ -40,6 +40,11 @@ type Processor struct {
 	ready bool
 	conf  common.ConfManager
 	mi    *model.SelfMinerInfo
+	// signs the transactions sent by the miner with the node key
+	signer common.Signer
+
+	// the routines are registered once, a stopped miner restarts by resuming them
+	started bool
 
 	belongGroups *access.JoinedGroupStorage
 	globalGroups *access.GroupAccessor
@@ -60,13 +65,15 @@ type Processor struct {
 	partyManager                  map[string]Party
 	logger                        log.Logger
 	finishedParty, futureMessages *lru.Cache
+
+	evidences *evidencePool
 }
 
 func (p *Processor) getPrefix() string {
 	return p.GetMinerID().ShortS()
 }
 
-func (p *Processor) Init(mi model.SelfMinerInfo, conf common.ConfManager, joinedGroupStorage *access.JoinedGroupStorage) bool {
+func (p *Processor) Init(mi model.SelfMinerInfo, signer common.Signer, conf common.ConfManager, joinedGroupStorage *access.JoinedGroupStorage) bool {
 	p.ready = false
 	p.lock = sync.Mutex{}
 
@@ -81,11 +88,13 @@ func (p *Processor) Init(mi model.SelfMinerInfo, conf common.ConfManager, joined
 	p.MainChain = core.GetBlockChain()
 	p.GroupChain = core.GetGroupChain()
 	p.mi = &mi
+	p.signer = signer
 	p.globalGroups = access.NewGroupAccessor(p.GroupChain)
 	p.belongGroups = joinedGroupStorage
 	p.NetServer = net.NewNetworkServer()
 
 	p.minerReader = access.NewMinerPoolReader()
+	p.evidences = newEvidencePool(p)
 
 	p.Ticker = ticker.GetTickerInstance()
 
@@ -154,14 +154,12 @@ func (p *Processor) blockProposal() {
 	tlog.logStart("height=%v,qn=%v, preHash=%v, verifyGroup=%v", bh.Height, qn, bh.PreHash.ShortS(), gid.ShortS())
 
 	if bh.Height > 0 && bh.Height == height && bh.PreHash == worker.baseBH.Hash {
-		skey := p.mi.SecKey
-
 		var ccm model.ConsensusCastMessage
 		ccm.BH = bh
 		ccm.ProveHash = []common.Hash{}
 
-		if signInfo, ok := model.NewSignInfo(p.mi.SecKey, p.mi.ID, &ccm); !ok {
-			blog.log("sign fail, id=%v, sk=%v", p.GetMinerID().ShortS(), skey.ShortS())
+		if signInfo, ok := p.mi.Sign(&ccm); !ok {
+			blog.log("sign fail, id=%v", p.GetMinerID().ShortS())
 			return
 		} else {
 			ccm.SignInfo = signInfo
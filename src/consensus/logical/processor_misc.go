@@ -27,6 +27,11 @@ import (
 )
 
 func (p *Processor) Start() bool {
+	if p.started {
+		p.ready = true
+		p.triggerCastCheck()
+		return true
+	}
 	p.prepareMiner()
 
 	p.Ticker.RegisterRoutine(p.getCastCheckRoutineName(), p.checkSelfCastRoutine, common.CastingCheckInterval)
@@ -39,12 +44,17 @@ func (p *Processor) Start() bool {
 
 	p.triggerCastCheck()
 
+	p.started = true
 	p.ready = true
 	return true
 }
 
+// Stop stops casting and handling the consensus messages, the joined groups are kept for Start
 func (p *Processor) Stop() {
-	return
+	p.ready = false
+	if nil != p.Ticker {
+		p.Ticker.StopTickerRoutine(p.getCastCheckRoutineName())
+	}
 }
 
 func (p *Processor) prepareMiner() {
@@ -17,6 +17,7 @@
 package logical
 
 import (
+	"com.tuntun.rangers/node/src/common"
 	"com.tuntun.rangers/node/src/consensus/groupsig"
 	"com.tuntun.rangers/node/src/consensus/logical/group_create"
 	"com.tuntun.rangers/node/src/consensus/model"
@@ -43,6 +44,11 @@ func (p *Processor) onBlockAddSuccess(message notify.Message) {
 
 	group_create.GroupCreateProcessor.StartCreateGroupPolling()
 
+	if common.CurrentRules().IsProposal030 {
+		p.evidences.onBlockAdded(bh)
+		p.evidences.submit(bh.Height)
+	}
+
 	middleware.PerfLogger.Infof("OnBlockAddSuccess. cost: %v, Hash: %v, height: %v", utility.GetTime().Sub(bh.CurTime), bh.Hash.String(), bh.Height)
 	if p.isTriggerCastImmediately() {
 		p.triggerCastCheck()
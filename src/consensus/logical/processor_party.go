@@ -19,12 +19,18 @@ package logical
 import (
 	"com.tuntun.rangers/node/src/common"
 	"com.tuntun.rangers/node/src/consensus/model"
+	"com.tuntun.rangers/node/src/middleware/metrics"
 	"com.tuntun.rangers/node/src/middleware/types"
 	"com.tuntun.rangers/node/src/utility"
 	"sync"
 	"time"
 )
 
+var (
+	partyCounter = metrics.NewCounterVec("consensus_sign_party_total", "Outcomes of the sign parties, done, timeout or err", "result")
+	partyTimer   = metrics.NewHistogram("consensus_sign_party_seconds", "Time of the sign parties", metrics.DefBuckets)
+)
+
 func (p *Processor) OnMessageCast(ccm *model.ConsensusCastMessage) {
 	key := p.generatePartyKey(ccm.BH)
 	party := p.loadOrNewSignParty(key, ccm, true)
@@ -36,6 +42,11 @@ func (p *Processor) OnMessageCast(ccm *model.ConsensusCastMessage) {
 }
 
 func (p *Processor) OnMessageVerify(cvm *model.ConsensusVerifyMessage) {
+	// the party may be done before the pieces of all the members arrive
+	if common.CurrentRules().IsProposal030 {
+		p.evidences.addSigner(cvm.BlockHash, cvm.SignInfo.GetSignerID())
+	}
+
 	party := p.loadOrNewSignParty(cvm.BlockHash.Bytes(), cvm, false)
 	if nil == party {
 		return
@@ -76,7 +87,7 @@ func (p *Processor) loadOrNewSignParty(keyBytes []byte, msg model.ConsensusMessa
 
 	party := &SignParty{belongGroups: p.belongGroups, blockchain: p.MainChain,
 		minerReader: p.minerReader, globalGroups: p.globalGroups,
-		mi: p.mi.ID, netServer: p.NetServer,
+		mi: p.mi.ID, netServer: p.NetServer, evidences: p.evidences,
 		baseParty: baseParty{
 			logger:         p.logger,
 			mtx:            sync.Mutex{},
@@ -111,6 +122,9 @@ func (p *Processor) waitUntilDone(party *SignParty) {
 		party.Close()
 		delete(p.partyManager, party.id)
 		p.finishedParty.Add(party.id, 0)
+
+		partyCounter.WithLabelValues(endType).Inc()
+		partyTimer.UpdateSince(startTime)
 	}
 
 	key := party.id
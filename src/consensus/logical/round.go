@@ -97,6 +97,8 @@ type (
 		minerReader *access.MinerPoolReader
 		blockchain  core.BlockChain
 		netServer   net.NetworkServer
+		// votes with the miner key and finds the evidences
+		evidences *evidencePool
 
 		changedId chan string
 
@@ -216,13 +216,21 @@ func (r *round0) normalPieceVerify() {
 	cvm.BlockHash = r.bh.Hash
 	skey := r.getSignKey(gid)
 
+	if common.CurrentRules().IsProposal030 {
+		vote, ok := r.evidences.vote(r.bh)
+		if !ok {
+			return
+		}
+		cvm.Vote = vote
+	}
+
 	if signInfo, ok := model.NewSignInfo(skey, r.mi, &cvm); ok {
 		cvm.SignInfo = signInfo
 		r.logger.Debugf("round0 sendVerifiedCast, hash: %s, group: %s, sign: %s", cvm.BlockHash.String(), gid.GetHexString(), cvm.SignInfo.GetSignature().GetHexString())
 		cvm.GenRandomSign(skey, r.preBH.Random)
 		r.netServer.SendVerifiedCast(&cvm, gid)
 	} else {
-		r.logger.Errorf("genSign fail, sk=%v %v", skey.ShortS(), r.belongGroups.BelongGroup(gid))
+		r.logger.Errorf("genSign fail, gid=%v %v", gid.ShortS(), r.belongGroups.BelongGroup(gid))
 	}
 }
 
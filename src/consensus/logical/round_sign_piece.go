@@ -101,6 +101,10 @@ func (r *round1) Update(msg model.ConsensusMessage) *Error {
 		return nil
 	}
 
+	if common.CurrentRules().IsProposal030 {
+		r.evidences.addVote(bh, si.GetSignerID(), cvm.Vote)
+	}
+
 	add, generate := r.gSignGenerator.AddWitnessSign(si.GetSignerID(), si.GetSignature())
 	if !add {
 		r.logger.Warnf("already had the piece, from: %s, hash: %s, height: %d", si.GetSignerID().GetHexString(), cvm.BlockHash.String(), bh.Height)
@@ -79,7 +79,7 @@ func validateProve(prove vrf.VRFProve, height, workingMiners, totalStake uint64)
 	vrfValueRatio := calcVrfValueRatio(prove)
 
 	difficulty := uint64(1)
-	if 0 != workingMiners && height > common.LocalChainConfig.Proposal025Block+common.GetRewardBlocks() {
+	if 0 != workingMiners && height > common.LocalChainConfig.Forks[common.Proposal025]+common.GetRewardBlocks() {
 		difficulty = totalStake / workingMiners
 		stdLogger.Infof("change difficulty, %d, %d, %d", totalStake, workingMiners, difficulty)
 	} else {
@@ -298,7 +298,9 @@ func (msg *ConsensusCastMessage) VerifyRandomSign(pkey groupsig.Pubkey, preRando
 type ConsensusVerifyMessage struct {
 	BlockHash  common.Hash
 	RandomSign groupsig.Signature
-	Id         string
+	// the signature of the block hash with the miner key, kept as the evidence of double sign
+	Vote []byte
+	Id   string
 	SignInfo
 }
 
@@ -21,6 +21,7 @@ import (
 	"com.tuntun.rangers/node/src/consensus/base"
 	"com.tuntun.rangers/node/src/consensus/groupsig"
 	"com.tuntun.rangers/node/src/consensus/vrf"
+	"errors"
 )
 
 const minerStake = 1
@@ -46,9 +47,19 @@ type SelfMinerInfo struct {
 	SecKey     groupsig.Seckey
 	VrfSK      vrf.VRFPrivateKey
 
+	// signer holds the secret keys out of the process, the secrets above are empty if it is set
+	signer MinerSigner
+
 	MinerInfo
 }
 
+// MinerSigner signs with the miner secret keys on behalf of the node
+type MinerSigner interface {
+	SignBLS(hash common.Hash) (groupsig.Signature, error)
+	ProveVRF(msg []byte) (vrf.VRFProve, error)
+	GenSecretForGroup(hash common.Hash) (base.Rand, error)
+}
+
 func NewSelfMinerInfo(privateKey common.PrivateKey) SelfMinerInfo {
 	var mi SelfMinerInfo
 	mi.SecretSeed = base.RandFromBytes(privateKey.PrivKey.D.Bytes())
@@ -66,9 +77,51 @@ func NewSelfMinerInfo(privateKey common.PrivateKey) SelfMinerInfo {
 	return mi
 }
 
-func (mi SelfMinerInfo) GenSecretForGroup(h common.Hash) base.Rand {
+// NewRemoteSelfMinerInfo creates the miner info whose secret keys are held by the signer
+func NewRemoteSelfMinerInfo(id groupsig.ID, pubKey groupsig.Pubkey, vrfPK vrf.VRFPublicKey, signer MinerSigner) SelfMinerInfo {
+	var mi SelfMinerInfo
+	mi.ID = id
+	mi.PubKey = pubKey
+	mi.VrfPK = vrfPK
+	mi.Stake = minerStake
+	mi.signer = signer
+	return mi
+}
+
+func (mi SelfMinerInfo) GenSecretForGroup(h common.Hash) (base.Rand, error) {
+	if nil != mi.signer {
+		return mi.signer.GenSecretForGroup(h)
+	}
 	r := base.RandFromBytes(h.Bytes())
-	return mi.SecretSeed.DerivedRand(r[:])
+	return mi.SecretSeed.DerivedRand(r[:]), nil
+}
+
+// Sign signs the message with the miner key
+func (mi SelfMinerInfo) Sign(hasher common.Hasher) (SignInfo, bool) {
+	if nil == mi.signer {
+		return NewSignInfo(mi.SecKey, mi.ID, hasher)
+	}
+	if !mi.ID.IsValid() {
+		return SignInfo{}, false
+	}
+
+	hash := hasher.GenHash()
+	signature, err := mi.signer.SignBLS(hash)
+	if err != nil || !signature.IsValid() {
+		return SignInfo{}, false
+	}
+	return MakeSignInfo(hash, signature, mi.ID, common.ConsensusVersion), true
+}
+
+// GenVRFProve generates the vrf prove of the message with the miner vrf key
+func (mi SelfMinerInfo) GenVRFProve(msg []byte) (vrf.VRFProve, error) {
+	if nil != mi.signer {
+		return mi.signer.ProveVRF(msg)
+	}
+	if 0 == len(mi.VrfSK) {
+		return nil, errors.New("no vrf key")
+	}
+	return vrf.VRFGenProve(mi.VrfPK, mi.VrfSK, msg)
 }
 
 func (mi MinerInfo) GetMinerID() groupsig.ID {
@@ -201,6 +201,7 @@ func UnMarshalConsensusVerifyMessage(b []byte) (*model.ConsensusVerifyMessage, e
 		BlockHash:  common.BytesToHash(m.BlockHash),
 		RandomSign: *groupsig.DeserializeSign(m.RandomSign),
 		SignInfo:   *baseMessage(m.Sign),
+		Vote:       m.Vote,
 		Id:         common.ToHex(common.Sha256(b)),
 	}, nil
 }
@@ -118,6 +118,7 @@ func marshalConsensusVerifyMessage(m *model.ConsensusVerifyMessage) ([]byte, err
 		BlockHash:  m.BlockHash.Bytes(),
 		RandomSign: m.RandomSign.Serialize(),
 		Sign:       signDataToPb(&m.SignInfo),
+		Vote:       m.Vote,
 	}
 	return proto.Marshal(message)
 }
@@ -19,6 +19,7 @@ package net
 import (
 	"com.tuntun.rangers/node/src/consensus/groupsig"
 	"com.tuntun.rangers/node/src/consensus/model"
+	"com.tuntun.rangers/node/src/middleware/types"
 )
 
 type GroupCreateMessageProcessor interface {
@@ -81,6 +82,8 @@ type NetworkServer interface {
 
 	BroadcastNewBlock(cbm *model.ConsensusBlockMessage)
 
+	BroadcastTransactions(txs []*types.Transaction)
+
 	JoinGroupNet(groupId string)
 
 	ReleaseGroupNet(groupIdentifier string)
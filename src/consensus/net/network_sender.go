@@ -196,6 +196,19 @@ func (ns *NetworkServerImpl) BroadcastNewBlock(cbm *model.ConsensusBlockMessage)
 	//	utility.GetTime().UnixNano(), "", "", common.InstanceIndex, cbm.Block.Header.CurTime.UnixNano())
 }
 
+// BroadcastTransactions spreads the txs made by consensus, such as evidences, to all the proposers
+func (ns *NetworkServerImpl) BroadcastTransactions(txs []*types.Transaction) {
+	body, e := types.MarshalTransactions(txs)
+	if e != nil {
+		logger.Errorf("[peer]Discard send transactions because of marshal error:%s", e.Error())
+		return
+	}
+	m := network.Message{Code: network.TransactionGotMsg, Body: body}
+
+	go ns.net.Broadcast(m)
+	logger.Debugf("[peer]broadcast transactions, size: %d", len(txs))
+}
+
 func (ns *NetworkServerImpl) AskSignPkMessage(msg *model.SignPubkeyReqMessage, receiver groupsig.ID) {
 	body, e := marshalConsensusSignPubKeyReqMessage(msg)
 	if e != nil {
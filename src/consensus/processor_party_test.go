@@ -73,7 +73,7 @@ func preTest() {
 	vm.InitVM()
 	core.InitCore(NewConsensusHelper(minerInfo.ID), *sk, minerInfo.ID.GetHexString())
 
-	InitConsensus(minerInfo, common.GlobalConf)
+	InitConsensus(minerInfo, *sk, common.GlobalConf)
 	group_create.GroupCreateProcessor.BeginGenesisGroupMember()
 	Proc.Start()
 }
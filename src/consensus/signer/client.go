@@ -0,0 +1,191 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package signer
+
+import (
+	"bytes"
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/consensus/base"
+	"com.tuntun.rangers/node/src/consensus/groupsig"
+	"com.tuntun.rangers/node/src/consensus/model"
+	"com.tuntun.rangers/node/src/consensus/vrf"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+const requestTimeout = 5 * time.Second
+
+// Client asks the remote signer for the signatures,
+// it implements common.Signer for the node key and model.MinerSigner for the miner keys
+type Client struct {
+	client  *http.Client
+	baseURL string
+
+	account   Account
+	publicKey *common.PublicKey
+}
+
+// Dial connects to the signer at the local endpoint and loads the public info of its keys
+func Dial(endpoint string) (*Client, error) {
+	path, err := parseEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{Timeout: requestTimeout}
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", path)
+		},
+	}
+	c := &Client{client: &http.Client{Transport: transport, Timeout: requestTimeout}, baseURL: "http://signer"}
+
+	if err := c.loadAccount(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Client) loadAccount() error {
+	resp, err := c.client.Get(c.baseURL + pathAccount)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if http.StatusOK != resp.StatusCode {
+		return fmt.Errorf("signer account status: %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&c.account); err != nil {
+		return err
+	}
+
+	c.publicKey = common.HexStringToPubKey(c.account.PublicKey)
+	if nil == c.publicKey {
+		return errors.New("invalid signer public key")
+	}
+	if c.publicKey.GetAddress().GetHexString() != c.account.Address {
+		return errors.New("signer address mismatches the public key")
+	}
+	return nil
+}
+
+// Account returns the public info of the keys held by the signer
+func (c *Client) Account() Account {
+	return c.account
+}
+
+// MinerInfo returns the miner info whose secret keys are held by the signer
+func (c *Client) MinerInfo() (model.SelfMinerInfo, error) {
+	var (
+		id     groupsig.ID
+		pubKey groupsig.Pubkey
+	)
+	if err := id.SetHexString(c.account.MinerId); err != nil {
+		return model.SelfMinerInfo{}, err
+	}
+	idBytes := c.publicKey.GetID()
+	if !id.IsEqual(groupsig.DeserializeID(idBytes[:])) {
+		return model.SelfMinerInfo{}, errors.New("signer miner id mismatches the public key")
+	}
+	if err := pubKey.SetHexString(c.account.BLSPubKey); err != nil {
+		return model.SelfMinerInfo{}, err
+	}
+	vrfPK := vrf.Hex2VRFPublicKey(c.account.VRFPubKey)
+	if 0 == len(vrfPK) {
+		return model.SelfMinerInfo{}, errors.New("invalid signer vrf public key")
+	}
+	return model.NewRemoteSelfMinerInfo(id, pubKey, vrfPK, c), nil
+}
+
+// Sign signs the hash with the node key. Callers can not handle the error,
+// so it is logged and the empty signature fails the verification of the peers.
+func (c *Client) Sign(hash []byte) common.Sign {
+	result, err := c.call(pathSignECDSA, hash)
+	if err == nil {
+		sign := common.BytesToSign(result)
+		if nil != sign && c.publicKey.Verify(hash, sign) {
+			return *sign
+		}
+		err = errors.New("invalid signature")
+	}
+	if nil != common.DefaultLogger {
+		common.DefaultLogger.Errorf("remote signer sign failed: %s", err)
+	}
+	return common.Sign{}
+}
+
+func (c *Client) SignBLS(hash common.Hash) (groupsig.Signature, error) {
+	var signature groupsig.Signature
+	result, err := c.call(pathSignBLS, hash.Bytes())
+	if err != nil {
+		return signature, err
+	}
+	if err := signature.Deserialize(result); err != nil {
+		return signature, err
+	}
+	return signature, nil
+}
+
+func (c *Client) ProveVRF(msg []byte) (vrf.VRFProve, error) {
+	result, err := c.call(pathProveVRF, msg)
+	if err != nil {
+		return nil, err
+	}
+	return vrf.VRFProve(result), nil
+}
+
+func (c *Client) GenSecretForGroup(hash common.Hash) (base.Rand, error) {
+	var secret base.Rand
+	result, err := c.call(pathGroupSecret, hash.Bytes())
+	if err != nil {
+		return secret, err
+	}
+	if base.RandLength != len(result) {
+		return secret, errors.New("invalid group secret length")
+	}
+	copy(secret[:], result)
+	return secret, nil
+}
+
+func (c *Client) call(path string, data []byte) ([]byte, error) {
+	body, err := json.Marshal(request{Data: common.ToHex(data)})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Post(c.baseURL+path, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var res response
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	if 0 != len(res.Error) {
+		return nil, errors.New(res.Error)
+	}
+	if http.StatusOK != resp.StatusCode {
+		return nil, fmt.Errorf("signer status: %s", resp.Status)
+	}
+	return common.FromHex(res.Result), nil
+}
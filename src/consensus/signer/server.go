@@ -0,0 +1,162 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package signer
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/consensus/groupsig"
+	"com.tuntun.rangers/node/src/consensus/model"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"syscall"
+)
+
+// the umask is of the process, it is changed by one listener at a time
+var umaskLock sync.Mutex
+
+// Server signs the requests of the node with the keys in its own process.
+// It never returns nor logs the secret keys.
+type Server struct {
+	privateKey common.PrivateKey
+	minerInfo  model.SelfMinerInfo
+	account    Account
+
+	mux *http.ServeMux
+}
+
+func NewServer(privateKey common.PrivateKey) *Server {
+	server := &Server{privateKey: privateKey, minerInfo: model.NewSelfMinerInfo(privateKey)}
+	publicKey := privateKey.GetPubKey()
+	server.account = Account{
+		Address:   publicKey.GetAddress().GetHexString(),
+		PublicKey: publicKey.GetHexString(),
+		MinerId:   server.minerInfo.ID.GetHexString(),
+		BLSPubKey: server.minerInfo.PubKey.GetHexString(),
+		VRFPubKey: server.minerInfo.VrfPK.GetHexString(),
+	}
+
+	server.mux = http.NewServeMux()
+	server.mux.HandleFunc(pathAccount, server.handleAccount)
+	server.mux.HandleFunc(pathSignECDSA, server.handle(server.signECDSA))
+	server.mux.HandleFunc(pathSignBLS, server.handle(server.signBLS))
+	server.mux.HandleFunc(pathProveVRF, server.handle(server.proveVRF))
+	server.mux.HandleFunc(pathGroupSecret, server.handle(server.groupSecret))
+	return server
+}
+
+// ListenAndServe serves the signer at the local endpoint until it fails
+func (server *Server) ListenAndServe(endpoint string) error {
+	listener, err := Listen(endpoint)
+	if err != nil {
+		return err
+	}
+	return http.Serve(listener, server)
+}
+
+// Listen creates the unix socket of the endpoint. The socket is created with the umask
+// only allowing the user running the signer, so that no other user can connect to it before it is served
+func Listen(endpoint string) (net.Listener, error) {
+	path, err := parseEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	os.Remove(path)
+
+	umaskLock.Lock()
+	defer umaskLock.Unlock()
+	mask := syscall.Umask(0177)
+	defer syscall.Umask(mask)
+	return net.Listen("unix", path)
+}
+
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	server.mux.ServeHTTP(w, r)
+}
+
+func (server *Server) handleAccount(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(server.account)
+}
+
+func (server *Server) handle(sign func(data []byte) (string, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if http.MethodPost != r.Method {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			json.NewEncoder(w).Encode(response{Error: "method not allowed"})
+			return
+		}
+
+		var req request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(response{Error: err.Error()})
+			return
+		}
+		data := common.FromHex(req.Data)
+		if 0 == len(data) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(response{Error: errNoData.Error()})
+			return
+		}
+
+		result, err := sign(data)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(response{Error: err.Error()})
+			return
+		}
+		json.NewEncoder(w).Encode(response{Result: result})
+	}
+}
+
+func (server *Server) signECDSA(hash []byte) (string, error) {
+	if common.HashLength != len(hash) {
+		return "", errors.New("invalid hash length")
+	}
+	return server.privateKey.Sign(hash).GetHexString(), nil
+}
+
+func (server *Server) signBLS(hash []byte) (string, error) {
+	if common.HashLength != len(hash) {
+		return "", errors.New("invalid hash length")
+	}
+	return groupsig.Sign(server.minerInfo.SecKey, hash).GetHexString(), nil
+}
+
+func (server *Server) proveVRF(msg []byte) (string, error) {
+	prove, err := server.minerInfo.GenVRFProve(msg)
+	if err != nil {
+		return "", err
+	}
+	return common.ToHex(prove), nil
+}
+
+func (server *Server) groupSecret(hash []byte) (string, error) {
+	if common.HashLength != len(hash) {
+		return "", errors.New("invalid hash length")
+	}
+	secret, err := server.minerInfo.GenSecretForGroup(common.BytesToHash(hash))
+	if err != nil {
+		return "", err
+	}
+	return common.ToHex(secret.Bytes()), nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package signer
+
+import (
+	"errors"
+	"strings"
+)
+
+// The signer serves the node over http on a unix socket, only the user running the signer can connect to it.
+// All the requests and responses are json, the binary values are 0x prefixed hex strings.
+const (
+	pathAccount     = "/account"
+	pathSignECDSA   = "/sign/ecdsa"
+	pathSignBLS     = "/sign/bls"
+	pathProveVRF    = "/prove/vrf"
+	pathGroupSecret = "/secret/group"
+
+	unixPrefix = "unix:"
+)
+
+var (
+	errNotLocal = errors.New("signer endpoint must be a unix socket")
+	errNoData   = errors.New("empty data")
+)
+
+// Account is the public info of the keys held by the signer
+type Account struct {
+	Address   string `json:"address"`
+	PublicKey string `json:"publicKey"`
+	MinerId   string `json:"minerId"`
+	BLSPubKey string `json:"blsPubKey"`
+	VRFPubKey string `json:"vrfPubKey"`
+}
+
+type request struct {
+	Data string `json:"data"`
+}
+
+type response struct {
+	Result string `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// parseEndpoint returns the socket path of the endpoint, which is "unix:/path/to/signer.sock"
+func parseEndpoint(endpoint string) (string, error) {
+	if !strings.HasPrefix(endpoint, unixPrefix) {
+		return "", errNotLocal
+	}
+	path := strings.TrimPrefix(strings.TrimPrefix(endpoint, unixPrefix), "//")
+	if 0 == len(path) {
+		return "", errNotLocal
+	}
+	return path, nil
+}
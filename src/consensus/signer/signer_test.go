@@ -0,0 +1,134 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package signer
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/consensus/groupsig"
+	"com.tuntun.rangers/node/src/consensus/model"
+	"com.tuntun.rangers/node/src/consensus/vrf"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type hasher common.Hash
+
+func (h hasher) GenHash() common.Hash {
+	return common.Hash(h)
+}
+
+// serve serves the signer of the key on a unix socket in a temp dir, and connects to it
+func serve(t *testing.T, privateKey common.PrivateKey) (*Client, string) {
+	dir, err := ioutil.TempDir("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoint := unixPrefix + filepath.Join(dir, "signer.sock")
+	listener, err := Listen(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go http.Serve(listener, NewServer(privateKey))
+
+	client, err := Dial(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, dir
+}
+
+func TestRemoteSigner(t *testing.T) {
+	privateKey := common.GenerateKey("")
+	local := model.NewSelfMinerInfo(privateKey)
+
+	client, dir := serve(t, privateKey)
+	defer os.RemoveAll(dir)
+	if client.Account().Address != privateKey.GetPubKey().GetAddress().GetHexString() {
+		t.Fatalf("address mismatch: %s", client.Account().Address)
+	}
+	remote, err := client.MinerInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !remote.ID.IsEqual(local.ID) || !remote.PubKey.IsEqual(local.PubKey) || remote.VrfPK.GetHexString() != local.VrfPK.GetHexString() {
+		t.Fatal("miner info mismatch")
+	}
+	if remote.SecKey.IsValid() || 0 != len(remote.VrfSK) {
+		t.Fatal("remote miner info holds secret keys")
+	}
+
+	hash := common.BytesToHash(common.Sha256([]byte("remote signer")))
+	signData := common.NewSignData(client, remote.ID.GetHexString(), hasher(hash))
+	if err := signData.ValidateSign(hasher(hash)); err != nil {
+		t.Fatal(err)
+	}
+
+	signInfo, ok := remote.Sign(hasher(hash))
+	if !ok || !groupsig.VerifySig(local.PubKey, hash.Bytes(), signInfo.GetSignature()) {
+		t.Fatal("bls sign failed")
+	}
+
+	msg := []byte("vrf message")
+	prove, err := remote.GenVRFProve(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := vrf.VRFVerify(local.VrfPK, prove, msg); !ok || err != nil {
+		t.Fatalf("vrf verify failed: %v", err)
+	}
+
+	localSecret, _ := local.GenSecretForGroup(hash)
+	remoteSecret, err := remote.GenSecretForGroup(hash)
+	if err != nil || localSecret != remoteSecret {
+		t.Fatalf("group secret mismatch: %v", err)
+	}
+}
+
+func TestRemoteSignerUnixSocket(t *testing.T) {
+	privateKey := common.GenerateKey("")
+	client, dir := serve(t, privateKey)
+	defer os.RemoveAll(dir)
+
+	info, err := os.Stat(filepath.Join(dir, "signer.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if 0 != info.Mode().Perm()&0077 {
+		t.Fatalf("socket open to other users: %s", info.Mode().Perm())
+	}
+	hash := common.Sha256([]byte("unix socket"))
+	sign := client.Sign(hash)
+	if !privateKey.GetPubKey().Verify(hash, &sign) {
+		t.Fatal("ecdsa sign failed")
+	}
+}
+
+func TestParseEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"unix:/tmp/signer.sock", "unix://tmp/signer.sock", "unix:signer.sock"} {
+		if _, err := parseEndpoint(endpoint); err != nil {
+			t.Fatalf("%s: %v", endpoint, err)
+		}
+	}
+	for _, endpoint := range []string{"127.0.0.1:8545", "http://localhost:8545", "[::1]:8545", "10.0.0.1:8545", "unix:", "8545"} {
+		if _, err := parseEndpoint(endpoint); err == nil {
+			t.Fatalf("%s accepted", endpoint)
+		}
+	}
+}
@@ -66,6 +66,12 @@ func (manager *peerManager) isEvil(id string) bool {
 	return exit
 }
 
+func (manager *peerManager) count() int {
+	manager.lock.RLock()
+	defer manager.lock.RUnlock()
+	return len(manager.badPeers)
+}
+
 func (manager *peerManager) loop() {
 	for {
 		select {
@@ -54,9 +54,15 @@ var blockChainImpl *blockChain
 type blockChain struct {
 	init bool
 
+	// replaying an archive, no need to broadcast chain info
+	importing bool
+
 	latestBlock *types.BlockHeader
 	requestIds  map[string]uint64
 
+	// blocks at or below finalizedHeight can no longer be removed by a fork
+	finalizedHeight uint64
+
 	topBlocks         *lru.Cache
 	futureBlocks      *lru.Cache
 	verifiedBlocks    *lru.Cache
@@ -126,6 +132,9 @@ func initBlockChain() error {
 		chain.ensureChainConsistency()
 
 		state, err := middleware.AccountDBManagerInstance.GetAccountDBByHash(chain.latestBlock.StateTree)
+		if err == middleware.ErrStatePruned {
+			state, err = chain.rewindToState()
+		}
 		if nil != err {
 			panic(err)
 		}
@@ -188,13 +197,16 @@ func (chain *blockChain) CastBlock(timestamp time.Time, height uint64, proveValu
 		PreTime:    latestBlock.CurTime,
 	}
 	bh.RequestIds = getRequestIdFromTransactions(txs, latestBlock.RequestIds)
+	if common.CurrentRules().IsProposal029 {
+		bh.GasLimit = common.BlockGasLimit
+	}
 
 	middleware.PerfLogger.Infof("fin cast object. last: %v height: %v", utility.GetTime().Sub(timestamp), height)
 
 	block := new(types.Block)
 	block.Transactions = txs
 
-	if common.IsProposal020() {
+	if common.CurrentRules().IsProposal020 {
 		transactionHashes := make([]common.Hashes, len(txs))
 		for i, transaction := range txs {
 			hashes := common.Hashes{}
@@ -225,7 +237,7 @@ func (chain *blockChain) runTransactions(block *types.Block, state *account.Acco
 	stateRoot, evictedTxs, transactions, receipts := executor.Execute()
 	middleware.PerfLogger.Infof("fin execute txs. last: %v height: %v", utility.GetTime().Sub(timestamp), height)
 
-	if !common.IsProposal020() || common.IsProposal023() {
+	if !common.CurrentRules().IsProposal020 || common.CurrentRules().IsProposal023 {
 		transactionHashes := make([]common.Hashes, len(transactions))
 		block.Transactions = transactions
 		for i, transaction := range transactions {
@@ -244,6 +256,7 @@ func (chain *blockChain) runTransactions(block *types.Block, state *account.Acco
 
 	block.Header.StateTree = stateRoot
 	block.Header.ReceiptTree = calcReceiptsTree(receipts)
+	block.Header.GasUsed = calcGasUsed(receipts)
 	block.Header.Hash = block.Header.GenHash()
 	middleware.PerfLogger.Infof("fin calcReceiptsTree. last: %v height: %v", utility.GetTime().Sub(timestamp), height)
 
@@ -448,6 +461,24 @@ func (chain *blockChain) remove(block *types.Block) bool {
 	return true
 }
 
+// rewindToState removes the top blocks until the state of latest block is found.
+// In gcmode full the recent states are lost if the node is not closed normally,
+// the rewind is bounded as documented on the state pruner.
+func (chain *blockChain) rewindToState() (*account.AccountDB, error) {
+	for {
+		logger.Errorf("State of latest block is pruned, rewind. height: %d, state: %s", chain.latestBlock.Height, chain.latestBlock.StateTree.String())
+		block := chain.queryBlockByHash(chain.latestBlock.Hash)
+		if nil == block || !chain.remove(block) {
+			return nil, middleware.ErrStatePruned
+		}
+
+		state, err := middleware.AccountDBManagerInstance.GetAccountDBByHash(chain.latestBlock.StateTree)
+		if err != middleware.ErrStatePruned {
+			return state, err
+		}
+	}
+}
+
 func (chain *blockChain) HasBlockByHash(hash common.Hash) bool {
 	result, err := chain.hashDB.Has(hash.Bytes())
 	if err != nil {
@@ -465,6 +496,16 @@ func (chain *blockChain) GetBlockHash(height uint64) common.Hash {
 	return common.Hash{}
 }
 
+// PrevRandao is the randomness of the parent's group signature,
+// since the group signs the block after its execution
+func (chain *blockChain) PrevRandao(header *types.BlockHeader) common.Hash {
+	parent := chain.queryBlockHeaderByHash(header.PreHash)
+	if parent != nil {
+		return parent.RandaoMix()
+	}
+	return common.Hash{}
+}
+
 func (chain *blockChain) queryBlockByHash(hash common.Hash) *types.Block {
 	result, err := chain.hashDB.Get(hash.Bytes())
 
@@ -551,6 +592,20 @@ func (chain *blockChain) buildCache(size uint64) {
 	}
 }
 
+// ReadLatestBlockHeader reads the latest block header from the chain database of a stopped node
+func ReadLatestBlockHeader(chainDB db.Database) *types.BlockHeader {
+	result, _ := chainDB.Get([]byte(heightDBPrefix + latestBlockKey))
+	if nil == result {
+		return nil
+	}
+
+	header, err := types.UnMarshalBlockHeader(result)
+	if err != nil {
+		return nil
+	}
+	return header
+}
+
 func generateHeightKey(height uint64) []byte {
 	h := make([]byte, 8)
 	binary.BigEndian.PutUint64(h, height)
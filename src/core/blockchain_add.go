@@ -92,6 +92,10 @@ func (chain *blockChain) addBlockOnChain(coming *types.Block) types.AddBlockResu
 		return types.AddBlockFailed
 	}
 
+	if !chain.canReorgTo(commonAncestor) {
+		return types.AddBlockFailed
+	}
+
 	if comingHeader.TotalQN > topBlock.TotalQN {
 		logger.Warnf("coming qn great than local. Remove from common ancestor and add...coming block:hash=%v, preH=%v, height=%v,totalQn:%d. Local topHash=%v, topPreHash=%v, height=%v,totalQn:%d. commonAncestor hash:%s height:%d",
 			comingHeader.Hash.Hex(), comingHeader.PreHash.Hex(), comingHeader.Height, comingHeader.TotalQN, topBlock.Hash.Hex(), topBlock.PreHash.Hex(), topBlock.Height, topBlock.TotalQN, commonAncestor.Hash.Hex(), commonAncestor.Height)
@@ -120,6 +124,10 @@ func (chain *blockChain) checkStates(block *types.Block, setHash bool) (bool, *a
 	if preBlock == nil {
 		panic("Pre block nil !!")
 	}
+	if exceedGasLimit(block.Transactions) {
+		logger.Errorf("Fail to verify gas, txs exceed the block gas limit. Hash:%s, height:%d", block.Header.Hash.String(), block.Header.Height)
+		return false, nil, nil
+	}
 	preRoot := common.BytesToHash(preBlock.StateTree.Bytes())
 	if len(block.Transactions) > 0 {
 		logger.Debugf("NewAccountDB height:%d StateTree:%s preHash:%s preRoot:%s", block.Header.Height, block.Header.StateTree.Hex(), preBlock.Hash.Hex(), preRoot.Hex())
@@ -140,8 +148,12 @@ func (chain *blockChain) checkStates(block *types.Block, setHash bool) (bool, *a
 		block.Header.StateTree = stateRoot
 		block.Header.ReceiptTree = receiptsTree
 		block.Header.EvictedTxs = evictedTxs
+		if common.CurrentRules().IsProposal029 {
+			block.Header.GasLimit = common.BlockGasLimit
+			block.Header.GasUsed = calcGasUsed(receipts)
+		}
 
-		if !common.IsProposal020() || common.IsProposal023() {
+		if !common.CurrentRules().IsProposal020 || common.CurrentRules().IsProposal023 {
 			transactionHashes := make([]common.Hashes, len(transactions))
 			block.Transactions = transactions
 			for i, transaction := range transactions {
@@ -172,6 +184,11 @@ func (chain *blockChain) checkStates(block *types.Block, setHash bool) (bool, *a
 			logger.Errorf("fail to verify txTree, hash1:%s hash2:%s", txTree.String(), block.Header.TxTree.String())
 			return false, state, receipts
 		}
+
+		if !verifyGas(block.Header, receipts) {
+			logger.Errorf("fail to verify gas, gas limit:%d gas used:%d", block.Header.GasLimit, block.Header.GasUsed)
+			return false, state, receipts
+		}
 	}
 
 	chain.verifiedBlocks.Add(block.Header.Hash, &castingBlock{state: state, receipts: receipts})
@@ -269,8 +286,7 @@ func (chain *blockChain) saveStates(b *types.Block) (bool, *account.AccountDB, t
 		return false, state, receipts
 	}
 
-	trieDB := middleware.AccountDBManagerInstance.GetTrieDB()
-	err = trieDB.Commit(root, false)
+	err = middleware.AccountDBManagerInstance.CommitState(root, b.Header.Height)
 	if err != nil {
 		logger.Errorf("Trie commit error:%s", err.Error())
 		return false, state, receipts
@@ -288,6 +304,7 @@ func (chain *blockChain) updateLastBlock(state *account.AccountDB, block *types.
 
 	chain.latestBlock = header
 	chain.requestIds = header.RequestIds
+	chain.updateFinalized()
 
 	middleware.AccountDBManagerInstance.SetLatestStateDB(state, block.Header.RequestIds, block.Header.Height)
 	logger.Debugf("Update latestStateDB: %s, height: %d, hash: %s", header.StateTree.Hex(), header.Height, header.Hash.String())
@@ -320,7 +337,7 @@ func (chain *blockChain) successOnChainCallBack(remoteBlock *types.Block) {
 		chain.addBlockOnChain(block)
 		return
 	}
-	if SyncProcessor != nil {
+	if SyncProcessor != nil && !chain.importing {
 		go SyncProcessor.broadcastChainInfo(chain.latestBlock)
 	}
 }
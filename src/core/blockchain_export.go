@@ -0,0 +1,290 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/middleware"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// archives are gzip streams of a magic, a version byte and records.
+// every record is a kind byte, an uvarint length and the record bytes.
+// the chain archive holds the protobuf bytes of blocks and groups.
+const (
+	archiveVersion = 1
+
+	archiveBlock byte = 1
+	archiveGroup byte = 2
+
+	maxArchiveRecordSize = 256 * 1024 * 1024
+)
+
+var (
+	archiveMagic = []byte("rpchain")
+
+	ErrInvalidArchive = errors.New("invalid chain archive")
+	ErrChainNotInit   = errors.New("chain not init")
+)
+
+type archiveWriter struct {
+	gz  *gzip.Writer
+	buf []byte
+}
+
+func newArchiveWriter(w io.Writer, magic []byte) (*archiveWriter, error) {
+	writer := &archiveWriter{gz: gzip.NewWriter(w), buf: make([]byte, binary.MaxVarintLen64+1)}
+	if _, err := writer.gz.Write(append(common.CopyBytes(magic), archiveVersion)); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (writer *archiveWriter) writeBlock(b *types.Block) error {
+	data, err := types.MarshalBlock(b)
+	if err != nil {
+		return err
+	}
+	return writer.write(archiveBlock, data)
+}
+
+func (writer *archiveWriter) writeGroup(g *types.Group) error {
+	data, err := types.MarshalGroup(g)
+	if err != nil {
+		return err
+	}
+	return writer.write(archiveGroup, data)
+}
+
+func (writer *archiveWriter) write(kind byte, data []byte) error {
+	writer.buf[0] = kind
+	n := binary.PutUvarint(writer.buf[1:], uint64(len(data)))
+	if _, err := writer.gz.Write(writer.buf[:n+1]); err != nil {
+		return err
+	}
+	_, err := writer.gz.Write(data)
+	return err
+}
+
+func (writer *archiveWriter) Close() error {
+	return writer.gz.Close()
+}
+
+type archiveReader struct {
+	gz *gzip.Reader
+	r  *bufio.Reader
+}
+
+func newArchiveReader(r io.Reader, magic []byte) (*archiveReader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	reader := &archiveReader{gz: gz, r: bufio.NewReader(gz)}
+
+	header := make([]byte, len(magic)+1)
+	if _, err := io.ReadFull(reader.r, header); err != nil {
+		return nil, ErrInvalidArchive
+	}
+	if !bytes.Equal(header[:len(magic)], magic) {
+		return nil, ErrInvalidArchive
+	}
+	if archiveVersion != header[len(magic)] {
+		return nil, fmt.Errorf("unsupported archive version %d", header[len(magic)])
+	}
+	return reader, nil
+}
+
+// read returns the kind and the bytes of the next record, and io.EOF at the end of the archive
+func (reader *archiveReader) read() (byte, []byte, error) {
+	kind, err := reader.r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	size, err := binary.ReadUvarint(reader.r)
+	if err != nil || size > maxArchiveRecordSize {
+		return 0, nil, ErrInvalidArchive
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(reader.r, data); err != nil {
+		return 0, nil, ErrInvalidArchive
+	}
+	return kind, data, nil
+}
+
+// next returns either a block or a group of the chain archive
+func (reader *archiveReader) next() (*types.Block, *types.Group, error) {
+	kind, data, err := reader.read()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch kind {
+	case archiveBlock:
+		block, err := types.UnMarshalBlock(data)
+		if err != nil || nil == block || nil == block.Header {
+			return nil, nil, ErrInvalidArchive
+		}
+		return block, nil, nil
+	case archiveGroup:
+		group, err := types.UnMarshalGroup(data)
+		if err != nil || nil == group || nil == group.Header {
+			return nil, nil, ErrInvalidArchive
+		}
+		return nil, group, nil
+	default:
+		return nil, nil, ErrInvalidArchive
+	}
+}
+
+func (reader *archiveReader) Close() error {
+	return reader.gz.Close()
+}
+
+// ExportChain writes the main chain blocks in [from, to] into w.
+// Groups created in the range follow the block they are created at, so that they can be replayed in order.
+// Groups created before the range and still working at from precede the first block, the blocks of the range need them.
+func ExportChain(w io.Writer, from, to uint64) (blocks int, groups int, err error) {
+	if nil == blockChainImpl || nil == groupChainImpl {
+		return 0, 0, ErrChainNotInit
+	}
+	if top := blockChainImpl.Height(); to > top {
+		to = top
+	}
+	if from > to {
+		return 0, 0, fmt.Errorf("invalid range [%d, %d]", from, to)
+	}
+
+	writer, err := newArchiveWriter(w, archiveMagic)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	groupHeight, groupCount := uint64(0), groupChainImpl.Count()
+	nextGroup := func(height uint64) *types.Group {
+		for ; groupHeight < groupCount; groupHeight++ {
+			group := groupChainImpl.GetGroupByHeight(groupHeight)
+			if nil == group || (group.Header.CreateHeight < from && group.Header.DismissHeight <= from) {
+				continue
+			}
+			if group.Header.CreateHeight > height {
+				return nil
+			}
+			groupHeight++
+			return group
+		}
+		return nil
+	}
+
+	writeGroups := func(height uint64) error {
+		for group := nextGroup(height); nil != group; group = nextGroup(height) {
+			if err := writer.writeGroup(group); err != nil {
+				return err
+			}
+			groups++
+		}
+		return nil
+	}
+
+	if from > 0 {
+		if err = writeGroups(from - 1); err != nil {
+			return blocks, groups, err
+		}
+	}
+	for height := from; height <= to; height++ {
+		block := blockChainImpl.QueryBlock(height)
+		if nil != block {
+			if err = writer.writeBlock(block); err != nil {
+				return blocks, groups, err
+			}
+			blocks++
+		}
+
+		if err = writeGroups(height); err != nil {
+			return blocks, groups, err
+		}
+	}
+
+	return blocks, groups, writer.Close()
+}
+
+// ImportChain replays the blocks and groups of an archive on the local chain.
+// Blocks and groups are fully verified unless trusted, which skips the group signature verification.
+func ImportChain(r io.Reader, trusted bool) (blocks int, groups int, err error) {
+	if nil == blockChainImpl || nil == groupChainImpl {
+		return 0, 0, ErrChainNotInit
+	}
+	reader, err := newArchiveReader(r, archiveMagic)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer reader.Close()
+
+	blockChainImpl.importing = true
+	defer func() {
+		blockChainImpl.importing = false
+	}()
+
+	for {
+		block, group, err := reader.next()
+		if err == io.EOF {
+			return blocks, groups, nil
+		}
+		if err != nil {
+			return blocks, groups, err
+		}
+
+		if nil != group {
+			err = groupChainImpl.addGroup(group, !trusted)
+			if err == common.ErrGroupAlreadyExist {
+				continue
+			}
+			if err != nil {
+				return blocks, groups, fmt.Errorf("fail to import group %s: %v", common.ToHex(group.Id), err)
+			}
+			groups++
+			continue
+		}
+
+		var result types.AddBlockResult
+		if trusted {
+			result = blockChainImpl.addBlockTrusted(block)
+		} else {
+			result = blockChainImpl.AddBlockOnChain(block)
+		}
+		switch result {
+		case types.AddBlockSucc:
+			blocks++
+		case types.BlockExisted:
+		default:
+			return blocks, groups, fmt.Errorf("fail to import block %d %s, result: %d", block.Header.Height, block.Header.Hash.String(), result)
+		}
+	}
+}
+
+func (chain *blockChain) addBlockTrusted(b *types.Block) types.AddBlockResult {
+	middleware.LockBlockchain("AddBlockTrusted")
+	defer middleware.UnLockBlockchain("AddBlockTrusted")
+	return chain.addBlockOnChain(b)
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestChainArchive(t *testing.T) {
+	types.InitSerialzation()
+
+	var buf bytes.Buffer
+	writer, err := newArchiveWriter(&buf, archiveMagic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := &types.Transaction{Source: "0x38780174572fb5b4735df1b7c69aee77ff6e9f49", Target: "0x1", Type: types.TransactionTypeETHTX, Nonce: 7}
+	tx.Hash = tx.GenHash()
+	block := &types.Block{Header: &types.BlockHeader{Height: 10, PreHash: common.HexToHash("0x01"), CurTime: time.Unix(1600000000, 0), Transactions: []common.Hashes{{tx.Hash, common.Hash{}}}}, Transactions: []*types.Transaction{tx}}
+	block.Header.Hash = block.Header.GenHash()
+	group := &types.Group{Header: &types.GroupHeader{CreateHeight: 10, BeginTime: time.Unix(1600000000, 0)}, Id: []byte{1, 2, 3}, PubKey: []byte{4, 5}, Members: [][]byte{{6}, {7}}}
+	group.Header.Hash = group.Header.GenHash()
+
+	if err := writer.writeBlock(block); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.writeGroup(group); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := newArchiveReader(&buf, archiveMagic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, g, err := reader.next()
+	if err != nil || nil != g || nil == b {
+		t.Fatalf("expect block, got %v %v %v", b, g, err)
+	}
+	if b.Header.Hash != block.Header.Hash || b.Header.Height != 10 || 1 != len(b.Transactions) || b.Transactions[0].Hash != tx.Hash {
+		t.Fatalf("block mismatch: %+v", b.Header)
+	}
+	b, g, err = reader.next()
+	if err != nil || nil != b || nil == g {
+		t.Fatalf("expect group, got %v %v %v", b, g, err)
+	}
+	if !bytes.Equal(g.Id, group.Id) || g.Header.Hash != group.Header.Hash || g.Header.CreateHeight != 10 || 2 != len(g.Members) {
+		t.Fatalf("group mismatch: %+v", g.Header)
+	}
+	if _, _, err = reader.next(); err != io.EOF {
+		t.Fatalf("expect EOF, got %v", err)
+	}
+}
+
+func TestChainArchiveInvalid(t *testing.T) {
+	if _, err := newArchiveReader(bytes.NewReader([]byte("not an archive")), archiveMagic); err == nil {
+		t.Fatal("expect error")
+	}
+
+	var buf bytes.Buffer
+	writer, _ := newArchiveWriter(&buf, archiveMagic)
+	writer.write(3, []byte{1, 2, 3})
+	writer.Close()
+
+	reader, err := newArchiveReader(&buf, archiveMagic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = reader.next(); err != ErrInvalidArchive {
+		t.Fatalf("expect ErrInvalidArchive, got %v", err)
+	}
+}
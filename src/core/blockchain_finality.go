@@ -0,0 +1,99 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/middleware/types"
+)
+
+const (
+	// a block with safeDepth descendants is safe, the eth receipts are returned since then
+	safeDepth = 3
+	// the blocks looked back from the top block for the signing groups
+	maxFinalityDepth = 1000
+)
+
+// A block is finalized once the blocks built on it are signed by more than two thirds of the groups
+// casting at the top block. A fork replacing it needs those groups to sign conflicting blocks,
+// which their members are slashed for, so the fork choice never removes a finalized block.
+//
+// updateFinalized moves the finalized height along with the top block.
+// It never goes back when the top block is removed by a fork, only SetHead resets it.
+func (chain *blockChain) updateFinalized() {
+	top := chain.latestBlock
+	if nil == top || nil == groupChainImpl {
+		return
+	}
+
+	quorum := castGroupCount(top.Height)*2/3 + 1
+	signers := make(map[string]struct{})
+	header := top
+	for depth := 0; nil != header && header.Height > chain.finalizedHeight && depth < maxFinalityDepth; depth++ {
+		signers[common.ToHex(header.GroupId)] = struct{}{}
+		parent := chain.queryBlockHeaderByHash(header.PreHash)
+		if len(signers) >= quorum {
+			if nil != parent && parent.Height > chain.finalizedHeight {
+				chain.finalizedHeight = parent.Height
+			}
+			return
+		}
+		header = parent
+	}
+}
+
+// castGroupCount counts the groups casting at the height, the genesis group included
+func castGroupCount(height uint64) int {
+	count := 0
+	iter := groupChainImpl.Iterator()
+	for g := iter.Current(); g != nil; g = iter.MovePre() {
+		if g.Header.WorkHeight <= height && height < g.Header.DismissHeight {
+			count++
+		} else if g.Header.DismissHeight <= height {
+			count++
+			break
+		}
+	}
+	return count
+}
+
+// canReorgTo tells whether the blocks above the common ancestor can be removed by a fork
+func (chain *blockChain) canReorgTo(commonAncestor *types.BlockHeader) bool {
+	if commonAncestor.Height >= chain.finalizedHeight {
+		return true
+	}
+	logger.Warnf("refuse to reorg below the finalized block. common ancestor: %d, finalized: %d", commonAncestor.Height, chain.finalizedHeight)
+	return false
+}
+
+// FinalizedBlock returns the header of the latest block which can no longer be removed by a fork
+func (chain *blockChain) FinalizedBlock() *types.BlockHeader {
+	return chain.QueryBlockHeaderByHeight(chain.finalizedHeight, true)
+}
+
+// SafeBlock returns the header of the latest block with safeDepth descendants
+func (chain *blockChain) SafeBlock() *types.BlockHeader {
+	top := chain.latestBlock
+	if nil == top {
+		return nil
+	}
+	height := chain.finalizedHeight
+	if top.Height >= safeDepth && top.Height-safeDepth > height {
+		height = top.Height - safeDepth
+	}
+	return chain.QueryBlockHeaderByHeight(height, true)
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"com.tuntun.rangers/node/src/middleware"
+	"errors"
+)
+
+var (
+	ErrSetHeadAboveTop = errors.New("set head above the top block")
+	ErrSetHeadRemove   = errors.New("fail to remove the top block")
+)
+
+// SetHead removes the blocks above height from the main chain and refreshes the latest state.
+// If the state of the block at height is pruned, the chain is rewound further to the latest kept state.
+func SetHead(height uint64) (uint64, error) {
+	if nil == blockChainImpl {
+		return 0, ErrChainNotInit
+	}
+	return blockChainImpl.setHead(height)
+}
+
+func (chain *blockChain) setHead(height uint64) (uint64, error) {
+	middleware.LockBlockchain("SetHead")
+	defer middleware.UnLockBlockchain("SetHead")
+
+	if height > chain.latestBlock.Height {
+		return chain.latestBlock.Height, ErrSetHeadAboveTop
+	}
+	logger.Warnf("set head from %d to %d", chain.latestBlock.Height, height)
+	for chain.latestBlock.Height > height {
+		block := chain.queryBlockByHash(chain.latestBlock.Hash)
+		if nil == block || !chain.remove(block) {
+			return chain.latestBlock.Height, ErrSetHeadRemove
+		}
+	}
+
+	// the operator rewinds on purpose, even below the finalized block
+	if chain.finalizedHeight > chain.latestBlock.Height {
+		chain.finalizedHeight = chain.latestBlock.Height
+	}
+
+	state, err := middleware.AccountDBManagerInstance.GetAccountDBByHash(chain.latestBlock.StateTree)
+	if err == middleware.ErrStatePruned {
+		state, err = chain.rewindToState()
+	}
+	if nil != err {
+		return chain.latestBlock.Height, err
+	}
+	middleware.AccountDBManagerInstance.SetLatestStateDB(state, chain.latestBlock.RequestIds, chain.latestBlock.Height)
+	logger.Warnf("set head done, height: %d, hash: %s", chain.latestBlock.Height, chain.latestBlock.Hash.String())
+	return chain.latestBlock.Height, nil
+}
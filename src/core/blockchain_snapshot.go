@@ -0,0 +1,391 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/consensus/groupsig"
+	"com.tuntun.rangers/node/src/middleware"
+	"com.tuntun.rangers/node/src/middleware/db"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"com.tuntun.rangers/node/src/storage/account"
+	"com.tuntun.rangers/node/src/storage/rlp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// state snapshot: the manifest, the groups, the recent headers, the snapshot block and the flat state of the block.
+// groups are json as in the group chain, headers and block are protobuf, the others are rlp.
+const (
+	snapshotManifest byte = 1
+	snapshotGroup    byte = 2
+	snapshotHeader   byte = 3
+	snapshotBlock    byte = 4
+	snapshotAccount  byte = 5
+	snapshotStorage  byte = 6
+	snapshotNFTSet   byte = 7
+
+	// headers before the snapshot block, for the block cache and short forks
+	snapshotHeaders = topBlocksCacheSize
+
+	// the state is rebuilt here before its root is verified
+	snapshotStagingDB = "state_staging"
+)
+
+var (
+	snapshotMagic = []byte("rpstate")
+
+	ErrSnapshotBlockNotFound = errors.New("snapshot block not found")
+	ErrChainExisted          = errors.New("chain data existed")
+)
+
+type SnapshotManifest struct {
+	Height    uint64
+	Hash      common.Hash
+	StateRoot common.Hash
+}
+
+type snapshotWriter struct {
+	*archiveWriter
+}
+
+func (writer snapshotWriter) OnAccount(address []byte, account []byte) error {
+	return writer.writeRLP(snapshotAccount, [][]byte{address, account})
+}
+
+func (writer snapshotWriter) OnStorage(key []byte, value []byte) error {
+	return writer.writeRLP(snapshotStorage, [][]byte{key, value})
+}
+
+func (writer snapshotWriter) OnNFTSet(hash common.Hash, definition []byte) error {
+	return writer.writeRLP(snapshotNFTSet, [][]byte{hash.Bytes(), definition})
+}
+
+func (writer snapshotWriter) writeHeader(header *types.BlockHeader) error {
+	if nil == header {
+		return ErrSnapshotBlockNotFound
+	}
+	data, err := types.MarshalBlockHeader(header)
+	if err != nil {
+		return err
+	}
+	return writer.write(snapshotHeader, data)
+}
+
+func (writer snapshotWriter) writeRLP(kind byte, value interface{}) error {
+	data, err := rlp.EncodeToBytes(value)
+	if err != nil {
+		return err
+	}
+	return writer.write(kind, data)
+}
+
+// ExportSnapshot writes the state of the main chain block at height into w.
+// It returns the manifest and the number of accounts.
+func ExportSnapshot(w io.Writer, height uint64) (*SnapshotManifest, int, error) {
+	if nil == blockChainImpl || nil == groupChainImpl {
+		return nil, 0, ErrChainNotInit
+	}
+	block := blockChainImpl.QueryBlock(height)
+	if nil == block {
+		return nil, 0, ErrSnapshotBlockNotFound
+	}
+	header := block.Header
+	manifest := &SnapshotManifest{Height: header.Height, Hash: header.Hash, StateRoot: header.StateTree}
+
+	archive, err := newArchiveWriter(w, snapshotMagic)
+	if err != nil {
+		return nil, 0, err
+	}
+	writer := snapshotWriter{archive}
+	if err = writer.writeRLP(snapshotManifest, manifest); err != nil {
+		return nil, 0, err
+	}
+
+	for i := uint64(0); i < groupChainImpl.Count(); i++ {
+		group := groupChainImpl.GetGroupByHeight(i)
+		if nil == group || group.Header.CreateHeight > height {
+			break
+		}
+		data, err := json.Marshal(group)
+		if err != nil {
+			return nil, 0, err
+		}
+		if err = writer.write(snapshotGroup, data); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	// the genesis header is kept for the data version check
+	from := uint64(0)
+	if height > snapshotHeaders+1 {
+		from = height - snapshotHeaders
+		if err = writer.writeHeader(blockChainImpl.QueryBlockHeaderByHeight(uint64(0), false)); err != nil {
+			return nil, 0, err
+		}
+	}
+	for h := from; h < height; h++ {
+		bh := blockChainImpl.QueryBlockHeaderByHeight(h, false)
+		if nil == bh {
+			continue
+		}
+		if err = writer.writeHeader(bh); err != nil {
+			return nil, 0, err
+		}
+	}
+	data, err := types.MarshalBlock(block)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err = writer.write(snapshotBlock, data); err != nil {
+		return nil, 0, err
+	}
+
+	count, err := account.DumpState(middleware.AccountDBManagerInstance.GetTrieDB(), header.StateTree, writer)
+	if err != nil {
+		return nil, count, err
+	}
+	return manifest, count, writer.Close()
+}
+
+// ImportSnapshot writes the state, the block, the recent headers and the groups of a snapshot into the databases of a new node.
+// The groups, the headers, the group signature of the block and the rebuilt state root are verified before anything is written,
+// so that the node continues to sync from the snapshot height.
+func ImportSnapshot(r io.Reader, helper types.ConsensusHelper) (*SnapshotManifest, error) {
+	if nil != blockChainImpl {
+		return nil, ErrChainExisted
+	}
+	hashDB, err := db.NewDatabase(hashDBPrefix)
+	if err != nil {
+		return nil, err
+	}
+	heightDB, err := db.NewDatabase(heightDBPrefix)
+	if err != nil {
+		return nil, err
+	}
+	groupsDB, err := db.NewDatabase(groupChainPrefix)
+	if err != nil {
+		return nil, err
+	}
+	if exist, _ := heightDB.Has([]byte(latestBlockKey)); exist {
+		return nil, ErrChainExisted
+	}
+
+	reader, err := newArchiveReader(r, snapshotMagic)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	kind, data, err := reader.read()
+	if err != nil || snapshotManifest != kind {
+		return nil, ErrInvalidArchive
+	}
+	var manifest SnapshotManifest
+	if err := rlp.DecodeBytes(data, &manifest); err != nil {
+		return nil, ErrInvalidArchive
+	}
+
+	os.RemoveAll(snapshotStagingDB)
+	staging, err := db.NewLDBDatabase(snapshotStagingDB, 64, 64)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(snapshotStagingDB)
+	defer staging.Close()
+	rebuilder, err := account.NewStateRebuilder(staging)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		groups  []*types.Group
+		headers []*types.BlockHeader
+		block   *types.Block
+	)
+	for {
+		kind, data, err := reader.read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch kind {
+		case snapshotGroup:
+			var group *types.Group
+			if err := json.Unmarshal(data, &group); err != nil || nil == group || nil == group.Header {
+				return nil, ErrInvalidArchive
+			}
+			groups = append(groups, group)
+		case snapshotHeader:
+			header, err := types.UnMarshalBlockHeader(data)
+			if err != nil {
+				return nil, ErrInvalidArchive
+			}
+			headers = append(headers, header)
+		case snapshotBlock:
+			block, err = types.UnMarshalBlock(data)
+			if err != nil || nil == block || nil == block.Header {
+				return nil, ErrInvalidArchive
+			}
+		case snapshotAccount, snapshotStorage, snapshotNFTSet:
+			var pair [][]byte
+			if err := rlp.DecodeBytes(data, &pair); err != nil || 2 != len(pair) {
+				return nil, ErrInvalidArchive
+			}
+			switch kind {
+			case snapshotAccount:
+				err = rebuilder.OnAccount(pair[0], pair[1])
+			case snapshotStorage:
+				err = rebuilder.OnStorage(pair[0], pair[1])
+			default:
+				err = rebuilder.OnNFTSet(common.BytesToHash(pair[0]), pair[1])
+			}
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, ErrInvalidArchive
+		}
+	}
+
+	genesis := make([]*types.Group, 0)
+	for _, info := range helper.GenerateGenesisInfo() {
+		genesis = append(genesis, &info.Group)
+	}
+	if err := verifySnapshot(&manifest, block, headers, groups, genesis); err != nil {
+		return nil, err
+	}
+	root, err := rebuilder.Commit()
+	if err != nil {
+		return nil, err
+	}
+	if root != manifest.StateRoot {
+		return nil, fmt.Errorf("%w, expect: %s, got: %s", account.ErrStateRootMismatch, manifest.StateRoot.String(), root.String())
+	}
+	if err := rebuilder.Write(middleware.AccountDBManagerInstance.GetStateDiskDB()); err != nil {
+		return nil, err
+	}
+
+	for i, group := range groups {
+		if err := writeGroup(groupsDB, group, uint64(i)); err != nil {
+			return nil, err
+		}
+	}
+	for _, header := range headers {
+		data, err := types.MarshalBlockHeader(header)
+		if err != nil {
+			return nil, err
+		}
+		if err := heightDB.Put(generateHeightKey(header.Height), data); err != nil {
+			return nil, err
+		}
+	}
+	blockByte, err := types.MarshalBlock(block)
+	if err != nil {
+		return nil, err
+	}
+	if err := hashDB.Put(block.Header.Hash.Bytes(), blockByte); err != nil {
+		return nil, err
+	}
+	headerByte, err := types.MarshalBlockHeader(block.Header)
+	if err != nil {
+		return nil, err
+	}
+	if err := heightDB.Put(generateHeightKey(block.Header.Height), headerByte); err != nil {
+		return nil, err
+	}
+	if err := heightDB.Put([]byte(latestBlockKey), headerByte); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// verifySnapshot checks the block and the recent headers are a hash chain matching the manifest,
+// the groups are a group chain starting from the genesis groups and the block is signed by one of them.
+// The create blocks of the groups are not in the snapshot, so every group is checked by the sign of its parent group
+func verifySnapshot(manifest *SnapshotManifest, block *types.Block, headers []*types.BlockHeader, groups []*types.Group, genesis []*types.Group) error {
+	if nil == block {
+		return ErrSnapshotBlockNotFound
+	}
+	bh := block.Header
+	if bh.Hash != manifest.Hash || bh.GenHash() != bh.Hash || bh.Height != manifest.Height || bh.StateTree != manifest.StateRoot {
+		return fmt.Errorf("snapshot block mismatch, height: %d, hash: %s", bh.Height, bh.Hash.String())
+	}
+
+	if 0 != bh.Height && (0 == len(headers) || 0 != headers[0].Height) {
+		return errors.New("snapshot without genesis header")
+	}
+	next := bh
+	for i := len(headers) - 1; i >= 0; i-- {
+		header := headers[i]
+		linked := header.Hash == next.PreHash || (0 == i && 0 == header.Height)
+		if !linked || header.GenHash() != header.Hash || header.Height >= next.Height {
+			return fmt.Errorf("snapshot header mismatch, height: %d, hash: %s", header.Height, header.Hash.String())
+		}
+		next = header
+	}
+
+	if 0 == len(genesis) || len(groups) < len(genesis) {
+		return errors.New("snapshot without groups")
+	}
+	verified := make(map[string]*types.Group, len(groups))
+	for i, group := range groups {
+		if i < len(genesis) {
+			if !bytes.Equal(group.Id, genesis[i].Id) || !bytes.Equal(group.PubKey, genesis[i].PubKey) {
+				return fmt.Errorf("snapshot genesis group mismatch, group: %s", common.ToHex(group.Id))
+			}
+		} else if err := verifySnapshotGroup(group, groups[i-1], verified); err != nil {
+			return err
+		}
+		verified[string(group.Id)] = group
+	}
+
+	signer := verified[string(bh.GroupId)]
+	if nil == signer || !verifyGroupSign(signer.PubKey, bh.Hash, bh.Signature) {
+		return fmt.Errorf("snapshot block sign mismatch, group: %s", common.ToHex(bh.GroupId))
+	}
+	return nil
+}
+
+func verifySnapshotGroup(group, pre *types.Group, verified map[string]*types.Group) error {
+	header := group.Header
+	parent := verified[string(header.Parent)]
+	if !bytes.Equal(header.PreGroup, pre.Id) || nil == parent || header.Hash != header.GenHash() {
+		return fmt.Errorf("snapshot group mismatch, group: %s", common.ToHex(group.Id))
+	}
+	if !verifyGroupSign(parent.PubKey, header.Hash, group.Signature) {
+		return fmt.Errorf("snapshot group sign mismatch, group: %s", common.ToHex(group.Id))
+	}
+	id := groupsig.NewIDFromPubkey(groupsig.ByteToPublicKey(group.PubKey))
+	if !bytes.Equal(id.Serialize(), group.Id) {
+		return fmt.Errorf("snapshot group pubkey mismatch, group: %s", common.ToHex(group.Id))
+	}
+	return nil
+}
+
+func verifyGroupSign(pubkey []byte, hash common.Hash, sign []byte) bool {
+	if 0 == len(pubkey) || 0 == len(sign) {
+		return false
+	}
+	return groupsig.VerifySig(groupsig.ByteToPublicKey(pubkey), hash.Bytes(), *groupsig.DeserializeSign(sign))
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/consensus/base"
+	"com.tuntun.rangers/node/src/consensus/groupsig"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"testing"
+	"time"
+)
+
+func newSnapshotHeader(height uint64, pre common.Hash) *types.BlockHeader {
+	header := &types.BlockHeader{Height: height, PreHash: pre, CurTime: time.Unix(1600000000+int64(height), 0), StateTree: common.HexToHash("0x5a")}
+	header.Hash = header.GenHash()
+	return header
+}
+
+func newSnapshotGroup(key groupsig.Seckey, pre, parent []byte, parentKey groupsig.Seckey) *types.Group {
+	pubkey := groupsig.GeneratePubkey(key)
+	group := &types.Group{Id: groupsig.NewIDFromPubkey(*pubkey).Serialize(), PubKey: pubkey.Serialize(),
+		Header: &types.GroupHeader{PreGroup: pre, Parent: parent, CreateHeight: 1}}
+	group.Header.Hash = group.Header.GenHash()
+	group.Signature = groupsig.Sign(parentKey, group.Header.Hash.Bytes()).Serialize()
+	return group
+}
+
+func TestVerifySnapshot(t *testing.T) {
+	r := base.NewRand()
+	genesisKey, groupKey := *groupsig.NewSeckeyFromRand(r.Deri(1)), *groupsig.NewSeckeyFromRand(r.Deri(2))
+	genesisGroup := newSnapshotGroup(genesisKey, nil, nil, genesisKey)
+	group := newSnapshotGroup(groupKey, genesisGroup.Id, genesisGroup.Id, genesisKey)
+	groups := []*types.Group{genesisGroup, group}
+	genesisGroups := []*types.Group{genesisGroup}
+
+	genesis := newSnapshotHeader(0, common.Hash{})
+	h5 := newSnapshotHeader(5, common.HexToHash("0x04"))
+	h7 := newSnapshotHeader(7, h5.Hash)
+	block := &types.Block{Header: newSnapshotHeader(8, h7.Hash)}
+	block.Header.GroupId = group.Id
+	block.Header.Signature = groupsig.Sign(groupKey, block.Header.Hash.Bytes()).Serialize()
+	manifest := &SnapshotManifest{Height: 8, Hash: block.Header.Hash, StateRoot: block.Header.StateTree}
+
+	if err := verifySnapshot(manifest, block, []*types.BlockHeader{genesis, h5, h7}, groups, genesisGroups); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifySnapshot(manifest, block, []*types.BlockHeader{h5, h7}, groups, genesisGroups); err == nil {
+		t.Fatal("expect error without genesis header")
+	}
+	if err := verifySnapshot(manifest, block, []*types.BlockHeader{genesis, h7, h5}, groups, genesisGroups); err == nil {
+		t.Fatal("expect error on broken header chain")
+	}
+
+	tampered := *h7
+	tampered.StateTree = common.HexToHash("0x01")
+	if err := verifySnapshot(manifest, block, []*types.BlockHeader{genesis, h5, &tampered}, groups, genesisGroups); err == nil {
+		t.Fatal("expect error on tampered header")
+	}
+
+	wrongRoot := *manifest
+	wrongRoot.StateRoot = common.HexToHash("0x01")
+	if err := verifySnapshot(&wrongRoot, block, []*types.BlockHeader{genesis, h5, h7}, groups, genesisGroups); err == nil {
+		t.Fatal("expect error on manifest mismatch")
+	}
+
+	if err := verifySnapshot(manifest, block, []*types.BlockHeader{genesis, h5, h7}, []*types.Group{group, genesisGroup}, genesisGroups); err == nil {
+		t.Fatal("expect error on broken group chain")
+	}
+
+	forged := newSnapshotGroup(groupKey, genesisGroup.Id, genesisGroup.Id, groupKey)
+	if err := verifySnapshot(manifest, block, []*types.BlockHeader{genesis, h5, h7}, []*types.Group{genesisGroup, forged}, genesisGroups); err == nil {
+		t.Fatal("expect error on group not signed by its parent")
+	}
+	if err := verifySnapshot(manifest, block, []*types.BlockHeader{genesis, h5, h7}, genesisGroups, genesisGroups); err == nil {
+		t.Fatal("expect error on a block signed by an unknown group")
+	}
+
+	unsigned := *block.Header
+	unsigned.Signature = groupsig.Sign(genesisKey, block.Header.Hash.Bytes()).Serialize()
+	if err := verifySnapshot(manifest, &types.Block{Header: &unsigned}, []*types.BlockHeader{genesis, h5, h7}, groups, genesisGroups); err == nil {
+		t.Fatal("expect error on wrong block sign")
+	}
+}
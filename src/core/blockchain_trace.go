@@ -0,0 +1,77 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/middleware"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"com.tuntun.rangers/node/src/vm"
+	"errors"
+	"sort"
+)
+
+var (
+	ErrTraceBlockNotFound = errors.New("block not found")
+	ErrTraceTxNotFound    = errors.New("transaction not found in block")
+)
+
+// TraceTransaction re-executes the block holding txHash on top of its parent state
+// and reports the execution of txHash to tracer.
+// Transactions following txHash in the block are not executed.
+func (chain *blockChain) TraceTransaction(blockHash common.Hash, txHash common.Hash, tracer vm.Tracer) (*types.Receipt, error) {
+	block := chain.QueryBlockByHash(blockHash)
+	if nil == block {
+		return nil, ErrTraceBlockNotFound
+	}
+	preHeader := chain.queryBlockHeaderByHash(block.Header.PreHash)
+	if nil == preHeader {
+		return nil, ErrTraceBlockNotFound
+	}
+	state, err := middleware.AccountDBManagerInstance.GetAccountDBByHash(preHeader.StateTree)
+	if err != nil {
+		return nil, err
+	}
+
+	// keep the execution order of VMExecutor and cut off the tail
+	txs := make(types.Transactions, len(block.Transactions))
+	copy(txs, block.Transactions)
+	sort.Sort(txs)
+	index := -1
+	for i, tx := range txs {
+		if tx.Hash == txHash {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, ErrTraceTxNotFound
+	}
+	traced := &types.Block{Header: block.Header, Transactions: txs[:index+1]}
+
+	executor := newVMExecutor(state, traced, "tracing")
+	executor.tracer = tracer
+	executor.traceTx = txHash
+	_, _, _, receipts := executor.Execute()
+	for _, receipt := range receipts {
+		if receipt.TxHash == txHash {
+			return receipt, nil
+		}
+	}
+	// evicted during execution
+	return nil, ErrTraceTxNotFound
+}
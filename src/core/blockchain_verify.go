@@ -45,7 +45,7 @@ func (chain *blockChain) verifyBlock(bh *types.BlockHeader, txs []*types.Transac
 		return nil, 2
 	}
 
-	if common.IsProposal008() {
+	if common.CurrentRules().IsProposal008 {
 		for _, tx := range txs {
 			if chain.transactionPool.GetExecuted(tx.Hash) != nil {
 				logger.Debugf("tx has already on chain:%s", tx.Hash.String())
@@ -69,7 +69,7 @@ func (chain *blockChain) verifyBlock(bh *types.BlockHeader, txs []*types.Transac
 	}
 
 	logger.Debugf("validateTxRoot,tx tree root:%v,len txs:%d,miss len:%d", bh.TxTree.Hex(), len(transactions), len(missingTx))
-	if !common.IsProposal020() && !chain.validateTxRoot(bh.TxTree, transactions) {
+	if !common.CurrentRules().IsProposal020 && !chain.validateTxRoot(bh.TxTree, transactions) {
 		return nil, -1
 	}
 
@@ -152,6 +152,40 @@ func calcTxTree(txs []*types.Transaction) common.Hash {
 	return common.BytesToHash(common.Sha256(buf.Bytes()))
 }
 
+// calcGasUsed returns the gas used by the block, the cumulative gas of its last receipt
+func calcGasUsed(receipts types.Receipts) uint64 {
+	for i := len(receipts) - 1; i >= 0; i-- {
+		if nil != receipts[i] {
+			return receipts[i].CumulativeGasUsed
+		}
+	}
+	return 0
+}
+
+// verifyGas checks the gas limit and the gas used of the header against the receipts since Proposal029
+func verifyGas(bh *types.BlockHeader, receipts types.Receipts) bool {
+	if !common.CurrentRules().IsProposal029 {
+		return true
+	}
+	return bh.GasLimit == common.BlockGasLimit && bh.GasUsed == calcGasUsed(receipts)
+}
+
+// exceedGasLimit checks the gas limits of the txs exceed the block gas limit since Proposal029.
+// Such a block is rejected, the txs over the limit are never skipped
+func exceedGasLimit(txs []*types.Transaction) bool {
+	if !common.CurrentRules().IsProposal029 {
+		return false
+	}
+	var gasLimit uint64
+	for _, tx := range txs {
+		if tx.GasLimit() > common.BlockGasLimit-gasLimit {
+			return true
+		}
+		gasLimit += tx.GasLimit()
+	}
+	return false
+}
+
 func calcReceiptsTree(receipts types.Receipts) common.Hash {
 	if nil == receipts || 0 == len(receipts) {
 		return emptyHash
@@ -0,0 +1,46 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestExceedGasLimit(t *testing.T) {
+	defer func() {
+		os.RemoveAll("logs")
+		os.RemoveAll("1.ini")
+	}()
+	common.Init(0, "1.ini", "dev")
+
+	tx := &types.Transaction{Type: types.TransactionTypeContract, Data: `{"gasLimit":"` + strconv.FormatUint(types.MaxTxGasLimit, 10) + `"}`}
+	count := int(common.BlockGasLimit / types.MaxTxGasLimit)
+	txs := []*types.Transaction{{Type: types.TransactionTypeOperatorEvent}}
+	for i := 0; i < count; i++ {
+		txs = append(txs, tx)
+	}
+	if exceedGasLimit(txs) {
+		t.Fatalf("%d txs of the max gas limit fit in the block", count)
+	}
+	if !exceedGasLimit(append(txs, tx)) {
+		t.Fatalf("%d txs of the max gas limit exceed the block", count+1)
+	}
+}
@@ -120,6 +120,9 @@ func (blockFork *blockChainFork) triggerOnChain(chain *blockChain) bool {
 	}
 
 	if blockFork.current == blockFork.header {
+		if !chain.canReorgTo(commonAncestor) {
+			return false
+		}
 		chain.removeFromCommonAncestor(commonAncestor)
 		blockFork.current++
 	}
@@ -190,7 +193,7 @@ func (fork *blockChainFork) addBlockOnFork(coming *types.Block, groupFork *group
 	if !verifyResult {
 		return verifyBlockErr
 	}
-	fork.saveState(state)
+	fork.saveState(state, coming.Header.Height)
 
 	fork.insertBlock(coming)
 	fork.latestBlock = coming.Header
@@ -281,6 +284,10 @@ func (fork *blockChainFork) verifyTxRoot(coming *types.Block) bool {
 }
 
 func (fork *blockChainFork) verifyStateAndReceipt(coming *types.Block) (bool, *account.AccountDB) {
+	if exceedGasLimit(coming.Transactions) {
+		fork.logger.Errorf("Txs exceed the block gas limit! coming:%s", coming.Header.Hash.String())
+		return false, nil
+	}
 	var height uint64 = 0
 	if coming.Header.Height > 1 {
 		height = coming.Header.Height - 1
@@ -309,6 +316,10 @@ func (fork *blockChainFork) verifyStateAndReceipt(coming *types.Block) (bool, *a
 		fork.logger.Errorf("Receipt root error!coming:%s gen:%s", coming.Header.ReceiptTree.Hex(), receiptsTree.Hex())
 		return false, state
 	}
+	if !verifyGas(coming.Header, receipts) {
+		fork.logger.Errorf("Gas error!coming gas limit:%d gas used:%d gen:%d", coming.Header.GasLimit, coming.Header.GasUsed, calcGasUsed(receipts))
+		return false, state
+	}
 	return true, state
 }
 
@@ -334,7 +345,7 @@ func (fork *blockChainFork) verifyMemberLegal(coming *types.Block) (bool, error)
 	return consensusHelper.VerifyMemberInfo(coming.Header, preBlock.Header)
 }
 
-func (fork *blockChainFork) saveState(state *account.AccountDB) error {
+func (fork *blockChainFork) saveState(state *account.AccountDB, height uint64) error {
 	if state == nil {
 		return nil
 	}
@@ -345,8 +356,7 @@ func (fork *blockChainFork) saveState(state *account.AccountDB) error {
 	}
 	fork.logger.Debugf("commit state root:%s", root.Hex())
 
-	trieDB := middleware.AccountDBManagerInstance.GetTrieDB()
-	err = trieDB.Commit(root, false)
+	err = middleware.AccountDBManagerInstance.CommitState(root, height)
 	if err != nil {
 		fork.logger.Errorf("Trie commit error:%s", err.Error())
 		return err
@@ -406,8 +416,18 @@ func (p *syncProcessor) GetBlockHash(height uint64) common.Hash {
 	return common.Hash{}
 }
 
+func (p *syncProcessor) PrevRandao(header *types.BlockHeader) common.Hash {
+	if p.blockFork != nil {
+		parent := p.blockFork.getBlockByHash(header.PreHash)
+		if parent != nil {
+			return parent.Header.RandaoMix()
+		}
+	}
+	return p.blockChain.PrevRandao(header)
+}
+
 func adaptSort(block *types.Block) *types.Block {
-	if common.IsMainnet() && block.Header.Height >= common.LocalChainConfig.Proposal024Block && block.Header.Height < common.LocalChainConfig.Proposal023Block {
+	if common.IsMainnet() && !common.LocalChainConfig.Rules(block.Header.Height).IsProposal023 && common.LocalChainConfig.Rules(block.Header.Height).IsProposal024 {
 		sort.Sort(types.Transactions(block.Transactions))
 	}
 	return block
@@ -272,7 +272,8 @@ func (executor *GameExecutor) runTransaction(accountDB *account.AccountDB, heigh
 		return false, "Tx Is Existed"
 	}
 
-	if common.IsProposal006() && !common.IsProposal007() {
+	rules := common.CurrentRules()
+	if rules.IsProposal006 && !rules.IsProposal007 {
 		accountDB.IncreaseNonce(common.HexToAddress(txRaw.Source))
 	}
 
@@ -304,13 +305,13 @@ func (executor *GameExecutor) runTransaction(accountDB *account.AccountDB, heigh
 		if !result {
 			accountDB.RevertToSnapshot(snapshot)
 		} else if txRaw.Source != "" {
-			if !common.IsProposal006() {
+			if !rules.IsProposal006 {
 				accountDB.IncreaseNonce(common.HexToAddress(txRaw.Source))
 			}
 		}
 	}
 
-	if common.IsProposal007() {
+	if rules.IsProposal007 {
 		if !(types.IsContractTx(txRaw.Type) && result) {
 			nonce := accountDB.GetNonce(common.HexToAddress(txRaw.Source))
 			accountDB.SetNonce(common.HexToAddress(txRaw.Source), nonce+1)
@@ -19,7 +19,7 @@ package core
 import (
 	"com.tuntun.rangers/node/src/common"
 	"com.tuntun.rangers/node/src/middleware"
-	"com.tuntun.rangers/node/src/middleware/mysql"
+	"com.tuntun.rangers/node/src/middleware/logindex"
 	"com.tuntun.rangers/node/src/middleware/types"
 	"com.tuntun.rangers/node/src/storage/account"
 	"com.tuntun.rangers/node/src/utility"
@@ -31,8 +31,6 @@ import (
 	"strconv"
 )
 
-const getLogsBlockLimit = 1000
-
 var limitExceededError = errors.New("limit exceeded")
 
 type queryLogData struct {
@@ -133,33 +131,42 @@ func getPastLogs(crit types.FilterCriteria) string {
 
 func GetLogs(crit types.FilterCriteria) ([]*types.Log, error) {
 	var logs []*types.Log
+	index := logindex.GetLogIndex()
 	if crit.BlockHash != nil {
-		logs = mysql.SelectLogsByHash(*crit.BlockHash, crit.Addresses)
+		logs = index.SelectLogsByHash(*crit.BlockHash, crit.Addresses, crit.Topics)
 	} else {
-		var begin, end uint64
-		if crit.FromBlock == nil || crit.FromBlock.Cmp(common.Big0) < 0 || !crit.FromBlock.IsUint64() {
-			begin = GetBlockChain().Height()
-		} else {
-			begin = crit.FromBlock.Uint64()
-		}
+		begin := resolveLogHeight(crit.FromBlock)
+		end := resolveLogHeight(crit.ToBlock)
 
-		if crit.ToBlock == nil || crit.ToBlock.Cmp(common.Big0) < 0 || !crit.ToBlock.IsUint64() {
-			end = GetBlockChain().Height()
-		} else {
-			end = crit.ToBlock.Uint64()
-		}
-
-		if end-begin > getLogsBlockLimit {
+		if end-begin > index.BlockLimit() {
 			return nil, limitExceededError
 		}
-		logs = mysql.SelectLogs(begin, end, crit.Addresses)
+		logs = index.SelectLogs(begin, end, crit.Addresses, crit.Topics)
 	}
 
-	result := types.FilterLogsByTopics(logs, crit.Topics)
-	if result == nil {
+	if logs == nil {
 		return []*types.Log{}, nil
 	}
-	return result, nil
+	return logs, nil
+}
+
+// resolveLogHeight maps a filter bound to a height, the safe and finalized
+// tags resolve against the finality depth and the others to the top block
+func resolveLogHeight(number *big.Int) uint64 {
+	if number != nil && number.Cmp(common.Big0) >= 0 && number.IsUint64() {
+		return number.Uint64()
+	}
+
+	var header *types.BlockHeader
+	if number != nil && number.Int64() == int64(types.SafeBlockNumber) {
+		header = GetBlockChain().SafeBlock()
+	} else if number != nil && number.Int64() == int64(types.FinalizedBlockNumber) {
+		header = GetBlockChain().FinalizedBlock()
+	}
+	if header == nil {
+		return GetBlockChain().Height()
+	}
+	return header.Height
 }
 
 func (executor *GameExecutor) callVM(param callVMData) string {
@@ -181,6 +188,7 @@ func (executor *GameExecutor) callVM(param callVMData) string {
 	vmCtx.Time = new(big.Int).SetUint64(uint64(block.Header.CurTime.Unix()))
 	//set constant value
 	vmCtx.Difficulty = new(big.Int).SetUint64(123)
+	vmCtx.GetRandom = func() common.Hash { return GetBlockChain().PrevRandao(block.Header) }
 	vmCtx.GasLimit = param.Gas
 	var convertError error
 	vmCtx.GasPrice, convertError = utility.StrToBigInt(param.GasPrice)
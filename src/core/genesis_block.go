@@ -174,6 +174,7 @@ func createGenesisContract(header *types.BlockHeader, statedb *account.AccountDB
 	vmCtx.CanTransfer = vm.CanTransfer
 	vmCtx.Transfer = vm.Transfer
 	vmCtx.GetHash = func(uint64) common.Hash { return emptyHash }
+	vmCtx.GetRandom = func() common.Hash { return emptyHash }
 
 	vmCtx.Origin = common.HexToAddress(source)
 	vmCtx.Coinbase = common.BytesToAddress(header.Castor)
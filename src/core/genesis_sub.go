@@ -191,6 +191,7 @@ func createEconomyContract(header *types.BlockHeader, statedb *account.AccountDB
 	vmCtx.CanTransfer = vm.CanTransfer
 	vmCtx.Transfer = vm.Transfer
 	vmCtx.GetHash = func(uint64) common.Hash { return emptyHash }
+	vmCtx.GetRandom = func() common.Hash { return emptyHash }
 
 	vmCtx.Origin = common.HexToAddress(source)
 	vmCtx.Coinbase = common.BytesToAddress(header.Castor)
@@ -246,6 +247,7 @@ func createSubCrossContract(header *types.BlockHeader, statedb *account.AccountD
 	vmCtx.CanTransfer = vm.CanTransfer
 	vmCtx.Transfer = vm.Transfer
 	vmCtx.GetHash = func(uint64) common.Hash { return emptyHash }
+	vmCtx.GetRandom = func() common.Hash { return emptyHash }
 	vmCtx.Origin = common.HexToAddress("0xCA8E4c934CF34e22b578ECe48c657f02B1053367")
 	vmCtx.Coinbase = common.BytesToAddress(header.Castor)
 	vmCtx.BlockNumber = new(big.Int).SetUint64(header.Height)
@@ -345,6 +347,7 @@ func createSubGovernance(header *types.BlockHeader, db *account.AccountDB, rpg,
 	vmCtx.CanTransfer = vm.CanTransfer
 	vmCtx.Transfer = vm.Transfer
 	vmCtx.GetHash = func(uint64) common.Hash { return emptyHash }
+	vmCtx.GetRandom = func() common.Hash { return emptyHash }
 
 	vmCtx.Origin = common.HexToAddress(source)
 	vmCtx.Coinbase = common.BytesToAddress(header.Castor)
@@ -119,6 +119,10 @@ func (chain *groupChain) refreshCache(lastGroup *types.Group) {
 }
 
 func (chain *groupChain) AddGroup(group *types.Group) error {
+	return chain.addGroup(group, true)
+}
+
+func (chain *groupChain) addGroup(group *types.Group, verify bool) error {
 	if nil == group {
 		return fmt.Errorf("nil group")
 	}
@@ -131,14 +135,16 @@ func (chain *groupChain) AddGroup(group *types.Group) error {
 		return common.ErrGroupAlreadyExist
 	}
 
-	ok, err := consensusHelper.CheckGroup(group)
-	if !ok {
-		if errors.Is(err, common.ErrCreateBlockNil) {
-			logger.Infof("Add group failed:depend on block!")
-		} else {
-			logger.Infof("Add group failed:%v", err.Error())
+	if verify {
+		ok, err := consensusHelper.CheckGroup(group)
+		if !ok {
+			if errors.Is(err, common.ErrCreateBlockNil) {
+				logger.Infof("Add group failed:depend on block!")
+			} else {
+				logger.Infof("Add group failed:%v", err.Error())
+			}
+			return err
 		}
-		return err
 	}
 
 	chain.lock.Lock()
@@ -276,22 +282,15 @@ func (chain *groupChain) remove(group *types.Group) bool {
 }
 
 func (chain *groupChain) save(group *types.Group) error {
-	group.GroupHeight = chain.count
-	data, err := json.Marshal(group)
-	if err != nil {
+	if err := writeGroup(chain.groups, group, chain.count); err != nil {
 		logger.Errorf("Marshal group error:%s", err.Error())
 		return err
 	}
-
-	chain.groups.Put(group.Id, data)
-	chain.groups.Put([]byte(lastGroupKey), group.Id)
-	chain.groups.Put(generateKey(chain.count), group.Id)
 	chain.count++
-	chain.groups.Put([]byte(groupCountKey), utility.UInt64ToByte(chain.count))
 	chain.lastGroup = group
 	logger.Debugf("Add group on chain success! Group id:%s,group pubkey:%s", hex.EncodeToString(group.Id), hex.EncodeToString(group.PubKey))
 
-	if err = mysql.InsertGroup(group); nil != err {
+	if err := mysql.InsertGroup(group); nil != err {
 		panic(err)
 	}
 	if nil != notify.BUS {
@@ -300,6 +299,21 @@ func (chain *groupChain) save(group *types.Group) error {
 	return nil
 }
 
+// writeGroup puts the group at the given group height as the last group
+func writeGroup(groups db.Database, group *types.Group, height uint64) error {
+	group.GroupHeight = height
+	data, err := json.Marshal(group)
+	if err != nil {
+		return err
+	}
+
+	groups.Put(group.Id, data)
+	groups.Put([]byte(lastGroupKey), group.Id)
+	groups.Put(generateKey(height), group.Id)
+	groups.Put([]byte(groupCountKey), utility.UInt64ToByte(height+1))
+	return nil
+}
+
 func generateKey(i uint64) []byte {
 	return intToBytes(i)
 }
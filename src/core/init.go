@@ -20,6 +20,7 @@ import (
 	"com.tuntun.rangers/node/src/common"
 	"com.tuntun.rangers/node/src/executor"
 	"com.tuntun.rangers/node/src/middleware/log"
+	"com.tuntun.rangers/node/src/middleware/metrics"
 	"com.tuntun.rangers/node/src/middleware/types"
 	"com.tuntun.rangers/node/src/service"
 	"strconv"
@@ -36,7 +37,7 @@ var (
 	consensusHelper types.ConsensusHelper
 )
 
-func InitCore(helper types.ConsensusHelper, privateKey common.PrivateKey, id string) error {
+func InitCore(helper types.ConsensusHelper, signer common.Signer, id string) error {
 	logger = log.GetLoggerByIndex(log.CoreLogConfig, strconv.Itoa(common.InstanceIndex))
 	txLogger = log.GetLoggerByIndex(log.TxLogConfig, strconv.Itoa(common.InstanceIndex))
 	syncLogger = log.GetLoggerByIndex(log.SyncLogConfig, strconv.Itoa(common.InstanceIndex))
@@ -63,16 +64,34 @@ func InitCore(helper types.ConsensusHelper, privateKey common.PrivateKey, id str
 	if nil == groupChainImpl {
 		initGroupChain()
 	}
+	blockChainImpl.updateFinalized()
 
-	InitSyncProcessor(privateKey, id)
+	InitSyncProcessor(signer, id)
 
 	executor.InitExecutors()
 	service.InitRewardCalculator(blockChainImpl, groupChainImpl, SyncProcessor)
 	service.InitRefundManager(groupChainImpl, SyncProcessor)
+	service.InitEvidenceManager(groupChainImpl, SyncProcessor)
 
 	initChainHandler()
 
 	initGameExecutor(blockChainImpl)
 
+	initPendingState(blockChainImpl)
+
+	initMetrics()
+
 	return nil
 }
+
+func initMetrics() {
+	metrics.NewGaugeFunc("chain_height", "Height of the local top block", func() float64 {
+		return float64(blockChainImpl.Height())
+	})
+	metrics.NewGaugeFunc("chain_candidate_height", "Height of the sync candidate", func() float64 {
+		return float64(GetCandidateHeight())
+	})
+	metrics.NewGaugeFunc("bad_peers", "Number of the peers marked evil", func() float64 {
+		return float64(PeerManager.count())
+	})
+}
@@ -19,6 +19,7 @@ package core
 import (
 	"com.tuntun.rangers/node/src/common"
 	"com.tuntun.rangers/node/src/middleware/types"
+	"com.tuntun.rangers/node/src/vm"
 	"math/big"
 	"time"
 )
@@ -32,6 +33,10 @@ type GroupForkIterator struct {
 }
 
 func GetBlockChain() BlockChain {
+	// avoid the non nil interface holding a nil pointer before the chain is initialized
+	if nil == blockChainImpl {
+		return nil
+	}
 	return blockChainImpl
 }
 
@@ -54,6 +59,11 @@ type BlockChain interface {
 
 	TopBlock() *types.BlockHeader
 
+	// SafeBlock is unlikely to be reorged, FinalizedBlock can no longer be reorged
+	SafeBlock() *types.BlockHeader
+
+	FinalizedBlock() *types.BlockHeader
+
 	QueryBlockByHash(hash common.Hash) *types.Block
 
 	QueryBlock(height uint64) *types.Block
@@ -71,6 +81,10 @@ type BlockChain interface {
 	HasBlockByHash(hash common.Hash) bool
 
 	GetBlockHash(height uint64) common.Hash
+
+	PrevRandao(header *types.BlockHeader) common.Hash
+
+	TraceTransaction(blockHash common.Hash, txHash common.Hash, tracer vm.Tracer) (*types.Receipt, error)
 }
 
 type GroupChain interface {
@@ -0,0 +1,118 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/middleware"
+	"com.tuntun.rangers/node/src/middleware/notify"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"com.tuntun.rangers/node/src/storage/account"
+	"com.tuntun.rangers/node/src/utility"
+	"sync"
+)
+
+var pendingStateImpl *pendingState
+
+// pendingState keeps the state after the executable txs in the pool are applied on top of the latest block.
+// It is rebuilt when a block is added on chain or a tx is added into the pool.
+type pendingState struct {
+	chain *blockChain
+
+	lock   sync.RWMutex
+	header *types.BlockHeader
+	state  *account.AccountDB
+
+	// buffered by one, notifications coming during a rebuild are merged into the next one
+	refresh chan struct{}
+}
+
+func initPendingState(chain *blockChain) {
+	pendingStateImpl = &pendingState{chain: chain, refresh: make(chan struct{}, 1)}
+	notify.BUS.Subscribe(notify.BlockAddSucc, pendingStateImpl)
+	notify.BUS.Subscribe(notify.NewPendingTxNotify, pendingStateImpl)
+	go pendingStateImpl.loop()
+	pendingStateImpl.trigger()
+}
+
+// GetPendingState returns the header of the pending block and a copy of the pending state.
+// Both are nil before the pending state is built.
+func GetPendingState() (*types.BlockHeader, *account.AccountDB) {
+	if nil == pendingStateImpl {
+		return nil, nil
+	}
+	pendingStateImpl.lock.RLock()
+	defer pendingStateImpl.lock.RUnlock()
+
+	if nil == pendingStateImpl.state {
+		return nil, nil
+	}
+	return pendingStateImpl.header, pendingStateImpl.state.Copy()
+}
+
+func (p *pendingState) HandleNetMessage(topic string, msg notify.Message) {
+	p.trigger()
+}
+
+func (p *pendingState) trigger() {
+	select {
+	case p.refresh <- struct{}{}:
+	default:
+	}
+}
+
+func (p *pendingState) loop() {
+	for range p.refresh {
+		p.update()
+	}
+}
+
+func (p *pendingState) update() {
+	top := p.chain.TopBlock()
+	if nil == top {
+		return
+	}
+	state, err := middleware.AccountDBManagerInstance.GetAccountDBByHash(top.StateTree)
+	if err != nil {
+		logger.Errorf("fail to build pending state, height: %d, err: %s", top.Height, err)
+		return
+	}
+
+	header := &types.BlockHeader{
+		CurTime: utility.GetTime(),
+		Height:  top.Height + 1,
+		Castor:  top.Castor,
+		GroupId: top.GroupId,
+		PreHash: top.Hash,
+		PreTime: top.CurTime,
+	}
+	if common.CurrentRules().IsProposal029 {
+		header.GasLimit = common.BlockGasLimit
+	}
+	txs := p.chain.transactionPool.PackForCast(header.Height, state)
+	block := &types.Block{Header: header, Transactions: txs}
+	if 0 != len(txs) {
+		_, _, _, receipts := newVMExecutor(state, block, "pending").Execute()
+		header.GasUsed = calcGasUsed(receipts)
+	}
+
+	p.lock.Lock()
+	p.header = header
+	p.state = state
+	p.lock.Unlock()
+	logger.Debugf("pending state updated, height: %d, txs: %d", header.Height, len(txs))
+}
@@ -137,6 +137,11 @@ func ConvertBlockByHeader(bh *types.BlockHeader) *RPCBlock {
 		Bloom:        "0x00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
 	}
 
+	if 0 != bh.GasLimit {
+		block.GasLimit = utility.Uint64(bh.GasLimit)
+		block.GasUsed = utility.Uint64(bh.GasUsed)
+	}
+
 	totalReward := service.GetTotalReward(bh.Height)
 	block.TotalReward = strconv.FormatFloat(totalReward, 'f', -1, 64)
 
@@ -72,7 +72,7 @@ type groupMsgResponse struct {
 
 func (p *syncProcessor) requestBlockChainPiece(targetNode string, reqHeight uint64) {
 	req := blockChainPieceReq{Height: reqHeight}
-	req.SignInfo = common.NewSignData(p.privateKey, p.id, &req)
+	req.SignInfo = common.NewSignData(p.signer, p.id, &req)
 
 	body, e := marshalBlockChainPieceReq(req)
 	if e != nil {
@@ -106,7 +106,7 @@ func (p *syncProcessor) blockChainPieceReqHandler(m notify.Message) {
 	syncHandleLogger.Debugf("Rcv block chain piece req from:%s,source height:%d", from, chainPieceReq.Height)
 	chainPiece := p.blockChain.getChainPiece(chainPieceReq.Height)
 	chainPieceMsg := blockChainPiece{ChainPiece: chainPiece, TopHeader: p.blockChain.TopBlock()}
-	chainPieceMsg.SignInfo = common.NewSignData(p.privateKey, p.id, &chainPieceMsg)
+	chainPieceMsg.SignInfo = common.NewSignData(p.signer, p.id, &chainPieceMsg)
 
 	if len(chainPiece) > 0 {
 		syncHandleLogger.Debugf("Send chain piece %d-%d to:%s", chainPiece[0].Height, chainPiece[len(chainPiece)-1].Height, from)
@@ -241,7 +241,7 @@ func (p *syncProcessor) syncBlock(id string, commonAncestor types.BlockHeader) {
 	syncHeight := commonAncestor.Height + 1
 	p.logger.Debugf("Sync block from:%s,reqHeight:%d", id, syncHeight)
 	req := blockSyncReq{Height: syncHeight}
-	req.SignInfo = common.NewSignData(p.privateKey, p.id, &req)
+	req.SignInfo = common.NewSignData(p.signer, p.id, &req)
 
 	body, e := marshalBlockSyncReq(req)
 	if e != nil {
@@ -279,7 +279,7 @@ func (p *syncProcessor) syncBlockReqHandler(msg notify.Message) {
 		isLastBlock = true
 	}
 	response := blockMsgResponse{Block: block, IsLastBlock: isLastBlock}
-	response.SignInfo = common.NewSignData(p.privateKey, p.id, &response)
+	response.SignInfo = common.NewSignData(p.signer, p.id, &response)
 	body, e := marshalBlockMsgResponse(response)
 	if e != nil {
 		syncHandleLogger.Errorf("Marshal block msg response error:%s", e.Error())
@@ -344,7 +344,7 @@ func (p *syncProcessor) syncGroup(id string, commonAncestor *types.Group) {
 	syncHeight := commonAncestor.GroupHeight + 1
 	p.logger.Debugf("Sync group from:%s,reqHeight:%d", id, syncHeight)
 	req := groupSyncReq{Height: syncHeight}
-	req.SignInfo = common.NewSignData(p.privateKey, p.id, &req)
+	req.SignInfo = common.NewSignData(p.signer, p.id, &req)
 
 	body, e := marshalGroupSyncReq(req)
 	if e != nil {
@@ -383,7 +383,7 @@ func (p *syncProcessor) syncGroupReqHandler(msg notify.Message) {
 		isLastGroup = true
 	}
 	response := groupMsgResponse{Group: group, IsLastGroup: isLastGroup}
-	response.SignInfo = common.NewSignData(p.privateKey, p.id, &response)
+	response.SignInfo = common.NewSignData(p.signer, p.id, &response)
 	body, e := marshalGroupMsgResponse(response)
 	if e != nil {
 		syncHandleLogger.Errorf("Marshal group msg response error:%s", e.Error())
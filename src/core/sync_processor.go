@@ -55,8 +55,8 @@ type CandidateInfo struct {
 var SyncProcessor *syncProcessor
 
 type syncProcessor struct {
-	privateKey common.PrivateKey
-	id         string
+	signer common.Signer
+	id     string
 
 	candidateInfo CandidateInfo
 	candidatePool map[string]chainInfo
@@ -64,6 +64,10 @@ type syncProcessor struct {
 	canSync bool
 	syncing bool
 
+	// the local height when the node began catching up with syncTarget, for eth_syncing
+	startingHeight uint64
+	syncTarget     uint64
+
 	syncTimer      *time.Timer
 	blockReqTimer  *time.Timer
 	groupReqTimer  *time.Timer
@@ -79,8 +83,8 @@ type syncProcessor struct {
 	logger log.Logger
 }
 
-func InitSyncProcessor(privateKey common.PrivateKey, id string) {
-	SyncProcessor = &syncProcessor{privateKey: privateKey, id: id, syncing: false, candidatePool: make(map[string]chainInfo)}
+func InitSyncProcessor(signer common.Signer, id string) {
+	SyncProcessor = &syncProcessor{signer: signer, id: id, syncing: false, candidatePool: make(map[string]chainInfo)}
 
 	SyncProcessor.broadcastTimer = time.NewTimer(broadcastBlockInfoInterval)
 	SyncProcessor.blockReqTimer = time.NewTimer(syncReqTimeout)
@@ -160,7 +164,7 @@ func (p *syncProcessor) broadcastChainInfo(bh *types.BlockHeader) {
 		return
 	}
 	topBlockInfo := chainInfo{TopBlockHash: bh.Hash, TotalQn: bh.TotalQN, TopBlockHeight: bh.Height, PreHash: bh.PreHash, TopGroupHeight: p.groupChain.height()}
-	topBlockInfo.SignInfo = common.NewSignData(p.privateKey, p.id, &topBlockInfo)
+	topBlockInfo.SignInfo = common.NewSignData(p.signer, p.id, &topBlockInfo)
 
 	body, e := marshalChainInfo(topBlockInfo)
 	if e != nil {
@@ -255,6 +259,13 @@ func (p *syncProcessor) trySync() {
 
 	p.syncing = true
 	p.candidateInfo = candidateInfo
+	// a new catch up begins once the last target is reached
+	if localBlockHeight >= p.syncTarget {
+		p.startingHeight = localBlockHeight
+	}
+	if candidateInfo.Height > p.syncTarget {
+		p.syncTarget = candidateInfo.Height
+	}
 	p.logger.Debugf("Begin sync!")
 	p.logger.Debugf("Candidate info:%s,%d-%d,%d", candidateInfo.Id, candidateInfo.Height, candidateInfo.TotalQn, candidateInfo.GroupHeight)
 	p.logger.Debugf("Local info:%d-%d,%d", localBlockHeight, localTotalQN, localGroupHeight)
@@ -410,6 +421,34 @@ func (p *syncProcessor) finishCurrentSync(syncResult bool) {
 	p.logger.Debugf("finish current sync:%v", syncResult)
 }
 
+// SyncProgress is the progress of catching up with the highest candidate
+type SyncProgress struct {
+	StartingBlock uint64
+	CurrentBlock  uint64
+	HighestBlock  uint64
+}
+
+// GetSyncProgress returns the progress and whether the local chain is behind the candidates
+func GetSyncProgress() (SyncProgress, bool) {
+	if nil == SyncProcessor || nil == blockChainImpl {
+		return SyncProgress{}, false
+	}
+	return SyncProcessor.getSyncProgress()
+}
+
+func (p *syncProcessor) getSyncProgress() (SyncProgress, bool) {
+	p.lock.RLock("getSyncProgress")
+	defer p.lock.RUnlock("getSyncProgress")
+
+	progress := SyncProgress{StartingBlock: p.startingHeight, CurrentBlock: p.blockChain.Height(), HighestBlock: p.candidateInfo.Height}
+	for _, chainInfo := range p.candidatePool {
+		if chainInfo.TopBlockHeight > progress.HighestBlock {
+			progress.HighestBlock = chainInfo.TopBlockHeight
+		}
+	}
+	return progress, progress.HighestBlock > progress.CurrentBlock
+}
+
 func GetCandidateHeight() uint64 {
 	if SyncProcessor != nil {
 		return SyncProcessor.candidateInfo.Height
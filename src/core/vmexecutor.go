@@ -20,10 +20,12 @@ import (
 	"com.tuntun.rangers/node/src/common"
 	"com.tuntun.rangers/node/src/executor"
 	"com.tuntun.rangers/node/src/middleware"
+	"com.tuntun.rangers/node/src/middleware/metrics"
 	"com.tuntun.rangers/node/src/middleware/types"
 	"com.tuntun.rangers/node/src/service"
 	"com.tuntun.rangers/node/src/storage/account"
 	"com.tuntun.rangers/node/src/utility"
+	"com.tuntun.rangers/node/src/vm"
 	"math/big"
 	"sort"
 	"strings"
@@ -32,12 +34,18 @@ import (
 
 const MaxCastBlockTime = time.Second * 3
 
+var blockExecuteTimer = metrics.NewHistogramVec("block_execute_seconds", "Time of executing the transactions of a block", metrics.DefBuckets, "situation")
+
 type VMExecutor struct {
 	accountdb *account.AccountDB
 	block     *types.Block
 	situation string
 	context   map[string]interface{}
 	mode      bool
+
+	// tracer is attached to the execution of traceTx only
+	tracer  vm.Tracer
+	traceTx common.Hash
 }
 
 func newVMExecutor(accountdb *account.AccountDB, block *types.Block, situation string) *VMExecutor {
@@ -56,6 +64,8 @@ func newVMExecutor(accountdb *account.AccountDB, block *types.Block, situation s
 }
 
 func (this *VMExecutor) Execute() (common.Hash, []common.Hash, []*types.Transaction, []*types.Receipt) {
+	defer blockExecuteTimer.WithLabelValues(this.situation).UpdateSince(time.Now())
+
 	var beginTime time.Time
 	if this.situation == "casting" {
 		beginTime = utility.GetTime()
@@ -71,13 +81,16 @@ func (this *VMExecutor) Execute() (common.Hash, []common.Hash, []*types.Transact
 		sort.Sort(txs)
 	}
 
+	// the rules of the chain top, kept for the whole block
+	rules := common.CurrentRules()
 	i := 0
+	var cumulativeGasUsed uint64
 	for _, transaction := range txs {
 		if 0 == transaction.Type {
 			continue
 		}
 
-		if common.IsProposal013() {
+		if rules.IsProposal013 {
 			this.accountdb.Prepare(transaction.Hash, common.Hash{}, i)
 		}
 
@@ -87,10 +100,14 @@ func (this *VMExecutor) Execute() (common.Hash, []common.Hash, []*types.Transact
 		}
 		logger.Debugf("Execute %s, type:%d", transaction.Hash.String(), transaction.Type)
 
-		if common.IsProposal006() && !common.IsProposal007() {
+		if rules.IsProposal006 && !rules.IsProposal007 {
 			this.accountdb.IncreaseNonce(common.HexToAddress(transaction.Source))
 		}
 
+		if this.tracer != nil && transaction.Hash == this.traceTx {
+			this.context["tracer"] = this.tracer
+		}
+
 		txExecutor := executor.GetTxExecutor(transaction.Type)
 		success := false
 		addAble := true
@@ -98,7 +115,7 @@ func (this *VMExecutor) Execute() (common.Hash, []common.Hash, []*types.Transact
 
 		if txExecutor != nil {
 			success, addAble, msg = txExecutor.BeforeExecute(transaction, this.block.Header, this.accountdb, this.context)
-			if common.IsProposal018() && !addAble {
+			if rules.IsProposal018 && !addAble {
 				evictedTxs = append(evictedTxs, transaction.Hash)
 				logger.Infof("Tx not addAble,skip.Hash:%s,msg:%s", transaction.Hash.String(), msg)
 				continue
@@ -110,18 +127,18 @@ func (this *VMExecutor) Execute() (common.Hash, []common.Hash, []*types.Transact
 
 				if !success {
 					logger.Debugf("Execute failed tx: %s, type: %d, msg: %s", transaction.Hash.String(), transaction.Type, msg)
-					if !common.IsProposal018() {
+					if !rules.IsProposal018 {
 						evictedTxs = append(evictedTxs, transaction.Hash)
 					}
 					this.accountdb.RevertToSnapshot(snapshot)
-					if common.IsProposal027() && types.IsContractTx(transaction.Type) {
+					if rules.IsProposal027 && types.IsContractTx(transaction.Type) {
 						if gasUsed := this.context["gasUsed"]; gasUsed != nil {
 							deductGasFee(gasUsed.(uint64), transaction.Source, this.accountdb, transaction.Hash)
 						}
 					}
 				} else {
 					if transaction.Source != "" {
-						if !common.IsProposal006() {
+						if !rules.IsProposal006 {
 							this.accountdb.IncreaseNonce(common.HexToAddress(transaction.Source))
 						}
 					}
@@ -129,7 +146,7 @@ func (this *VMExecutor) Execute() (common.Hash, []common.Hash, []*types.Transact
 					logger.Debugf("Execute success, txhash: %s, type: %d", transaction.Hash.String(), transaction.Type)
 				}
 			}
-			if common.IsProposal007() {
+			if rules.IsProposal007 {
 				if !(types.IsContractTx(transaction.Type) && success) {
 					nonce := this.accountdb.GetNonce(common.HexToAddress(transaction.Source))
 					this.accountdb.SetNonce(common.HexToAddress(transaction.Source), nonce+1)
@@ -137,10 +154,11 @@ func (this *VMExecutor) Execute() (common.Hash, []common.Hash, []*types.Transact
 			}
 		}
 
+		delete(this.context, "tracer")
 		transactions = append(transactions, transaction)
 
 		receipt := types.NewReceipt(nil, !success, 0, this.block.Header.Height, msg, transaction.Source, "")
-		if common.IsProposal013() {
+		if rules.IsProposal013 {
 			receipt.Logs = this.accountdb.GetLogs(transaction.Hash)
 		} else {
 			logs := this.context["logs"]
@@ -159,19 +177,24 @@ func (this *VMExecutor) Execute() (common.Hash, []common.Hash, []*types.Transact
 		}
 
 		gasUsed := this.context["gasUsed"]
-		if gasUsed != nil && common.IsProposal015() {
+		if gasUsed != nil && rules.IsProposal015 {
 			receipt.GasUsed = gasUsed.(uint64)
 		}
+		if rules.IsProposal029 {
+			delete(this.context, "gasUsed")
+			cumulativeGasUsed += receipt.GasUsed
+			receipt.CumulativeGasUsed = cumulativeGasUsed
+		}
 		receipt.TxHash = transaction.Hash
 		receipts = append(receipts, receipt)
 		i++
 	}
 
 	//only for robin
-	if this.block.Header.Height == common.LocalChainConfig.Proposal010Block {
+	if this.block.Header.Height == common.LocalChainConfig.Forks[common.Proposal010] {
 		removeUnusedValidator(this.accountdb)
 	}
-	if this.block.Header.Height == common.LocalChainConfig.Proposal019Block {
+	if this.block.Header.Height == common.LocalChainConfig.Forks[common.Proposal019] {
 		removeUnusedValidator1(this.accountdb)
 	}
 	this.after()
@@ -187,7 +210,7 @@ func (executor *VMExecutor) prepare() {
 }
 
 func (executor *VMExecutor) after() {
-	if 0 == strings.Compare("testing", executor.situation) {
+	if 0 == strings.Compare("testing", executor.situation) || 0 == strings.Compare("tracing", executor.situation) || 0 == strings.Compare("pending", executor.situation) {
 		return
 	}
 
@@ -205,15 +228,18 @@ func (executor *VMExecutor) after() {
 	}
 
 	service.RefundManagerImpl.CheckAndMove(height, executor.accountdb)
-	if common.LocalChainConfig.Proposal004Block == height {
+	if common.LocalChainConfig.Forks[common.Proposal004] == height {
 		service.RefundManagerImpl.CheckAndMove(0, executor.accountdb)
 	}
 
+	if common.LocalChainConfig.Rules(height).IsProposal030 {
+		service.EvidenceManagerImpl.CountBlock(executor.block.Header, executor.accountdb)
+	}
 }
 
 func (executor *VMExecutor) calcDifficulty() {
 	height := executor.block.Header.Height
-	if height < common.LocalChainConfig.Proposal025Block {
+	if !common.LocalChainConfig.Rules(height).IsProposal025 {
 		return
 	}
 
@@ -232,7 +258,7 @@ func (executor *VMExecutor) calcDifficulty() {
 	executor.accountdb.SetData(common.DifficultyAddress, executor.block.Header.Castor, utility.UInt64ToByte(value))
 	logger.Infof("height: %d, add difficulty, %s, %d", height, common.ToHex(executor.block.Header.Castor), value)
 
-	if height < common.LocalChainConfig.Proposal025Block+common.GetRewardBlocks() {
+	if height < common.LocalChainConfig.Forks[common.Proposal025]+common.GetRewardBlocks() {
 		return
 	}
 
@@ -58,6 +58,7 @@ func (executor *VMExecutor) calcSubCoinReward(proposals, validators map[string]c
 	vmCtx.CanTransfer = vm.CanTransfer
 	vmCtx.Transfer = transfer
 	vmCtx.GetHash = func(uint64) common.Hash { return emptyHash }
+	vmCtx.GetRandom = executor.prevRandao
 	vmCtx.Origin = callerAddress
 	vmCtx.Coinbase = common.BytesToAddress(header.Castor)
 	vmCtx.BlockNumber = new(big.Int).SetUint64(header.Height)
@@ -102,6 +103,7 @@ func (executor *VMExecutor) calcSubRPGReward(proposals, validators map[string]co
 	vmCtx.CanTransfer = vm.CanTransfer
 	vmCtx.Transfer = transfer
 	vmCtx.GetHash = func(uint64) common.Hash { return emptyHash }
+	vmCtx.GetRandom = executor.prevRandao
 	vmCtx.Origin = callerAddress
 	vmCtx.Coinbase = common.BytesToAddress(header.Castor)
 	vmCtx.BlockNumber = new(big.Int).SetUint64(header.Height)
@@ -148,3 +150,11 @@ func transfer(db vm.StateDB, sender, recipient common.Address, amount *big.Int)
 	db.SubBalance(sender, amount)
 	db.AddBalance(recipient, amount)
 }
+
+// prevRandao returns the PREVRANDAO of the block, from the fork blocks while syncing them
+func (executor *VMExecutor) prevRandao() common.Hash {
+	if executor.situation == "fork" {
+		return SyncProcessor.PrevRandao(executor.block.Header)
+	}
+	return blockChainImpl.PrevRandao(executor.block.Header)
+}
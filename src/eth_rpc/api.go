@@ -26,9 +26,9 @@ import (
 	"com.tuntun.rangers/node/src/network"
 	"com.tuntun.rangers/node/src/service"
 	"com.tuntun.rangers/node/src/storage/account"
-	"com.tuntun.rangers/node/src/storage/rlp"
 	"com.tuntun.rangers/node/src/utility"
 	"com.tuntun.rangers/node/src/vm"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -49,6 +49,8 @@ type CallArgs struct {
 	Value    *utility.Big    `json:"value"`
 	Data     *utility.Bytes  `json:"data"`
 	Input    *utility.Bytes  `json:"input"`
+
+	AccessList *types.AccessList `json:"accessList,omitempty"`
 }
 
 // data retrieves the transaction calldata. Input field is preferred.
@@ -62,6 +64,14 @@ func (args *CallArgs) data() []byte {
 	return nil
 }
 
+// accessList retrieves the transaction access list, nil if not given.
+func (args *CallArgs) accessList() types.AccessList {
+	if args.AccessList != nil {
+		return *args.AccessList
+	}
+	return nil
+}
+
 // SendTxArgs represents the arguments to sumbit a new transaction into the transaction pool.
 type SendTxArgs struct {
 	From     common.Address  `json:"from"`
@@ -89,9 +99,15 @@ type RPCTransaction struct {
 	To               *common.Address `json:"to"`
 	TransactionIndex *utility.Uint64 `json:"transactionIndex"`
 	Value            *utility.Big    `json:"value"`
+	Type             utility.Uint64  `json:"type"`
 	V                *utility.Big    `json:"v"`
 	R                *utility.Big    `json:"r"`
 	S                *utility.Big    `json:"s"`
+
+	Accesses  *types.AccessList `json:"accessList,omitempty"`
+	ChainID   *utility.Big      `json:"chainId,omitempty"`
+	GasFeeCap *utility.Big      `json:"maxFeePerGas,omitempty"`
+	GasTipCap *utility.Big      `json:"maxPriorityFeePerGas,omitempty"`
 }
 
 type RPCBlock struct {
@@ -117,6 +133,39 @@ type RPCBlock struct {
 	Uncles           []string       `json:"uncles"`
 }
 
+// FeeHistoryResult is the fee market history of eth_feeHistory.
+type FeeHistoryResult struct {
+	OldestBlock  *utility.Big     `json:"oldestBlock"`
+	Reward       [][]*utility.Big `json:"reward,omitempty"`
+	BaseFee      []*utility.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+// SyncingResult is the progress of eth_syncing while the node catches up.
+type SyncingResult struct {
+	StartingBlock utility.Uint64 `json:"startingBlock"`
+	CurrentBlock  utility.Uint64 `json:"currentBlock"`
+	HighestBlock  utility.Uint64 `json:"highestBlock"`
+}
+
+// AccountResult is the EIP-1186 proof of an account and some of its storage slots.
+type AccountResult struct {
+	Address      common.Address  `json:"address"`
+	AccountProof []string        `json:"accountProof"`
+	Balance      *utility.Big    `json:"balance"`
+	CodeHash     common.Hash     `json:"codeHash"`
+	Nonce        utility.Uint64  `json:"nonce"`
+	StorageHash  common.Hash     `json:"storageHash"`
+	StorageProof []StorageResult `json:"storageProof"`
+}
+
+// StorageResult is the EIP-1186 proof of a single storage slot.
+type StorageResult struct {
+	Key   string       `json:"key"`
+	Value *utility.Big `json:"value"`
+	Proof []string     `json:"proof"`
+}
+
 //test
 
 // OverrideAccount indicates the overriding fields of account during the execution
@@ -176,12 +225,11 @@ func (diff *StateOverride) Apply(state *account.AccountDB) error {
 }
 
 const (
-	gasLimit                  uint64 = 900000000
-	confirmBlockCount         uint64 = 3
-	txGas                     uint64 = 21000 // Per transaction not creating a contract. NOTE: Not payable on data of calls between transactions.
-	estimateExpandCoefficient        = 2.5
-	generalEstimateGas        uint64 = 500000
-	MaxInitCodeSize                  = 2 * 24576 // Maximum initcode to permit in a creation transaction and create instructions
+	gasLimit          uint64 = 900000000
+	confirmBlockCount uint64 = 3
+	txGas             uint64 = 21000     // Per transaction not creating a contract. NOTE: Not payable on data of calls between transactions.
+	maxFeeHistory            = 1024      // Maximum blocks of a fee history request
+	MaxInitCodeSize          = 2 * 24576 // Maximum initcode to permit in a creation transaction and create instructions
 )
 
 var (
@@ -210,10 +258,14 @@ var (
 // The sender is responsible for signing the transaction and using the correct nonce.
 func (api *EthAPIService) SendRawTransaction(encodedTx utility.Bytes) (*types.Transaction, error) {
 	tx := new(eth_tx.Transaction)
-	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+	if err := tx.UnmarshalBinary(encodedTx); err != nil {
 		return nil, err
 	}
 	logger.Debugf("raw tx hash:%v", tx.Hash().String())
+	// typed transactions are accepted since Proposal031
+	if tx.Type() != eth_tx.LegacyTxType && !common.CurrentRules().IsProposal031 {
+		return nil, eth_tx.ErrTxTypeNotSupported
+	}
 
 	sender, err := validateTx(tx)
 	if err != nil {
@@ -236,54 +288,159 @@ func (api *EthAPIService) SendRawTransaction(encodedTx utility.Bytes) (*types.Tr
 
 // Note, this function doesn't make and changes in the state/blockchain and is
 // useful to execute and retrieve values.
-func (s *EthAPIService) Call(args CallArgs, blockNrOrHash BlockNumberOrHash, overrides *StateOverride) (utility.Bytes, error) {
+func (s *EthAPIService) Call(ctx context.Context, args CallArgs, blockNrOrHash BlockNumberOrHash, overrides *StateOverride) (utility.Bytes, error) {
 	if args.Gas == nil || uint64(*args.Gas) > gasLimit {
 		defaultGasLimit := utility.Uint64(gasLimit)
 		args.Gas = &defaultGasLimit
 	}
-	data, err, _ := doCall(args, blockNrOrHash, overrides)
+	data, err, _ := doCall(ctx, args, blockNrOrHash, overrides)
 	return data, err
 }
 
-func (s *EthAPIService) EstimateGas(args CallArgs, blockNrOrHash *BlockNumberOrHash, overrides *StateOverride) (utility.Uint64, error) {
+// EstimateGas returns the lowest gas limit allowing the transaction to succeed, found by a binary search
+// between the intrinsic gas and the block gas limit. The upper bound is capped by the balance of the caller.
+func (s *EthAPIService) EstimateGas(ctx context.Context, args CallArgs, blockNrOrHash *BlockNumberOrHash, overrides *StateOverride) (utility.Uint64, error) {
 	bNrOrHash := BlockNumberOrHashWithNumber(LatestBlockNumber)
 	if blockNrOrHash != nil {
 		bNrOrHash = *blockNrOrHash
 	}
 
-	if args.Gas == nil || uint64(*args.Gas) < txGas || uint64(*args.Gas) > gasLimit {
-		defaultGasLimit := utility.Uint64(gasLimit)
-		args.Gas = &defaultGasLimit
+	accountdb, err := getAccountDBByHashOrHeight(bNrOrHash)
+	if err != nil {
+		return 0, err
+	}
+	if err := overrides.Apply(accountdb); err != nil {
+		return 0, err
+	}
+	return estimateGas(args, accountdb, func(args CallArgs) (*callResult, error) {
+		return applyCall(ctx, args, bNrOrHash, overrides, nil)
+	})
+}
+
+// estimateGas searches the gas limit of args on accountdb, call executes args with the gas limit tried.
+func estimateGas(args CallArgs, accountdb *account.AccountDB, call func(args CallArgs) (*callResult, error)) (utility.Uint64, error) {
+	intrinsicGas, err := executor.IntrinsicGas(args.data(), args.accessList(), args.To == nil)
+	if err != nil {
+		return 0, err
+	}
+	var (
+		lo = intrinsicGas - 1
+		hi = gasLimit
+	)
+	if args.Gas != nil && uint64(*args.Gas) >= intrinsicGas && uint64(*args.Gas) < gasLimit {
+		hi = uint64(*args.Gas)
 	}
-	_, err, gasUsed := doCall(args, bNrOrHash, overrides)
 
-	//transfer tx
-	if (args.data() == nil || len(args.data()) == 0) && gasUsed == txGas*common.GasMagnification {
-		return utility.Uint64(gasUsed), err
+	// plain transfer costs the intrinsic gas only
+	if args.To != nil && 0 == len(args.data()) && 0 == len(accountdb.GetCode(*args.To)) {
+		return utility.Uint64(intrinsicGas), nil
 	}
 
-	estimateGas := uint64(float64(gasUsed) * estimateExpandCoefficient)
-	if gasUsed != txGas && estimateGas < generalEstimateGas {
-		estimateGas = generalEstimateGas
+	// cap the gas by the balance of the caller
+	if args.From != nil {
+		price := gasPrice
+		if args.GasPrice != nil && 0 != args.GasPrice.ToInt().BitLen() {
+			price = args.GasPrice.ToInt()
+		}
+		available := new(big.Int).Set(accountdb.GetBalance(*args.From))
+		if args.Value != nil {
+			if args.Value.ToInt().Cmp(available) >= 0 {
+				return 0, errors.New("insufficient funds for transfer")
+			}
+			available.Sub(available, args.Value.ToInt())
+		}
+		allowance := new(big.Int).Div(available, price)
+		if allowance.IsUint64() && hi > allowance.Uint64() {
+			logger.Debugf("gas estimation capped by funds, balance: %s, value: %v, price: %s, allowance: %d", accountdb.GetBalance(*args.From), args.Value, price, allowance.Uint64())
+			hi = allowance.Uint64()
+		}
 	}
-	if estimateGas > gasLimit {
-		estimateGas = gasLimit
+	if hi < intrinsicGas {
+		return 0, fmt.Errorf("gas required exceeds allowance (%d)", hi)
 	}
-	return utility.Uint64(estimateGas), err
+	gasCap := hi
+
+	executable := func(gas uint64) (bool, *callResult, error) {
+		args.Gas = (*utility.Uint64)(&gas)
+		result, err := call(args)
+		if err != nil {
+			return true, nil, err
+		}
+		return result.err != nil, result, nil
+	}
+	for lo+1 < hi {
+		mid := lo + (hi-lo)/2
+		failed, _, err := executable(mid)
+		if err != nil {
+			return 0, err
+		}
+		if failed {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	// the transaction fails with the highest gas allowed
+	if hi == gasCap {
+		failed, result, err := executable(hi)
+		if err != nil {
+			return 0, err
+		}
+		if failed {
+			if result.err != vm.ErrOutOfGas {
+				if result.err == vm.ErrExecutionReverted && len(result.returnData) > 0 {
+					return 0, &revertError{adaptErrorOutput(result.err, result.returnData), common.ToHex(result.returnData)}
+				}
+				return 0, result.err
+			}
+			return 0, fmt.Errorf("gas required exceeds allowance (%d)", gasCap)
+		}
+	}
+	return utility.Uint64(hi), nil
 }
 
-func doCall(args CallArgs, blockNrOrHash BlockNumberOrHash, overrides *StateOverride) (utility.Bytes, error, uint64) {
+// callResult is the outcome of a message call executed by applyCall.
+type callResult struct {
+	usedGas    uint64 // total used gas but include the refunded gas
+	err        error  // any error encountered during the execution(listed in vm/errors.go)
+	returnData []byte // returned data from evm(function result or data supplied with revert opcode)
+}
+
+func doCall(ctx context.Context, args CallArgs, blockNrOrHash BlockNumberOrHash, overrides *StateOverride) (utility.Bytes, error, uint64) {
+	result, err := applyCall(ctx, args, blockNrOrHash, overrides, nil)
+	if err != nil {
+		return nil, err, 0
+	}
+	// If the result contains a revert reason, try to unpack and return it.
+	if result.err == vm.ErrExecutionReverted && len(result.returnData) > 0 {
+		err := adaptErrorOutput(result.err, result.returnData)
+		return nil, &revertError{err, common.ToHex(result.returnData)}, result.usedGas
+	}
+	if result.err != nil {
+		return nil, result.err, result.usedGas
+	}
+	return result.returnData, nil, result.usedGas
+}
+
+// applyCall executes args on the state of the given block. The returned error is
+// not nil only if the call can not be executed at all, the vm error is kept in callResult.
+// If tracer is not nil, the execution is reported to it. The execution is cancelled when ctx is done.
+func applyCall(ctx context.Context, args CallArgs, blockNrOrHash BlockNumberOrHash, overrides *StateOverride, tracer vm.Tracer) (*callResult, error) {
 	number, _ := blockNrOrHash.Number()
 	logger.Debugf("doCall:%v,%v", args, number)
-	accountdb := getAccountDBByHashOrHeight(blockNrOrHash)
-	block := getBlockByHashOrHeight(blockNrOrHash)
-	if accountdb == nil || block == nil {
-		return nil, errors.New("param invalid"), 0
+	accountdb, header, err := getStateAndHeader(blockNrOrHash)
+	if err != nil {
+		return nil, err
 	}
 	if err := overrides.Apply(accountdb); err != nil {
-		return nil, err, 0
+		return nil, err
 	}
+	return executeCall(ctx, args, accountdb, header, tracer)
+}
 
+// executeCall executes args on accountdb in the context of header.
+func executeCall(ctx context.Context, args CallArgs, accountdb *account.AccountDB, header *types.BlockHeader, tracer vm.Tracer) (*callResult, error) {
 	initialGas := uint64(*args.Gas)
 	var contractCreation = false
 	if args.To == nil {
@@ -293,29 +450,30 @@ func doCall(args CallArgs, blockNrOrHash BlockNumberOrHash, overrides *StateOver
 
 	var gasErr error
 	var intrinsicGas uint64
-	intrinsicGas, gasErr = executor.IntrinsicGas(data, contractCreation)
+	intrinsicGas, gasErr = executor.IntrinsicGas(data, args.accessList(), contractCreation)
 	if gasErr != nil {
 		logger.Errorf("IntrinsicGas error:%s", gasErr.Error())
-		return nil, gasErr, 0
+		return nil, gasErr
 	}
 	if initialGas < intrinsicGas {
 		logger.Errorf("gas limit too low,gas limit:%d,intrinsic gas:%d", initialGas, intrinsicGas)
-		return nil, errors.New("intrinsic gas too low"), 0
+		return nil, errors.New("intrinsic gas too low")
 	}
 
 	vmCtx := vm.Context{}
 	vmCtx.CanTransfer = vm.CanTransfer
 	vmCtx.Transfer = vm.Transfer
-	vmCtx.GetHash = core.GetBlockChain().GetBlockHash
+	vmCtx.GetHash = func(height uint64) common.Hash { return core.GetBlockChain().GetBlockHash(height) }
 	if args.From != nil {
 		vmCtx.Origin = *args.From
 	}
 	vmCtx.GasLimit = initialGas - intrinsicGas
-	vmCtx.Coinbase = common.BytesToAddress(block.Header.Castor)
-	vmCtx.BlockNumber = new(big.Int).SetUint64(block.Header.Height)
-	vmCtx.Time = new(big.Int).SetUint64(uint64(block.Header.CurTime.Unix()))
+	vmCtx.Coinbase = common.BytesToAddress(header.Castor)
+	vmCtx.BlockNumber = new(big.Int).SetUint64(header.Height)
+	vmCtx.Time = new(big.Int).SetUint64(uint64(header.CurTime.Unix()))
 	//set constant value
 	vmCtx.Difficulty = new(big.Int).SetUint64(123)
+	vmCtx.GetRandom = func() common.Hash { return core.GetBlockChain().PrevRandao(header) }
 	vmCtx.GasPrice = gasPrice
 
 	var transferValue *big.Int
@@ -326,13 +484,25 @@ func doCall(args CallArgs, blockNrOrHash BlockNumberOrHash, overrides *StateOver
 	}
 
 	vmInstance := vm.NewEVMWithNFT(vmCtx, accountdb, accountdb)
+	if tracer != nil {
+		vmInstance.SetTracer(tracer)
+	}
 	caller := vm.AccountRef(vmCtx.Origin)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		vmInstance.Cancel()
+	}()
 	var (
 		result          []byte
 		leftOverGas     uint64
 		contractAddress common.Address
 		err             error
 	)
+	if common.CurrentRules().IsProposal031 {
+		executor.PrepareAccessList(accountdb, args.accessList())
+	}
 	logger.Debugf("before vm instance,intrinsicGas:%d,gasLimit:%d", intrinsicGas, vmCtx.GasLimit)
 	if contractCreation {
 		result, contractAddress, leftOverGas, _, err = vmInstance.Create(caller, data, vmCtx.GasLimit, transferValue)
@@ -342,19 +512,15 @@ func doCall(args CallArgs, blockNrOrHash BlockNumberOrHash, overrides *StateOver
 		logger.Debugf("[eth_call]After execute contract call! result:%v,leftOverGas: %d,error:%v", result, leftOverGas, err)
 	}
 
+	if vmInstance.Cancelled() {
+		return nil, &timeoutError{}
+	}
+
 	gasUsed := initialGas - leftOverGas
 	if gasUsed < txGas {
 		gasUsed = txGas
 	}
-	// If the result contains a revert reason, try to unpack and return it.
-	if err == vm.ErrExecutionReverted && len(result) > 0 {
-		err := adaptErrorOutput(err, result)
-		return nil, &revertError{err, common.ToHex(result)}, gasUsed
-	}
-	if err != nil {
-		return nil, err, gasUsed
-	}
-	return result, nil, gasUsed
+	return &callResult{usedGas: gasUsed, err: err, returnData: result}, nil
 }
 
 // ChainId returns the chainID value for transaction replay protection.
@@ -379,13 +545,103 @@ func (s *EthAPIService) GasPrice() (*utility.Big, error) {
 	return &gasPrice, nil
 }
 
+// MaxPriorityFeePerGas returns a suggestion for a gas tip cap.
+// The gas price is flat, so no tip is needed for a transaction to be packed.
+func (s *EthAPIService) MaxPriorityFeePerGas() (*utility.Big, error) {
+	return (*utility.Big)(new(big.Int)), nil
+}
+
+// FeeHistory returns the fee market history of blockCount blocks up to lastBlock.
+// The base fee is the flat gas price and the rewards of all the percentiles are zero.
+func (s *EthAPIService) FeeHistory(blockCount DecimalOrHex, lastBlock BlockNumber, rewardPercentiles []float64) (*FeeHistoryResult, error) {
+	for i, p := range rewardPercentiles {
+		if p < 0 || p > 100 {
+			return nil, fmt.Errorf("invalid reward percentile: %f", p)
+		}
+		if i > 0 && p < rewardPercentiles[i-1] {
+			return nil, fmt.Errorf("invalid reward percentile: #%d:%f > #%d:%f", i-1, rewardPercentiles[i-1], i, p)
+		}
+	}
+
+	chain := core.GetBlockChain()
+	last := chain.Height()
+	if lastBlock > EarliestBlockNumber {
+		if uint64(lastBlock) > last {
+			return nil, fmt.Errorf("request beyond head block: requested %d, head %d", lastBlock, last)
+		}
+		last = uint64(lastBlock)
+	} else if lastBlock == EarliestBlockNumber {
+		last = 0
+	} else if lastBlock == SafeBlockNumber || lastBlock == FinalizedBlockNumber {
+		header := getHeaderByNumber(lastBlock)
+		if nil == header {
+			return nil, errors.New("param invalid")
+		}
+		last = header.Height
+	}
+
+	count := uint64(blockCount)
+	if count > maxFeeHistory {
+		count = maxFeeHistory
+	}
+	if count > last+1 {
+		count = last + 1
+	}
+	oldest := last + 1 - count
+	result := &FeeHistoryResult{OldestBlock: (*utility.Big)(new(big.Int).SetUint64(oldest)), GasUsedRatio: make([]float64, 0, count)}
+	if 0 == count {
+		return result, nil
+	}
+
+	result.BaseFee = make([]*utility.Big, 0, count+1)
+	if 0 != len(rewardPercentiles) {
+		result.Reward = make([][]*utility.Big, 0, count)
+	}
+	for height := oldest; height <= last; height++ {
+		var gasUsed uint64
+		limit := gasLimit
+		if block := chain.QueryBlock(height); block != nil {
+			gasUsed = blockGasUsed(block)
+			if 0 != block.Header.GasLimit {
+				limit = block.Header.GasLimit
+			}
+		}
+		result.GasUsedRatio = append(result.GasUsedRatio, float64(gasUsed)/float64(limit))
+		result.BaseFee = append(result.BaseFee, (*utility.Big)(gasPrice))
+		if nil != result.Reward {
+			reward := make([]*utility.Big, len(rewardPercentiles))
+			for i := range reward {
+				reward[i] = (*utility.Big)(new(big.Int))
+			}
+			result.Reward = append(result.Reward, reward)
+		}
+	}
+	// the base fee of the next block
+	result.BaseFee = append(result.BaseFee, (*utility.Big)(gasPrice))
+	return result, nil
+}
+
+// Syncing returns false if the node is not behind the sync candidates,
+// otherwise the starting, current and highest block of the catch up.
+func (s *EthAPIService) Syncing() (interface{}, error) {
+	progress, syncing := core.GetSyncProgress()
+	if !syncing {
+		return false, nil
+	}
+	return &SyncingResult{
+		StartingBlock: utility.Uint64(progress.StartingBlock),
+		CurrentBlock:  utility.Uint64(progress.CurrentBlock),
+		HighestBlock:  utility.Uint64(progress.HighestBlock),
+	}, nil
+}
+
 // GetBalance returns the amount of wei for the given address in the state of the
 // given block number. The rpc.LatestBlockNumber and rpc.PendingBlockNumber meta
 // block numbers are also allowed.
 func (api *EthAPIService) GetBalance(address common.Address, blockNrOrHash BlockNumberOrHash) (*utility.Big, error) {
-	accountDB := getAccountDBByHashOrHeight(blockNrOrHash)
-	if accountDB == nil {
-		return nil, errors.New("param invalid")
+	accountDB, err := getAccountDBByHashOrHeight(blockNrOrHash)
+	if err != nil {
+		return nil, err
 	}
 	balanceRaw := accountDB.GetBalance(address)
 	//base, _ := big.NewInt(0).SetString("1000000000000000000", 10)
@@ -396,9 +652,9 @@ func (api *EthAPIService) GetBalance(address common.Address, blockNrOrHash Block
 
 // GetCode returns the code stored at the given address in the state for the given block number.
 func (s *EthAPIService) GetCode(address common.Address, blockNrOrHash BlockNumberOrHash) (utility.Bytes, error) {
-	accountDB := getAccountDBByHashOrHeight(blockNrOrHash)
-	if accountDB == nil {
-		return nil, errors.New("param invalid")
+	accountDB, err := getAccountDBByHashOrHeight(blockNrOrHash)
+	if err != nil {
+		return nil, err
 	}
 	code := accountDB.GetCode(address)
 	if code == nil {
@@ -411,9 +667,9 @@ func (s *EthAPIService) GetCode(address common.Address, blockNrOrHash BlockNumbe
 // block number. The rpc.LatestBlockNumber and rpc.PendingBlockNumber meta block
 // numbers are also allowed.
 func (s *EthAPIService) GetStorageAt(address common.Address, key string, blockNrOrHash BlockNumberOrHash) (utility.Bytes, error) {
-	accountDB := getAccountDBByHashOrHeight(blockNrOrHash)
-	if accountDB == nil {
-		return nil, errors.New("param invalid")
+	accountDB, err := getAccountDBByHashOrHeight(blockNrOrHash)
+	if err != nil {
+		return nil, err
 	}
 	value := accountDB.GetData(address, common.HexToHash(key).Bytes())
 	if value == nil {
@@ -422,6 +678,52 @@ func (s *EthAPIService) GetStorageAt(address common.Address, key string, blockNr
 	return value, nil
 }
 
+// GetProof returns the Merkle-proof for a given account and optionally some storage keys.
+// The proofs are made against the state root of the given block, so they can be
+// checked against BlockHeader.StateTree with trie.VerifyProof.
+func (s *EthAPIService) GetProof(address common.Address, storageKeys []string, blockNrOrHash BlockNumberOrHash) (*AccountResult, error) {
+	accountDB, err := getAccountDBByHashOrHeight(blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+
+	storageTrie := accountDB.StorageTrie(address)
+	storageHash := common.HexToHash("0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+	if storageTrie != nil {
+		storageHash = storageTrie.Hash()
+	}
+
+	storageProof := make([]StorageResult, len(storageKeys))
+	for i, key := range storageKeys {
+		if storageTrie == nil {
+			storageProof[i] = StorageResult{key, &utility.Big{}, []string{}}
+			continue
+		}
+		slot := common.HexToHash(key)
+		proof, err := accountDB.GetStorageProof(address, slot)
+		if err != nil {
+			return nil, err
+		}
+		value := utility.Big(*new(big.Int).SetBytes(accountDB.GetData(address, slot.Bytes())))
+		storageProof[i] = StorageResult{key, &value, toHexSlice(proof)}
+	}
+
+	accountProof, err := accountDB.GetProof(address)
+	if err != nil {
+		return nil, err
+	}
+	balance := utility.Big(*accountDB.GetBalance(address))
+	return &AccountResult{
+		Address:      address,
+		AccountProof: toHexSlice(accountProof),
+		Balance:      &balance,
+		CodeHash:     accountDB.GetCodeHash(address),
+		Nonce:        utility.Uint64(accountDB.GetNonce(address)),
+		StorageHash:  storageHash,
+		StorageProof: storageProof,
+	}, nil
+}
+
 // GetBlockTransactionCountByNumber returns the number of transactions in the block with the given block number.
 func (s *EthAPIService) GetBlockTransactionCountByNumber(blockNr BlockNumber) *utility.Uint {
 	block := getBlockByHashOrHeight(BlockNumberOrHash{BlockNumber: &blockNr})
@@ -456,9 +758,9 @@ func (s *EthAPIService) GetBlockTransactionCountByHash(blockHash common.Hash) *u
 
 // GetTransactionCount returns the number of transactions the given address has sent for the given block number
 func (s *EthAPIService) GetTransactionCount(address common.Address, blockNrOrHash BlockNumberOrHash) (*utility.Uint64, error) {
-	accountDB := getAccountDBByHashOrHeight(blockNrOrHash)
-	if accountDB == nil {
-		return nil, errors.New("param invalid")
+	accountDB, err := getAccountDBByHashOrHeight(blockNrOrHash)
+	if err != nil {
+		return nil, err
 	}
 	nonce := utility.Uint64(accountDB.GetNonce(address))
 	return &nonce, nil
@@ -484,43 +786,32 @@ func (s *EthAPIService) GetTransactionReceipt(hash common.Hash) (map[string]inte
 	if executedTx.Receipt.Height+confirmBlockCount > topBlock.Height {
 		return nil, nil
 	}
+	return marshalReceipt(executedTx, &tx, 0, 0), nil
+}
 
-	fields := map[string]interface{}{
-		"blockHash": executedTx.Receipt.BlockHash,
-		//"blockNumber":       executedTx.Receipt.Height,
-		"blockNumber":       (*utility.Big)(new(big.Int).SetUint64(executedTx.Receipt.Height)),
-		"transactionHash":   executedTx.Receipt.TxHash,
-		"from":              tx.Source,
-		"gasUsed":           utility.Uint64(executedTx.Receipt.GasUsed),
-		"cumulativeGasUsed": utility.Uint64(0),
-		"contractAddress":   nil,
-		"logs":              executedTx.Receipt.Logs,
-		"to":                tx.Target,
-		"transactionIndex":  utility.Uint64(0),
-		"logsBloom":         "0x00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
-	}
-
-	// Assign receipt status or post state.
-	if len(executedTx.Receipt.PostState) > 0 {
-		fields["root"] = utility.Bytes(executedTx.Receipt.PostState)
-	} else {
-		fields["status"] = utility.Uint(executedTx.Receipt.Status)
-	}
-	if executedTx.Receipt.Logs == nil {
-		fields["logs"] = [][]*types.Log{}
+// GetBlockReceipts returns the receipts of all the transactions in the block.
+func (s *EthAPIService) GetBlockReceipts(blockNrOrHash BlockNumberOrHash) ([]map[string]interface{}, error) {
+	block := getBlockByHashOrHeight(blockNrOrHash)
+	if block == nil {
+		return nil, nil
 	}
-	// If the ContractAddress is 20 0x0 bytes, assume it is not a contract creation
-	if executedTx.Receipt.ContractAddress != (common.Address{}) {
-		fields["contractAddress"] = executedTx.Receipt.ContractAddress
+	topBlock := core.GetBlockChain().TopBlock()
+	//do not return during confirm block
+	if topBlock == nil || block.Header.Height+confirmBlockCount > topBlock.Height {
+		return nil, nil
 	}
 
-	if tx.Target == "" && (tx.Type == types.TransactionTypeETHTX || tx.Type == types.TransactionTypeContract) {
-		fields["to"] = executedTx.Receipt.ContractAddress
+	result := make([]map[string]interface{}, 0, len(block.Transactions))
+	var cumulativeGasUsed uint64
+	for index, tx := range block.Transactions {
+		executedTx := service.GetTransactionPool().GetExecuted(tx.Hash)
+		if executedTx == nil {
+			continue
+		}
+		cumulativeGasUsed += executedTx.Receipt.GasUsed
+		result = append(result, marshalReceipt(executedTx, tx, uint64(index), cumulativeGasUsed))
 	}
-	receipts := types.Receipts{&executedTx.Receipt.Receipt}
-	logBloom := types.CreateBloom(receipts)
-	fields["logsBloom"] = logBloom
-	return fields, nil
+	return result, nil
 }
 
 // GetBlockByHash returns the requested block. When fullTx is true all transactions in the block are returned in full
@@ -536,16 +827,11 @@ func (s *EthAPIService) GetBlockByHash(hash common.Hash, fullTx bool) (*RPCBlock
 // GetBlockByNumber returns the requested canonical block.
 //   - When blockNr is -1 the chain head is returned.
 //   - When blockNr is -2 the pending chain head is returned.
+//   - When blockNr is -3 or -4 the finalized or safe block is returned.
 //   - When fullTx is true all transactions in the block are returned, otherwise
 //     only the transaction hash is returned.
 func (s *EthAPIService) GetBlockByNumber(number BlockNumber, fullTx bool) (*RPCBlock, error) {
-	var block *types.Block
-	if number == PendingBlockNumber || number == LatestBlockNumber || number == EarliestBlockNumber {
-		height := core.GetBlockChain().Height()
-		block = core.GetBlockChain().QueryBlock(height)
-	} else {
-		block = core.GetBlockChain().QueryBlock(uint64(number))
-	}
+	block := getBlockByHashOrHeight(BlockNumberOrHash{BlockNumber: &number})
 	if block == nil {
 		return nil, errors.New("param invalid")
 	}
@@ -585,14 +871,7 @@ func (s *EthAPIService) GetLogs(crit types.FilterCriteria) ([]*types.Log, error)
 
 // GetTransactionByBlockNumberAndIndex returns the transaction for the given block number and index.
 func (s *EthAPIService) GetTransactionByBlockNumberAndIndex(blockNr BlockNumber, index utility.Uint) *RPCTransaction {
-	var block *types.Block
-	if blockNr == PendingBlockNumber || blockNr == LatestBlockNumber || blockNr == EarliestBlockNumber {
-		height := core.GetBlockChain().Height()
-		block = core.GetBlockChain().QueryBlock(height)
-	} else {
-		block = core.GetBlockChain().QueryBlock(uint64(blockNr))
-	}
-
+	block := getBlockByHashOrHeight(BlockNumberOrHash{BlockNumber: &blockNr})
 	if block == nil {
 		return nil
 	}
@@ -651,7 +930,7 @@ func validateTx(tx *eth_tx.Transaction) (common.Address, error) {
 	var sender common.Address
 
 	// Make sure the transaction is signed properly
-	signer := eth_tx.NewEIP155Signer(common.GetChainId(utility.MaxUint64))
+	signer := eth_tx.NewLondonSigner(common.GetChainId(utility.MaxUint64))
 	sender, err = eth_tx.Sender(signer, tx)
 	if err != nil {
 		return sender, err
@@ -667,7 +946,7 @@ func validateTx(tx *eth_tx.Transaction) (common.Address, error) {
 	}
 	// Ensure the transaction has more gas than the bare minimum needed to cover
 	// the transaction metadata
-	intrGas, err := executor.IntrinsicGas(tx.Data(), tx.To() == nil)
+	intrGas, err := executor.IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil)
 	if err != nil {
 		return sender, err
 	}
@@ -737,62 +1016,77 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		result.BlockNumber = (*utility.Big)(new(big.Int).SetUint64(blockNumber))
 		result.TransactionIndex = (*utility.Uint64)(&index)
 	}
-	r, s, v := signatureValues(tx)
-	result.R = r
-	result.V = v
-	result.S = s
-	return result
-}
-
-func signatureValues(tx *types.Transaction) (*utility.Big, *utility.Big, *utility.Big) {
-	if tx == nil || tx.Type != types.TransactionTypeETHTX {
-		return nil, nil, nil
-	}
 	ethTx := new(eth_tx.Transaction)
-	if err := rlp.DecodeBytes(common.FromHex(tx.ExtraData), ethTx); err != nil {
-		return nil, nil, nil
+	if err := ethTx.UnmarshalBinary(common.FromHex(tx.ExtraData)); err != nil {
+		return result
 	}
 	v, r, s := ethTx.RawSignatureValues()
-	return (*utility.Big)(r), (*utility.Big)(s), (*utility.Big)(v)
+	result.R = (*utility.Big)(r)
+	result.V = (*utility.Big)(v)
+	result.S = (*utility.Big)(s)
+	result.Type = utility.Uint64(ethTx.Type())
+	switch ethTx.Type() {
+	case eth_tx.AccessListTxType:
+		al := ethTx.AccessList()
+		result.Accesses = &al
+		result.ChainID = (*utility.Big)(ethTx.ChainId())
+	case eth_tx.DynamicFeeTxType:
+		al := ethTx.AccessList()
+		result.Accesses = &al
+		result.ChainID = (*utility.Big)(ethTx.ChainId())
+		result.GasFeeCap = (*utility.Big)(ethTx.GasFeeCap())
+		result.GasTipCap = (*utility.Big)(ethTx.GasTipCap())
+	}
+	return result
 }
 
 // -----------------------------------------------------------------------------
-func getAccountDBByHashOrHeight(blockNrOrHash BlockNumberOrHash) *account.AccountDB {
-	var accountDB *account.AccountDB
+func getAccountDBByHashOrHeight(blockNrOrHash BlockNumberOrHash) (*account.AccountDB, error) {
 	if blockNrOrHash.BlockHash != nil {
-		accountDB = getAccountDBByHash(*blockNrOrHash.BlockHash)
+		return getAccountDBByHash(*blockNrOrHash.BlockHash)
 	} else if blockNrOrHash.BlockNumber != nil {
-		var height uint64
-		if *blockNrOrHash.BlockNumber == PendingBlockNumber || *blockNrOrHash.BlockNumber == LatestBlockNumber || *blockNrOrHash.BlockNumber == EarliestBlockNumber {
-			accountDB = getAccountDBByHeight(0)
-		} else {
-			height = uint64(*blockNrOrHash.BlockNumber)
-			accountDB = getAccountDBByHeight(height)
-		}
+		return getAccountDBByNumber(*blockNrOrHash.BlockNumber)
 	}
-	return accountDB
+	return nil, errors.New("param invalid")
 }
-func getAccountDBByHeight(height uint64) (accountDB *account.AccountDB) {
-	var b *types.BlockHeader
-	if height == 0 {
-		b = core.GetBlockChain().TopBlock()
-	} else {
-		b = core.GetBlockChain().QueryBlockHeaderByHeight(height, true)
+func getAccountDBByNumber(number BlockNumber) (*account.AccountDB, error) {
+	if number == PendingBlockNumber {
+		if _, state := core.GetPendingState(); state != nil {
+			return state, nil
+		}
 	}
+	b := getHeaderByNumber(number)
 	if nil == b {
-		return nil
+		return nil, errors.New("param invalid")
 	}
-	accountDB, _ = middleware.AccountDBManagerInstance.GetAccountDBByHash(b.StateTree)
-	return
+	return middleware.AccountDBManagerInstance.GetAccountDBByHash(b.StateTree)
 }
 
-func getAccountDBByHash(hash common.Hash) (accountDB *account.AccountDB) {
+func getAccountDBByHash(hash common.Hash) (*account.AccountDB, error) {
 	b := core.GetBlockChain().QueryBlockByHash(hash)
 	if nil == b {
-		return nil
+		return nil, errors.New("param invalid")
 	}
-	accountDB, _ = middleware.AccountDBManagerInstance.GetAccountDBByHash(b.Header.StateTree)
-	return
+	return middleware.AccountDBManagerInstance.GetAccountDBByHash(b.Header.StateTree)
+}
+
+// getStateAndHeader returns the state and the header to execute a call on.
+// The pending state includes the executable txs in the pool and is built on a block not cast yet.
+func getStateAndHeader(blockNrOrHash BlockNumberOrHash) (*account.AccountDB, *types.BlockHeader, error) {
+	if number, ok := blockNrOrHash.Number(); ok && number == PendingBlockNumber {
+		if header, state := core.GetPendingState(); state != nil {
+			return state, header, nil
+		}
+	}
+	accountdb, err := getAccountDBByHashOrHeight(blockNrOrHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	block := getBlockByHashOrHeight(blockNrOrHash)
+	if block == nil {
+		return nil, nil, errors.New("param invalid")
+	}
+	return accountdb, block.Header, nil
 }
 
 func getBlockByHashOrHeight(blockNrOrHash BlockNumberOrHash) *types.Block {
@@ -800,18 +1094,39 @@ func getBlockByHashOrHeight(blockNrOrHash BlockNumberOrHash) *types.Block {
 	if blockNrOrHash.BlockHash != nil {
 		block = core.GetBlockChain().QueryBlockByHash(*blockNrOrHash.BlockHash)
 	} else if blockNrOrHash.BlockNumber != nil {
-		var height uint64
-		if *blockNrOrHash.BlockNumber == PendingBlockNumber || *blockNrOrHash.BlockNumber == LatestBlockNumber || *blockNrOrHash.BlockNumber == EarliestBlockNumber {
-			header := core.GetBlockChain().TopBlock()
+		if header := getHeaderByNumber(*blockNrOrHash.BlockNumber); header != nil {
 			block = core.GetBlockChain().QueryBlock(header.Height)
-		} else {
-			height = uint64(*blockNrOrHash.BlockNumber)
-			block = core.GetBlockChain().QueryBlock(height)
 		}
 	}
 	return block
 }
 
+// getHeaderByNumber resolves the block tags against the top block.
+// Block 0 is taken as latest, the same as the earliest tag.
+func getHeaderByNumber(number BlockNumber) *types.BlockHeader {
+	chain := core.GetBlockChain()
+	switch number {
+	case SafeBlockNumber:
+		return chain.SafeBlock()
+	case FinalizedBlockNumber:
+		return chain.FinalizedBlock()
+	case PendingBlockNumber, LatestBlockNumber, EarliestBlockNumber:
+		return chain.TopBlock()
+	}
+	if number < 0 {
+		return nil
+	}
+	return chain.QueryBlockHeaderByHeight(uint64(number), true)
+}
+
+// MixHash is the PREVRANDAO of the block since Proposal028, and zero before
+func MixHash(header *types.BlockHeader) string {
+	if !common.LocalChainConfig.Rules(header.Height).IsProposal028 {
+		return "0x0000000000000000000000000000000000000000000000000000000000000000"
+	}
+	return core.GetBlockChain().PrevRandao(header).Hex()
+}
+
 func adaptRPCBlock(block *types.Block, fullTx bool) *RPCBlock {
 	header := block.Header
 	rpcBlock := RPCBlock{
@@ -822,7 +1137,7 @@ func adaptRPCBlock(block *types.Block, fullTx bool) *RPCBlock {
 		Hash:         header.Hash,
 		Bloom:        "0x00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
 		Miner:        common.BytesToAddress(block.Header.Castor),
-		MixHash:      "0x0000000000000000000000000000000000000000000000000000000000000000",
+		MixHash:      MixHash(header),
 		Nonce:        utility.Bytes(nonce[:]),
 		Number:       utility.Uint64(header.Height),
 		ParentHash:   header.PreHash,
@@ -835,6 +1150,10 @@ func adaptRPCBlock(block *types.Block, fullTx bool) *RPCBlock {
 		TotalDifficulty: &totalDifficulty,
 		Uncles:          []string{},
 	}
+	if 0 != header.GasLimit {
+		rpcBlock.GasLimit = utility.Uint64(header.GasLimit)
+		rpcBlock.GasUsed = utility.Uint64(header.GasUsed)
+	}
 	transactions := make([]interface{}, 0)
 	for index, tx := range block.Transactions {
 		if !hasReceipt(tx.Hash) {
@@ -902,6 +1221,74 @@ func getRevertReason(result []byte) string {
 	return decoded.(string)
 }
 
+// marshalReceipt returns the receipt of the executed transaction in the RPC representation
+func marshalReceipt(executedTx *service.ExecutedTransaction, tx *types.Transaction, index uint64, cumulativeGasUsed uint64) map[string]interface{} {
+	// the receipt keeps the cumulative gas since Proposal029
+	if 0 != executedTx.Receipt.CumulativeGasUsed {
+		cumulativeGasUsed = executedTx.Receipt.CumulativeGasUsed
+	}
+	fields := map[string]interface{}{
+		"blockHash": executedTx.Receipt.BlockHash,
+		//"blockNumber":       executedTx.Receipt.Height,
+		"blockNumber":       (*utility.Big)(new(big.Int).SetUint64(executedTx.Receipt.Height)),
+		"transactionHash":   executedTx.Receipt.TxHash,
+		"from":              tx.Source,
+		"gasUsed":           utility.Uint64(executedTx.Receipt.GasUsed),
+		"cumulativeGasUsed": utility.Uint64(cumulativeGasUsed),
+		"contractAddress":   nil,
+		"logs":              executedTx.Receipt.Logs,
+		"to":                tx.Target,
+		"transactionIndex":  utility.Uint64(index),
+		"logsBloom":         "0x00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+	}
+
+	// Assign receipt status or post state.
+	if len(executedTx.Receipt.PostState) > 0 {
+		fields["root"] = utility.Bytes(executedTx.Receipt.PostState)
+	} else {
+		fields["status"] = utility.Uint(executedTx.Receipt.Status)
+	}
+	if executedTx.Receipt.Logs == nil {
+		fields["logs"] = [][]*types.Log{}
+	}
+	// If the ContractAddress is 20 0x0 bytes, assume it is not a contract creation
+	if executedTx.Receipt.ContractAddress != (common.Address{}) {
+		fields["contractAddress"] = executedTx.Receipt.ContractAddress
+	}
+
+	if tx.Target == "" && (tx.Type == types.TransactionTypeETHTX || tx.Type == types.TransactionTypeContract) {
+		fields["to"] = executedTx.Receipt.ContractAddress
+	}
+	receipts := types.Receipts{&executedTx.Receipt.Receipt}
+	logBloom := types.CreateBloom(receipts)
+	fields["logsBloom"] = logBloom
+	return fields
+}
+
+// blockGasUsed returns the gas used by the executed transactions of the block
+func blockGasUsed(block *types.Block) uint64 {
+	// the header keeps the gas used since Proposal029
+	if 0 != block.Header.GasLimit {
+		return block.Header.GasUsed
+	}
+	var gasUsed uint64
+	for _, tx := range block.Transactions {
+		if executedTx := service.GetTransactionPool().GetExecuted(tx.Hash); executedTx != nil {
+			gasUsed += executedTx.Receipt.GasUsed
+		}
+	}
+	return gasUsed
+}
+
 func hasReceipt(txHash common.Hash) bool {
 	return service.GetTransactionPool().GetExecuted(txHash) != nil
 }
+
+// toHexSlice creates a slice of hex-strings based on []byte.
+func toHexSlice(b [][]byte) []string {
+	r := make([]string, len(b))
+	for i := range b {
+		r[i] = common.ToHex(b[i])
+	}
+	return r
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth_rpc
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/executor"
+	"com.tuntun.rangers/node/src/middleware/db"
+	"com.tuntun.rangers/node/src/middleware/log"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"com.tuntun.rangers/node/src/storage/account"
+	"com.tuntun.rangers/node/src/utility"
+	"context"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEstimateGas(t *testing.T) {
+	defer func() {
+		os.RemoveAll("logs")
+		os.RemoveAll("1.ini")
+	}()
+	common.Init(0, "1.ini", "dev")
+	logger = log.GetLoggerByIndex(log.ETHRPCLogConfig, "0")
+
+	from := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	to := common.HexToAddress("0x1000000000000000000000000000000000000002")
+	stop := common.HexToAddress("0x1000000000000000000000000000000000000003")
+	loop := common.HexToAddress("0x1000000000000000000000000000000000000004")
+	revert := common.HexToAddress("0x1000000000000000000000000000000000000005")
+
+	memdb, _ := db.NewMemDatabase()
+	stateDB := account.NewDatabase(memdb)
+	state, _ := account.NewAccountDB(common.Hash{}, stateDB)
+	state.SetBalance(from, new(big.Int).Mul(big.NewInt(1000000), gasPrice))
+	state.SetCode(stop, []byte{0x00})
+	// JUMPDEST PUSH1 0 JUMP
+	state.SetCode(loop, []byte{0x5b, 0x60, 0x00, 0x56})
+	// copies Error("boom") after the code into memory and reverts with it
+	state.SetCode(revert, common.FromHex("0x6064600c60003960646000fd"+
+		"08c379a0"+
+		"0000000000000000000000000000000000000000000000000000000000000020"+
+		"0000000000000000000000000000000000000000000000000000000000000004"+
+		"626f6f6d00000000000000000000000000000000000000000000000000000000"))
+	root, _ := state.Commit(true)
+	header := &types.BlockHeader{Height: 1, CurTime: time.Now()}
+
+	estimate := func(to common.Address, gas uint64) (utility.Uint64, error) {
+		args := CallArgs{From: &from, To: &to}
+		if 0 != gas {
+			args.Gas = (*utility.Uint64)(&gas)
+		}
+		accountdb, _ := account.NewAccountDB(root, stateDB)
+		return estimateGas(args, accountdb, func(args CallArgs) (*callResult, error) {
+			accountdb, _ := account.NewAccountDB(root, stateDB)
+			return executeCall(context.Background(), args, accountdb, header, nil)
+		})
+	}
+
+	// a plain transfer costs the intrinsic gas
+	intrinsicGas, _ := executor.IntrinsicGas(nil, nil, false)
+	gas, err := estimate(to, 0)
+	if err != nil || uint64(gas) != intrinsicGas {
+		t.Fatalf("transfer: expected %d, got %d %v", intrinsicGas, gas, err)
+	}
+	gas, err = estimate(stop, 0)
+	if err != nil || uint64(gas) != intrinsicGas {
+		t.Fatalf("stop: expected %d, got %d %v", intrinsicGas, gas, err)
+	}
+
+	// the revert reason is returned
+	_, err = estimate(revert, 0)
+	revertErr, ok := err.(*revertError)
+	if !ok || !strings.Contains(revertErr.Error(), "boom") {
+		t.Fatalf("expected revert reason, got %v", err)
+	}
+
+	// capped by the balance of the caller
+	_, err = estimate(loop, 0)
+	if nil == err || !strings.Contains(err.Error(), "(1000000)") {
+		t.Fatalf("expected balance cap, got %v", err)
+	}
+	// capped by the given gas
+	_, err = estimate(loop, 800000)
+	if nil == err || !strings.Contains(err.Error(), "(800000)") {
+		t.Fatalf("expected gas cap, got %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth_rpc
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/core"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"com.tuntun.rangers/node/src/service"
+	"com.tuntun.rangers/node/src/utility"
+	"com.tuntun.rangers/node/src/vm"
+	"context"
+	"errors"
+	"fmt"
+)
+
+const callTracerName = "callTracer"
+
+var ErrTransactionNotFound = errors.New("transaction not found")
+
+// DebugAPIService provides the debug_ namespace: re-executing transactions
+// and calls with an attached vm.Tracer.
+// Tracing is expensive, so it is served on the ipc endpoint only.
+type DebugAPIService struct{}
+
+// TraceConfig holds extra parameters to trace functions.
+type TraceConfig struct {
+	DisableStorage   bool    `json:"disableStorage"`
+	DisableStack     bool    `json:"disableStack"`
+	DisableMemory    bool    `json:"disableMemory"`
+	EnableReturnData bool    `json:"enableReturnData"`
+	Limit            int     `json:"limit"`
+	Tracer           *string `json:"tracer"`
+}
+
+// ExecutionResult groups all structured logs emitted by the EVM
+// while replaying a transaction in debug mode as well as transaction
+// execution status, the amount of gas used and the return value
+type ExecutionResult struct {
+	Gas         uint64         `json:"gas"`
+	Failed      bool           `json:"failed"`
+	ReturnValue string         `json:"returnValue"`
+	StructLogs  []StructLogRes `json:"structLogs"`
+}
+
+// StructLogRes stores a structured log emitted by the EVM while replaying a
+// transaction in debug mode
+type StructLogRes struct {
+	Pc      uint64             `json:"pc"`
+	Op      string             `json:"op"`
+	Gas     uint64             `json:"gas"`
+	GasCost uint64             `json:"gasCost"`
+	Depth   int                `json:"depth"`
+	Error   string             `json:"error,omitempty"`
+	Stack   *[]string          `json:"stack,omitempty"`
+	Memory  *[]string          `json:"memory,omitempty"`
+	Storage *map[string]string `json:"storage,omitempty"`
+}
+
+// TraceTransaction returns the structured logs created during the execution of EVM
+// and returns them as a JSON object.
+// The transaction is re-executed on the state of its parent block.
+func (api *DebugAPIService) TraceTransaction(hash common.Hash, config *TraceConfig) (interface{}, error) {
+	executedTx := service.GetTransactionPool().GetExecuted(hash)
+	if executedTx == nil {
+		return nil, ErrTransactionNotFound
+	}
+
+	tracer, err := newTracer(config)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := core.GetBlockChain().TraceTransaction(executedTx.Receipt.BlockHash, hash, tracer)
+	if err != nil {
+		return nil, err
+	}
+	return formatTraceResult(tracer, receipt.GasUsed, receipt.Status == types.ReceiptStatusFailed), nil
+}
+
+// TraceCall lets you trace a given eth_call. It collects the structured logs
+// created during the execution of EVM if the given transaction was added on
+// top of the provided block and returns them as a JSON object.
+func (api *DebugAPIService) TraceCall(ctx context.Context, args CallArgs, blockNrOrHash BlockNumberOrHash, config *TraceConfig) (interface{}, error) {
+	if args.Gas == nil || uint64(*args.Gas) > gasLimit {
+		defaultGasLimit := utility.Uint64(gasLimit)
+		args.Gas = &defaultGasLimit
+	}
+
+	tracer, err := newTracer(config)
+	if err != nil {
+		return nil, err
+	}
+	result, err := applyCall(ctx, args, blockNrOrHash, nil, tracer)
+	if err != nil {
+		return nil, err
+	}
+	return formatTraceResult(tracer, result.usedGas, result.err != nil), nil
+}
+
+func newTracer(config *TraceConfig) (vm.Tracer, error) {
+	if config == nil {
+		config = &TraceConfig{}
+	}
+	if config.Tracer != nil {
+		if *config.Tracer != callTracerName {
+			return nil, fmt.Errorf("tracer %s is not supported", *config.Tracer)
+		}
+		return vm.NewCallTracer(), nil
+	}
+
+	logConfig := vm.LogConfig{
+		DisableMemory:     config.DisableMemory,
+		DisableStack:      config.DisableStack,
+		DisableStorage:    config.DisableStorage,
+		DisableReturnData: !config.EnableReturnData,
+		Limit:             config.Limit,
+	}
+	return vm.NewStructLogger(&logConfig, nil), nil
+}
+
+func formatTraceResult(tracer vm.Tracer, gasUsed uint64, failed bool) interface{} {
+	switch tracer := tracer.(type) {
+	case *vm.CallTracer:
+		return tracer.GetResult()
+	case *vm.StructLogger:
+		return &ExecutionResult{
+			Gas:         gasUsed,
+			Failed:      failed,
+			ReturnValue: fmt.Sprintf("%x", tracer.Output()),
+			StructLogs:  formatLogs(tracer.StructLogs()),
+		}
+	}
+	return nil
+}
+
+// formatLogs formats EVM returned structured logs for json output
+func formatLogs(logs []vm.StructLog) []StructLogRes {
+	formatted := make([]StructLogRes, len(logs))
+	for index, trace := range logs {
+		formatted[index] = StructLogRes{
+			Pc:      trace.Pc,
+			Op:      trace.Op.String(),
+			Gas:     trace.Gas,
+			GasCost: trace.GasCost,
+			Depth:   trace.Depth,
+			Error:   trace.ErrorString(),
+		}
+		if trace.Stack != nil {
+			stack := make([]string, len(trace.Stack))
+			for i, stackValue := range trace.Stack {
+				stack[i] = fmt.Sprintf("%x", common.BigToHash(stackValue).Bytes())
+			}
+			formatted[index].Stack = &stack
+		}
+		if trace.Memory != nil {
+			memory := make([]string, 0, (len(trace.Memory)+31)/32)
+			for i := 0; i+32 <= len(trace.Memory); i += 32 {
+				memory = append(memory, fmt.Sprintf("%x", trace.Memory[i:i+32]))
+			}
+			formatted[index].Memory = &memory
+		}
+		if trace.Storage != nil {
+			storage := make(map[string]string)
+			for i, storageValue := range trace.Storage {
+				storage[fmt.Sprintf("%x", i)] = fmt.Sprintf("%x", storageValue)
+			}
+			formatted[index].Storage = &storage
+		}
+	}
+	return formatted
+}
@@ -70,6 +70,31 @@ func (e *callbackError) ErrorCode() int { return -32000 }
 
 func (e *callbackError) Error() string { return e.message }
 
+// the method is denied on the listener
+type methodNotAllowedError struct {
+	method string
+}
+
+func (e *methodNotAllowedError) ErrorCode() int { return -32601 }
+
+func (e *methodNotAllowedError) Error() string {
+	return fmt.Sprintf("The method %s is not allowed", e.method)
+}
+
+// the request rate of the client or the method is exceeded
+type limitExceededError struct{ message string }
+
+func (e *limitExceededError) ErrorCode() int { return -32005 }
+
+func (e *limitExceededError) Error() string { return e.message }
+
+// the execution runs over the request timeout
+type timeoutError struct{}
+
+func (e *timeoutError) ErrorCode() int { return -32002 }
+
+func (e *timeoutError) Error() string { return "request timed out" }
+
 // issued when a request is received after the server is issued to stop.
 type shutdownError struct{}
 
@@ -89,3 +89,89 @@ func TestSize(t *testing.T) {
 	sBytes := common.FromHex(s)
 	fmt.Println(len(sBytes))
 }
+
+func TestDecimalOrHex(t *testing.T) {
+	for input, expected := range map[string]uint64{`"0x10"`: 16, `"10"`: 10, `10`: 10} {
+		var value DecimalOrHex
+		if err := json.Unmarshal([]byte(input), &value); err != nil {
+			t.Fatal(err)
+		}
+		if uint64(value) != expected {
+			t.Fatalf("%s decoded to %d", input, value)
+		}
+	}
+	var value DecimalOrHex
+	if err := json.Unmarshal([]byte(`"-1"`), &value); err == nil {
+		t.Fatal("negative count accepted")
+	}
+}
+
+func TestFeeHistoryPercentiles(t *testing.T) {
+	api := &EthAPIService{}
+	if _, err := api.FeeHistory(1, LatestBlockNumber, []float64{50, 10}); err == nil {
+		t.Fatal("descending percentiles accepted")
+	}
+	if _, err := api.FeeHistory(1, LatestBlockNumber, []float64{101}); err == nil {
+		t.Fatal("percentile above 100 accepted")
+	}
+}
+
+func TestFinalityTags(t *testing.T) {
+	for input, expected := range map[string]BlockNumber{`"safe"`: SafeBlockNumber, `"finalized"`: FinalizedBlockNumber} {
+		var number BlockNumber
+		if err := json.Unmarshal([]byte(input), &number); err != nil {
+			t.Fatal(err)
+		}
+		if number != expected {
+			t.Fatalf("%s decoded to %d", input, number)
+		}
+
+		var numberOrHash BlockNumberOrHash
+		if err := json.Unmarshal([]byte(input), &numberOrHash); err != nil {
+			t.Fatal(err)
+		}
+		if numberOrHash.BlockNumber == nil || *numberOrHash.BlockNumber != expected {
+			t.Fatalf("%s decoded to %v", input, numberOrHash.BlockNumber)
+		}
+	}
+}
+
+func TestRequestLimits(t *testing.T) {
+	l := newRPCLimits(nil)
+	l.allow[HTTPListener] = []string{"eth_*", "net_version"}
+	l.deny[HTTPListener] = []string{"eth_sendRawTransaction"}
+	l.methodBuckets["eth_call"] = newTokenBucket(1, 2)
+	l.ipRate, l.ipBurst = 1, 1
+
+	if err := l.check(HTTPListener, "", "eth_blockNumber"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.check(HTTPListener, "", "eth_sendRawTransaction"); err == nil || err.ErrorCode() != -32601 {
+		t.Fatalf("denied method served, err: %v", err)
+	}
+	if err := l.check(HTTPListener, "", "debug_traceCall"); err == nil || err.ErrorCode() != -32601 {
+		t.Fatalf("method out of the allow list served, err: %v", err)
+	}
+	if err := l.check(WSListener, "", "debug_traceCall"); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := l.check(WSListener, "", "eth_call"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.check(WSListener, "", "eth_call"); err == nil || err.ErrorCode() != -32005 {
+		t.Fatalf("method rate not limited, err: %v", err)
+	}
+
+	if err := l.check(WSListener, "10.0.0.1:1234", "eth_chainId"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.check(WSListener, "10.0.0.1:5678", "eth_chainId"); err == nil || err.ErrorCode() != -32005 {
+		t.Fatalf("ip rate not limited, err: %v", err)
+	}
+	if err := l.check(WSListener, "10.0.0.2:1234", "eth_chainId"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"com.tuntun.rangers/node/src/middleware/notify"
 	"com.tuntun.rangers/node/src/middleware/types"
 	"com.tuntun.rangers/node/src/network"
+	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
@@ -39,6 +40,7 @@ type execFunc struct {
 	receiver reflect.Value  // receiver of method
 	method   reflect.Method // callback
 	argTypes []reflect.Type // input argument types
+	hasCtx   bool           // method's first argument is a context (not included in argTypes)
 	errPos   int            // err return idx, of -1 when method cannot return error
 }
 
@@ -55,11 +57,17 @@ type ethMsgHandler struct {
 
 func InitEthMsgHandler() {
 	logger = log.GetLoggerByIndex(log.ETHRPCLogConfig, strconv.Itoa(common.InstanceIndex))
+	initLimits()
 	handler = ethMsgHandler{}
-	handler.registerAPI(&EthAPIService{})
+	handler.registerAPI(ethNamespace, &EthAPIService{})
 	notify.BUS.Subscribe(notify.ClientETHRPC, handler)
 }
 
+// RegisterAPI exposes the methods of an api living outside this package, such as the filters
+func RegisterAPI(namespace string, service interface{}) {
+	handler.registerAPI(namespace, service)
+}
+
 func GetEthMsgHandler() ethMsgHandler {
 	return handler
 }
@@ -77,7 +85,7 @@ func (handler ethMsgHandler) HandleNetMessage(topic string, message notify.Messa
 	singleMessage, single := message.GetData().(*notify.ETHRPCMessage)
 	if single {
 		logger.Debugf("Rcv single eth prc message.requestId: %d,session id: %s", singleMessage.GateNonce, singleMessage.SessionId)
-		response := handler.ProcessSingleRequest(singleMessage.Message, singleMessage.GateNonce)
+		response := handler.ProcessSingleRequest(singleMessage.Message, singleMessage.GateNonce, GatewayListener, "")
 		responseJson, err := json.Marshal(response)
 		if err != nil {
 			logger.Errorf("marshal err: %v", err)
@@ -91,7 +99,7 @@ func (handler ethMsgHandler) HandleNetMessage(topic string, message notify.Messa
 	batchMessage, batch := message.GetData().(*notify.ETHRPCBatchMessage)
 	if batch {
 		logger.Debugf("Rcv batch eth prc message.requestId:%d,session id:%d", batchMessage.GateNonce, batchMessage.SessionId)
-		response := handler.ProcessBatchRequest(batchMessage.Message, batchMessage.GateNonce)
+		response := handler.ProcessBatchRequest(batchMessage.Message, batchMessage.GateNonce, GatewayListener, "")
 		responseJson, _ := json.Marshal(response)
 		logger.Debugf("Response:%s,socketRequestId:%v,sessionId:%v", string(responseJson), batchMessage.GateNonce, batchMessage.SessionId)
 		network.GetNetInstance().SendToJSONRPC(responseJson, batchMessage.SessionId, batchMessage.GateNonce)
@@ -99,21 +107,32 @@ func (handler ethMsgHandler) HandleNetMessage(topic string, message notify.Messa
 	}
 }
 
-func (handler ethMsgHandler) ProcessBatchRequest(ethRpcMessage []notify.ETHRPCPiece, gateNonce uint64) []jsonResponse {
+// ProcessBatchRequest serves the batch coming from the remote address on the listener.
+// A batch larger than the limit is answered by a single error response.
+func (handler ethMsgHandler) ProcessBatchRequest(ethRpcMessage []notify.ETHRPCPiece, gateNonce uint64, listener, remote string) interface{} {
 	result := make([]jsonResponse, 0)
 	if ethRpcMessage == nil {
 		return result
 	}
+	if limits.maxBatchSize > 0 && len(ethRpcMessage) > limits.maxBatchSize {
+		return makeResponse(nil, &invalidRequestError{fmt.Sprintf("batch too large, at most %d requests", limits.maxBatchSize)}, nil)
+	}
 
 	for _, msg := range ethRpcMessage {
-		response := handler.ProcessSingleRequest(msg, gateNonce)
+		response := handler.ProcessSingleRequest(msg, gateNonce, listener, remote)
 		result = append(result, response)
 	}
 	return result
 }
 
-func (handler ethMsgHandler) ProcessSingleRequest(ethRpcMessage notify.ETHRPCPiece, gateNonce uint64) jsonResponse {
+// ProcessSingleRequest serves the request coming from the remote address on the listener.
+// An empty remote is not limited by ip.
+func (handler ethMsgHandler) ProcessSingleRequest(ethRpcMessage notify.ETHRPCPiece, gateNonce uint64, listener, remote string) jsonResponse {
 	logger.Debugf("Method: %s,params: %s,nonce: %d, id: %v", ethRpcMessage.Method, ethRpcMessage.Params, ethRpcMessage.Nonce, ethRpcMessage.Id)
+	if err := limits.check(listener, remote, ethRpcMessage.Method); err != nil {
+		logger.Debugf("Reject method: %s, remote: %s, listener: %s, err: %s", ethRpcMessage.Method, remote, listener, err.Error())
+		return makeResponse(nil, err, ethRpcMessage.Id)
+	}
 	handlerFunc, arguments, err := handler.parseRequest(ethRpcMessage)
 	var response jsonResponse
 	if err != nil {
@@ -146,7 +165,7 @@ func (handler ethMsgHandler) ProcessSingleRequest(ethRpcMessage notify.ETHRPCPie
 	return response
 }
 
-func (handler *ethMsgHandler) registerAPI(service interface{}) {
+func (handler *ethMsgHandler) registerAPI(namespace string, service interface{}) {
 	if handler.service == nil {
 		handler.service = make(map[string]*execFunc, 0)
 	}
@@ -157,7 +176,7 @@ METHODS:
 	for m := 0; m < serviceType.NumMethod(); m++ {
 		method := serviceType.Method(m)
 		mtype := method.Type
-		mname := formatName(method.Name)
+		mname := formatName(namespace, method.Name)
 		if method.PkgPath != "" { // method must be exported
 			continue
 		}
@@ -169,6 +188,10 @@ METHODS:
 
 		firstArg := 1
 		numIn := mtype.NumIn()
+		if numIn >= 2 && mtype.In(1) == contextType {
+			h.hasCtx = true
+			firstArg = 2
+		}
 
 		// determine method arguments, ignore first arg since it's the receiver type
 		// Arguments must be exported or builtin types
@@ -242,9 +265,30 @@ func (handler ethMsgHandler) parseRequest(ethRpcMessage notify.ETHRPCPiece) (han
 	return handlerFunc, arguments, nil
 }
 
-// execute RPC method and return result
+// execute RPC method and return result.
+// The method runs over the request timeout is answered by a timeout error, and stops itself if it watches the context.
 func (handler ethMsgHandler) exec(handlerFunc *execFunc, arguments []reflect.Value, method string, nonce uint64, params string) (interface{}, error) {
-	reply := handlerFunc.method.Func.Call(arguments)
+	ctx := context.Background()
+	if limits.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limits.timeout)
+		defer cancel()
+	}
+	if handlerFunc.hasCtx {
+		arguments = append([]reflect.Value{arguments[0], reflect.ValueOf(ctx)}, arguments[1:]...)
+	}
+
+	replies := make(chan []reflect.Value, 1)
+	go func() {
+		replies <- handlerFunc.method.Func.Call(arguments)
+	}()
+	var reply []reflect.Value
+	select {
+	case reply = <-replies:
+	case <-ctx.Done():
+		logger.Warnf("Method: %s timed out, params: %s", method, params)
+		return nil, &timeoutError{}
+	}
 	if len(reply) == 0 {
 		return nil, nil
 	}
@@ -0,0 +1,253 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth_rpc
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"fmt"
+	lru "github.com/hashicorp/golang-lru"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// listeners serving the eth rpc, each one has its own allow and deny lists
+const (
+	HTTPListener    = "http"
+	WSListener      = "ws"
+	GatewayListener = "gateway"
+)
+
+const (
+	limitSection = "rpc"
+
+	defaultMaxBodySize  = 5 * 1024 * 1024
+	defaultMaxBatchSize = 1000
+	defaultTimeout      = 30
+	defaultCors         = "*"
+
+	// clients tracked by the per ip limits, the least recent ones are forgotten
+	ipBucketsSize = 10000
+)
+
+var limits = newRPCLimits(nil)
+
+// rpcLimits guards the eth rpc listeners against abusive clients.
+// The limits are read from the rpc section of the config:
+//   - maxBodySize, maxBatchSize: the size of a request body in bytes and the requests in a batch
+//   - timeout: seconds a request may execute
+//   - cors: allowed origins separated by comma
+//   - ipRate, ipBurst: requests per second and the burst of one client, 0 means unlimited
+//   - methodRate: requests per second of the methods of all clients, such as eth_call:100,eth_getLogs:10
+//   - httpAllow, httpDeny, wsAllow, wsDeny, gatewayAllow, gatewayDeny: methods separated by comma,
+//     a trailing * matches a prefix such as debug_*
+type rpcLimits struct {
+	maxBodySize  int
+	maxBatchSize int
+	timeout      time.Duration
+	cors         []string
+
+	ipRate, ipBurst float64
+	ipBuckets       *lru.Cache
+	ipLock          sync.Mutex
+
+	// read only after init
+	methodBuckets map[string]*tokenBucket
+	allow, deny   map[string][]string
+}
+
+func initLimits() {
+	limits = newRPCLimits(common.GlobalConf)
+}
+
+func newRPCLimits(conf common.ConfManager) *rpcLimits {
+	getInt := func(key string, defaultValue int) int {
+		if nil == conf {
+			return defaultValue
+		}
+		return conf.GetInt(limitSection, key, defaultValue)
+	}
+	getString := func(key string, defaultValue string) string {
+		if nil == conf {
+			return defaultValue
+		}
+		return conf.GetString(limitSection, key, defaultValue)
+	}
+
+	l := &rpcLimits{
+		maxBodySize:   getInt("maxBodySize", defaultMaxBodySize),
+		maxBatchSize:  getInt("maxBatchSize", defaultMaxBatchSize),
+		timeout:       time.Duration(getInt("timeout", defaultTimeout)) * time.Second,
+		cors:          splitList(getString("cors", defaultCors)),
+		ipRate:        float64(getInt("ipRate", 0)),
+		methodBuckets: make(map[string]*tokenBucket),
+		allow:         make(map[string][]string),
+		deny:          make(map[string][]string),
+	}
+	l.ipBurst = float64(getInt("ipBurst", int(l.ipRate)))
+	l.ipBuckets, _ = lru.New(ipBucketsSize)
+
+	for _, item := range splitList(getString("methodRate", "")) {
+		pair := strings.SplitN(item, ":", 2)
+		if 2 != len(pair) {
+			continue
+		}
+		rate, err := strconv.ParseFloat(pair[1], 64)
+		if err != nil || rate <= 0 {
+			continue
+		}
+		l.methodBuckets[pair[0]] = newTokenBucket(rate, rate)
+	}
+	for _, listener := range []string{HTTPListener, WSListener, GatewayListener} {
+		l.allow[listener] = splitList(getString(listener+"Allow", ""))
+		l.deny[listener] = splitList(getString(listener+"Deny", ""))
+	}
+	return l
+}
+
+// RequestFilter returns the filter of the requests coming from the listener
+func RequestFilter(listener string) func(remote string, method string) error {
+	return func(remote string, method string) error {
+		if err := limits.check(listener, remote, method); err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
+// MaxBodySize returns the size limit of a request body in bytes
+func MaxBodySize() int {
+	return limits.maxBodySize
+}
+
+// MaxBatchSize returns the limit of the requests in a batch
+func MaxBatchSize() int {
+	return limits.maxBatchSize
+}
+
+// RequestTimeout returns the time a request may execute
+func RequestTimeout() time.Duration {
+	return limits.timeout
+}
+
+// CorsOrigins returns the origins allowed to access the rpc from browsers
+func CorsOrigins() []string {
+	return limits.cors
+}
+
+// AllowedOrigin returns the value of the Access-Control-Allow-Origin header for the origin, or empty if not allowed
+func AllowedOrigin(origin string) string {
+	for _, allowed := range limits.cors {
+		if allowed == "*" {
+			return "*"
+		}
+		if origin != "" && strings.EqualFold(allowed, origin) {
+			return origin
+		}
+	}
+	return ""
+}
+
+// check returns an error if the method is not served on the listener or the rate of the client or the method is exceeded.
+// An empty remote is not limited by ip, such as the requests forwarded by the gateway.
+func (l *rpcLimits) check(listener, remote, method string) Error {
+	if matchMethod(l.deny[listener], method) || (0 != len(l.allow[listener]) && !matchMethod(l.allow[listener], method)) {
+		return &methodNotAllowedError{method}
+	}
+
+	now := time.Now()
+	if bucket := l.methodBuckets[method]; nil != bucket && !bucket.take(now) {
+		return &limitExceededError{fmt.Sprintf("rate limit of %s exceeded", method)}
+	}
+	if l.ipRate > 0 && remote != "" {
+		if !l.ipBucket(remote).take(now) {
+			return &limitExceededError{"rate limit exceeded"}
+		}
+	}
+	return nil
+}
+
+func (l *rpcLimits) ipBucket(remote string) *tokenBucket {
+	ip := remote
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		ip = host
+	}
+
+	l.ipLock.Lock()
+	defer l.ipLock.Unlock()
+	if bucket, ok := l.ipBuckets.Get(ip); ok {
+		return bucket.(*tokenBucket)
+	}
+	bucket := newTokenBucket(l.ipRate, l.ipBurst)
+	l.ipBuckets.Add(ip, bucket)
+	return bucket
+}
+
+func matchMethod(patterns []string, method string) bool {
+	for _, pattern := range patterns {
+		if pattern == method || (strings.HasSuffix(pattern, "*") && strings.HasPrefix(method, strings.TrimSuffix(pattern, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(value string) []string {
+	result := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// tokenBucket refills rate tokens per second up to burst, a request takes one
+type tokenBucket struct {
+	rate, burst float64
+
+	lock   sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate, burst float64) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+func (b *tokenBucket) take(now time.Time) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -17,6 +17,7 @@
 package eth_rpc
 
 import (
+	"context"
 	"reflect"
 	"unicode"
 	"unicode/utf8"
@@ -26,12 +27,18 @@ const ethMethodPrefix = "eth_"
 const netMethodPrefix = "net_"
 const web3MethodPrefix = "web3_"
 
+const ethNamespace = "eth"
+
 // formatName will convert to first character to lower case
-func formatName(name string) string {
+// and prefix it with the namespace of the service
+func formatName(namespace string, name string) string {
 	ret := []rune(name)
 	if len(ret) > 0 {
 		ret[0] = unicode.ToLower(ret[0])
 	}
+	if namespace != ethNamespace {
+		return namespace + "_" + string(ret)
+	}
 	if string(ret) == "version" || string(ret) == "listening" {
 		return netMethodPrefix + string(ret)
 	}
@@ -57,6 +64,7 @@ func isExported(name string) bool {
 	return unicode.IsUpper(rune)
 }
 
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
 var errorType = reflect.TypeOf((*error)(nil)).Elem()
 
 // Implements this type the error interface
@@ -0,0 +1,95 @@
+// Copyright 2014 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth_tx
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"math/big"
+)
+
+// AccessListTx is the data of EIP-2930 access list transactions.
+type AccessListTx struct {
+	ChainID    *big.Int         // destination chain ID
+	Nonce      uint64           // nonce of sender account
+	GasPrice   *big.Int         // wei per gas
+	Gas        uint64           // gas limit
+	To         *common.Address  `rlp:"nil"` // nil means contract creation
+	Value      *big.Int         // wei amount
+	Data       []byte           // contract invocation input data
+	AccessList types.AccessList // EIP-2930 access list
+	V, R, S    *big.Int         // signature values
+}
+
+// copy creates a deep copy of the transaction data and initializes all fields.
+func (tx *AccessListTx) copy() TxData {
+	cpy := &AccessListTx{
+		Nonce: tx.Nonce,
+		To:    copyAddressPtr(tx.To),
+		Data:  common.CopyBytes(tx.Data),
+		Gas:   tx.Gas,
+		// These are copied below.
+		AccessList: make(types.AccessList, len(tx.AccessList)),
+		Value:      new(big.Int),
+		ChainID:    new(big.Int),
+		GasPrice:   new(big.Int),
+		V:          new(big.Int),
+		R:          new(big.Int),
+		S:          new(big.Int),
+	}
+	copy(cpy.AccessList, tx.AccessList)
+	if tx.Value != nil {
+		cpy.Value.Set(tx.Value)
+	}
+	if tx.ChainID != nil {
+		cpy.ChainID.Set(tx.ChainID)
+	}
+	if tx.GasPrice != nil {
+		cpy.GasPrice.Set(tx.GasPrice)
+	}
+	if tx.V != nil {
+		cpy.V.Set(tx.V)
+	}
+	if tx.R != nil {
+		cpy.R.Set(tx.R)
+	}
+	if tx.S != nil {
+		cpy.S.Set(tx.S)
+	}
+	return cpy
+}
+
+// accessors for innerTx.
+func (tx *AccessListTx) txType() byte                 { return AccessListTxType }
+func (tx *AccessListTx) chainID() *big.Int            { return tx.ChainID }
+func (tx *AccessListTx) accessList() types.AccessList { return tx.AccessList }
+func (tx *AccessListTx) data() []byte                 { return tx.Data }
+func (tx *AccessListTx) gas() uint64                  { return tx.Gas }
+func (tx *AccessListTx) gasPrice() *big.Int           { return tx.GasPrice }
+func (tx *AccessListTx) gasTipCap() *big.Int          { return tx.GasPrice }
+func (tx *AccessListTx) gasFeeCap() *big.Int          { return tx.GasPrice }
+func (tx *AccessListTx) value() *big.Int              { return tx.Value }
+func (tx *AccessListTx) nonce() uint64                { return tx.Nonce }
+func (tx *AccessListTx) to() *common.Address          { return tx.To }
+
+func (tx *AccessListTx) rawSignatureValues() (v, r, s *big.Int) {
+	return tx.V, tx.R, tx.S
+}
+
+func (tx *AccessListTx) setSignatureValues(chainID, v, r, s *big.Int) {
+	tx.ChainID, tx.V, tx.R, tx.S = chainID, v, r, s
+}
@@ -17,6 +17,7 @@
 package eth_tx
 
 import (
+	"bytes"
 	"com.tuntun.rangers/node/src/common"
 	crypto "com.tuntun.rangers/node/src/eth_crypto"
 	"com.tuntun.rangers/node/src/middleware/types"
@@ -34,16 +35,25 @@ import (
 )
 
 var (
-	ErrInvalidSig = errors.New("invalid transaction v, r, s values")
+	ErrInvalidSig         = errors.New("invalid transaction v, r, s values")
+	ErrTxTypeNotSupported = errors.New("transaction type not supported")
+	errEmptyTypedTx       = errors.New("empty typed transaction bytes")
 
 	defaultGasPrice = big.NewInt(1000000000)
 	protocolFee     = big.NewInt(100000000000000)  //0.0001
 	protocolFee026  = big.NewInt(1000000000000000) //0.001
 )
 
+// Transaction types.
+const (
+	LegacyTxType = iota
+	AccessListTxType
+	DynamicFeeTxType
+)
+
 type Transaction struct {
-	data txdata    // Consensus contents of a transaction
-	time time.Time // Time first seen locally (spam avoidance)
+	inner TxData    // Consensus contents of a transaction
+	time  time.Time // Time first seen locally (spam avoidance)
 
 	// caches
 	hash atomic.Value
@@ -51,7 +61,30 @@ type Transaction struct {
 	from atomic.Value
 }
 
-type txdata struct {
+// TxData is the underlying data of a transaction.
+//
+// This is implemented by LegacyTx, AccessListTx and DynamicFeeTx.
+type TxData interface {
+	txType() byte // returns the type ID
+	copy() TxData // creates a deep copy and initializes all fields
+
+	chainID() *big.Int
+	accessList() types.AccessList
+	data() []byte
+	gas() uint64
+	gasPrice() *big.Int
+	gasTipCap() *big.Int
+	gasFeeCap() *big.Int
+	value() *big.Int
+	nonce() uint64
+	to() *common.Address
+
+	rawSignatureValues() (v, r, s *big.Int)
+	setSignatureValues(chainID, v, r, s *big.Int)
+}
+
+// LegacyTx is the transaction data of regular Ethereum transactions.
+type LegacyTx struct {
 	AccountNonce uint64          `json:"nonce"    gencodec:"required"`
 	Price        *big.Int        `json:"gasPrice" gencodec:"required"`
 	GasLimit     uint64          `json:"gas"      gencodec:"required"`
@@ -68,6 +101,59 @@ type txdata struct {
 	Hash *common.Hash `json:"hash" rlp:"-"`
 }
 
+// copy creates a deep copy of the transaction data and initializes all fields.
+func (tx *LegacyTx) copy() TxData {
+	cpy := &LegacyTx{
+		AccountNonce: tx.AccountNonce,
+		Recipient:    copyAddressPtr(tx.Recipient),
+		Payload:      common.CopyBytes(tx.Payload),
+		GasLimit:     tx.GasLimit,
+		// These are initialized below.
+		Amount: new(big.Int),
+		Price:  new(big.Int),
+		V:      new(big.Int),
+		R:      new(big.Int),
+		S:      new(big.Int),
+	}
+	if tx.Amount != nil {
+		cpy.Amount.Set(tx.Amount)
+	}
+	if tx.Price != nil {
+		cpy.Price.Set(tx.Price)
+	}
+	if tx.V != nil {
+		cpy.V.Set(tx.V)
+	}
+	if tx.R != nil {
+		cpy.R.Set(tx.R)
+	}
+	if tx.S != nil {
+		cpy.S.Set(tx.S)
+	}
+	return cpy
+}
+
+// accessors for innerTx.
+func (tx *LegacyTx) txType() byte                 { return LegacyTxType }
+func (tx *LegacyTx) chainID() *big.Int            { return deriveChainId(tx.V) }
+func (tx *LegacyTx) accessList() types.AccessList { return nil }
+func (tx *LegacyTx) data() []byte                 { return tx.Payload }
+func (tx *LegacyTx) gas() uint64                  { return tx.GasLimit }
+func (tx *LegacyTx) gasPrice() *big.Int           { return tx.Price }
+func (tx *LegacyTx) gasTipCap() *big.Int          { return tx.Price }
+func (tx *LegacyTx) gasFeeCap() *big.Int          { return tx.Price }
+func (tx *LegacyTx) value() *big.Int              { return tx.Amount }
+func (tx *LegacyTx) nonce() uint64                { return tx.AccountNonce }
+func (tx *LegacyTx) to() *common.Address          { return tx.Recipient }
+
+func (tx *LegacyTx) rawSignatureValues() (v, r, s *big.Int) {
+	return tx.V, tx.R, tx.S
+}
+
+func (tx *LegacyTx) setSignatureValues(chainID, v, r, s *big.Int) {
+	tx.V, tx.R, tx.S = v, r, s
+}
+
 func NewTransaction(nonce uint64, to common.Address, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte) *Transaction {
 	return newTransaction(nonce, &to, amount, gasLimit, gasPrice, data)
 }
@@ -80,7 +166,7 @@ func newTransaction(nonce uint64, to *common.Address, amount *big.Int, gasLimit
 	if len(data) > 0 {
 		data = common.CopyBytes(data)
 	}
-	d := txdata{
+	d := LegacyTx{
 		AccountNonce: nonce,
 		Recipient:    to,
 		Payload:      data,
@@ -97,20 +183,34 @@ func newTransaction(nonce uint64, to *common.Address, amount *big.Int, gasLimit
 	if gasPrice != nil {
 		d.Price.Set(gasPrice)
 	}
-	return &Transaction{
-		data: d,
-		time: time.Now(),
-	}
+	return NewTx(&d)
+}
+
+// NewTx creates a new transaction.
+func NewTx(inner TxData) *Transaction {
+	tx := new(Transaction)
+	tx.setDecoded(inner.copy(), 0)
+	return tx
+}
+
+// Type returns the transaction type.
+func (tx *Transaction) Type() uint8 {
+	return tx.inner.txType()
 }
 
 // ChainId returns which chain id this transaction was signed for (if at all)
 func (tx *Transaction) ChainId() *big.Int {
-	return deriveChainId(tx.data.V)
+	return tx.inner.chainID()
 }
 
 // Protected returns whether the transaction is protected from replay protection.
 func (tx *Transaction) Protected() bool {
-	return isProtectedV(tx.data.V)
+	switch tx := tx.inner.(type) {
+	case *LegacyTx:
+		return tx.V != nil && isProtectedV(tx.V)
+	default:
+		return true
+	}
 }
 
 func isProtectedV(V *big.Int) bool {
@@ -124,50 +224,150 @@ func isProtectedV(V *big.Int) bool {
 
 // EncodeRLP implements rlp.Encoder
 func (tx *Transaction) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, &tx.data)
+	if tx.Type() == LegacyTxType {
+		return rlp.Encode(w, tx.inner)
+	}
+	// It's an EIP-2718 typed TX envelope.
+	var buf bytes.Buffer
+	if err := tx.encodeTyped(&buf); err != nil {
+		return err
+	}
+	return rlp.Encode(w, buf.Bytes())
+}
+
+// encodeTyped writes the canonical encoding of a typed transaction to w.
+func (tx *Transaction) encodeTyped(w *bytes.Buffer) error {
+	w.WriteByte(tx.Type())
+	return rlp.Encode(w, tx.inner)
+}
+
+// MarshalBinary returns the canonical encoding of the transaction.
+// For legacy transactions, it returns the RLP encoding. For EIP-2718 typed
+// transactions, it returns the type and payload.
+func (tx *Transaction) MarshalBinary() ([]byte, error) {
+	if tx.Type() == LegacyTxType {
+		return rlp.EncodeToBytes(tx.inner)
+	}
+	var buf bytes.Buffer
+	err := tx.encodeTyped(&buf)
+	return buf.Bytes(), err
 }
 
 // DecodeRLP implements rlp.Decoder
 func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
-	_, size, _ := s.Kind()
-	err := s.Decode(&tx.data)
-	if err == nil {
-		tx.size.Store(common.StorageSize(rlp.ListSize(size)))
-		tx.time = time.Now()
+	kind, size, err := s.Kind()
+	switch {
+	case err != nil:
+		return err
+	case kind == rlp.List:
+		// It's a legacy transaction.
+		var inner LegacyTx
+		err := s.Decode(&inner)
+		if err == nil {
+			tx.setDecoded(&inner, int(rlp.ListSize(size)))
+		}
+		return err
+	case kind == rlp.String:
+		// It's an EIP-2718 typed TX envelope.
+		var b []byte
+		if b, err = s.Bytes(); err != nil {
+			return err
+		}
+		inner, err := tx.decodeTyped(b)
+		if err == nil {
+			tx.setDecoded(inner, len(b))
+		}
+		return err
+	default:
+		return rlp.ErrExpectedList
 	}
-	return err
 }
 
-func (tx *Transaction) Data() []byte       { return common.CopyBytes(tx.data.Payload) }
-func (tx *Transaction) Gas() uint64        { return tx.data.GasLimit }
-func (tx *Transaction) GasPrice() *big.Int { return new(big.Int).Set(tx.data.Price) }
+// UnmarshalBinary decodes the canonical encoding of transactions.
+// It supports legacy RLP transactions and EIP-2718 typed transactions.
+func (tx *Transaction) UnmarshalBinary(b []byte) error {
+	if len(b) > 0 && b[0] > 0x7f {
+		// It's a legacy transaction.
+		var data LegacyTx
+		err := rlp.DecodeBytes(b, &data)
+		if err != nil {
+			return err
+		}
+		tx.setDecoded(&data, len(b))
+		return nil
+	}
+	// It's an EIP-2718 typed transaction envelope.
+	inner, err := tx.decodeTyped(b)
+	if err != nil {
+		return err
+	}
+	tx.setDecoded(inner, len(b))
+	return nil
+}
+
+// decodeTyped decodes a typed transaction from the canonical format.
+func (tx *Transaction) decodeTyped(b []byte) (TxData, error) {
+	if len(b) == 0 {
+		return nil, errEmptyTypedTx
+	}
+	switch b[0] {
+	case AccessListTxType:
+		var inner AccessListTx
+		err := rlp.DecodeBytes(b[1:], &inner)
+		return &inner, err
+	case DynamicFeeTxType:
+		var inner DynamicFeeTx
+		err := rlp.DecodeBytes(b[1:], &inner)
+		return &inner, err
+	default:
+		return nil, ErrTxTypeNotSupported
+	}
+}
+
+// setDecoded sets the inner transaction and size after decoding.
+func (tx *Transaction) setDecoded(inner TxData, size int) {
+	tx.inner = inner
+	tx.time = time.Now()
+	if size > 0 {
+		tx.size.Store(common.StorageSize(size))
+	}
+}
+
+func (tx *Transaction) Data() []byte                 { return common.CopyBytes(tx.inner.data()) }
+func (tx *Transaction) AccessList() types.AccessList { return tx.inner.accessList() }
+func (tx *Transaction) Gas() uint64                  { return tx.inner.gas() }
+func (tx *Transaction) GasPrice() *big.Int           { return new(big.Int).Set(tx.inner.gasPrice()) }
+func (tx *Transaction) GasTipCap() *big.Int          { return new(big.Int).Set(tx.inner.gasTipCap()) }
+func (tx *Transaction) GasFeeCap() *big.Int          { return new(big.Int).Set(tx.inner.gasFeeCap()) }
 func (tx *Transaction) GasPriceCmp(other *Transaction) int {
-	return tx.data.Price.Cmp(other.data.Price)
+	return tx.inner.gasPrice().Cmp(other.inner.gasPrice())
 }
 func (tx *Transaction) GasPriceIntCmp(other *big.Int) int {
-	return tx.data.Price.Cmp(other)
+	return tx.inner.gasPrice().Cmp(other)
 }
-func (tx *Transaction) Value() *big.Int  { return new(big.Int).Set(tx.data.Amount) }
-func (tx *Transaction) Nonce() uint64    { return tx.data.AccountNonce }
+func (tx *Transaction) Value() *big.Int  { return new(big.Int).Set(tx.inner.value()) }
+func (tx *Transaction) Nonce() uint64    { return tx.inner.nonce() }
 func (tx *Transaction) CheckNonce() bool { return true }
 
 // To returns the recipient address of the transaction.
 // It returns nil if the transaction is a contract creation.
 func (tx *Transaction) To() *common.Address {
-	if tx.data.Recipient == nil {
-		return nil
-	}
-	to := *tx.data.Recipient
-	return &to
+	return copyAddressPtr(tx.inner.to())
 }
 
-// Hash hashes the RLP encoding of tx.
+// Hash returns the transaction hash.
 // It uniquely identifies the transaction.
 func (tx *Transaction) Hash() common.Hash {
 	if hash := tx.hash.Load(); hash != nil {
 		return hash.(common.Hash)
 	}
-	v := rlpHash(tx)
+
+	var v common.Hash
+	if tx.Type() == LegacyTxType {
+		v = rlpHash(tx.inner)
+	} else {
+		v = prefixedRlpHash(tx.Type(), tx.inner)
+	}
 	tx.hash.Store(v)
 	return v
 }
@@ -179,19 +379,16 @@ func (tx *Transaction) WithSignature(signer Signer, sig []byte) (*Transaction, e
 	if err != nil {
 		return nil, err
 	}
-	cpy := &Transaction{
-		data: tx.data,
-		time: tx.time,
-	}
-	cpy.data.R, cpy.data.S, cpy.data.V = r, s, v
-	return cpy, nil
+	cpy := tx.inner.copy()
+	cpy.setSignatureValues(signer.ChainID(), v, r, s)
+	return &Transaction{inner: cpy, time: tx.time}, nil
 }
 
 // Cost returns amount + gasprice * gaslimit + protocolFee
 func (tx *Transaction) Cost() *big.Int {
-	total := new(big.Int).Mul(defaultGasPrice, new(big.Int).SetUint64(tx.data.GasLimit))
-	total.Add(total, tx.data.Amount)
-	if common.IsProposal026() {
+	total := new(big.Int).Mul(defaultGasPrice, new(big.Int).SetUint64(tx.inner.gas()))
+	total.Add(total, tx.inner.value())
+	if common.CurrentRules().IsProposal026 {
 		total.Add(total, protocolFee026)
 	} else {
 		total.Add(total, protocolFee)
@@ -202,7 +399,7 @@ func (tx *Transaction) Cost() *big.Int {
 // RawSignatureValues returns the V, R, S signature values of the transaction.
 // The return values should not be modified by the caller.
 func (tx *Transaction) RawSignatureValues() (v, r, s *big.Int) {
-	return tx.data.V, tx.data.R, tx.data.S
+	return tx.inner.rawSignatureValues()
 }
 
 // Transactions is a Transaction slice type for basic sorting.
@@ -236,6 +433,27 @@ func rlpHash(x interface{}) (h common.Hash) {
 	return h
 }
 
+// prefixedRlpHash writes the prefix into the hasher before rlp-encoding x.
+// It's used for typed transactions.
+func prefixedRlpHash(prefix byte, x interface{}) (h common.Hash) {
+	sha := hasherPool.Get().(crypto.KeccakState)
+	defer hasherPool.Put(sha)
+	sha.Reset()
+	sha.Write([]byte{prefix})
+	rlp.Encode(sha, x)
+	sha.Read(h[:])
+	return h
+}
+
+// copyAddressPtr copies an address.
+func copyAddressPtr(a *common.Address) *common.Address {
+	if a == nil {
+		return nil
+	}
+	cpy := *a
+	return &cpy
+}
+
 // TxDifference returns a new set which is the difference between a and b.
 func TxDifference(a, b Transactions) Transactions {
 	keep := make(Transactions, 0, len(a))
@@ -263,7 +481,7 @@ func ConvertTx(txRaw *Transaction, sender common.Address, encodedTx utility.Byte
 	result.Type = types.TransactionTypeETHTX
 	//can not ues time!
 	//result.Time = txRaw.time.String()
-	result.Nonce = txRaw.data.AccountNonce
+	result.Nonce = txRaw.Nonce()
 	result.ChainId = txRaw.ChainId().String()
 
 	data := types.ContractData{}
@@ -274,6 +492,7 @@ func ConvertTx(txRaw *Transaction, sender common.Address, encodedTx utility.Byte
 	}
 	data.GasPrice = txRaw.GasPrice().String()
 	data.GasLimit = strconv.FormatUint(txRaw.Gas(), 10)
+	data.AccessList = txRaw.AccessList()
 
 	dataByes, _ := json.Marshal(data)
 	result.Data = string(dataByes)
@@ -0,0 +1,89 @@
+// Copyright 2014 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth_tx
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	crypto "com.tuntun.rangers/node/src/eth_crypto"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestTypedTransactionSigning(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	chainId := big.NewInt(2025)
+	signer := NewLondonSigner(chainId)
+	accessList := types.AccessList{{Address: to, StorageKeys: []common.Hash{common.BigToHash(common.Big1)}}}
+
+	txs := []TxData{
+		&LegacyTx{AccountNonce: 1, Price: big.NewInt(1), GasLimit: 21000, Recipient: &to, Amount: big.NewInt(10)},
+		&AccessListTx{ChainID: chainId, Nonce: 2, GasPrice: big.NewInt(1), Gas: 30000, To: &to, Value: big.NewInt(10), AccessList: accessList},
+		&DynamicFeeTx{ChainID: chainId, Nonce: 3, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), Gas: 30000, To: &to, Value: big.NewInt(10), AccessList: accessList},
+	}
+	for i, inner := range txs {
+		tx, err := SignTx(NewTx(inner), signer, key)
+		if err != nil {
+			t.Fatalf("tx %d: sign error: %v", i, err)
+		}
+		enc, err := tx.MarshalBinary()
+		if err != nil {
+			t.Fatalf("tx %d: encode error: %v", i, err)
+		}
+		decoded := new(Transaction)
+		if err := decoded.UnmarshalBinary(enc); err != nil {
+			t.Fatalf("tx %d: decode error: %v", i, err)
+		}
+		if decoded.Type() != inner.txType() {
+			t.Fatalf("tx %d: type mismatch: got %d, want %d", i, decoded.Type(), inner.txType())
+		}
+		if decoded.Hash() != tx.Hash() {
+			t.Fatalf("tx %d: hash mismatch: got %x, want %x", i, decoded.Hash(), tx.Hash())
+		}
+		sender, err := Sender(NewLondonSigner(chainId), decoded)
+		if err != nil {
+			t.Fatalf("tx %d: sender error: %v", i, err)
+		}
+		if sender != addr {
+			t.Fatalf("tx %d: sender mismatch: got %x, want %x", i, sender, addr)
+		}
+		if _, err := Sender(NewLondonSigner(big.NewInt(1)), decoded); err != ErrInvalidChainId {
+			t.Fatalf("tx %d: expected invalid chain id, got %v", i, err)
+		}
+
+		rocketTx := ConvertTx(decoded, sender, enc)
+		var data types.ContractData
+		if err := json.Unmarshal([]byte(rocketTx.Data), &data); err != nil {
+			t.Fatal(err)
+		}
+		if len(data.AccessList) != len(inner.accessList()) {
+			t.Fatalf("tx %d: access list not converted", i)
+		}
+	}
+}
+
+func TestTypedTransactionUnsupported(t *testing.T) {
+	if err := new(Transaction).UnmarshalBinary([]byte{0x05, 0xc0}); err != ErrTxTypeNotSupported {
+		t.Fatalf("expected unsupported type, got %v", err)
+	}
+	if err := new(Transaction).UnmarshalBinary(nil); err != errEmptyTypedTx {
+		t.Fatalf("expected empty typed tx, got %v", err)
+	}
+}
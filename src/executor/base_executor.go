@@ -49,12 +49,12 @@ func (this *baseFeeExecutor) BeforeExecute(tx *types.Transaction, header *types.
 }
 
 func validateNonce(tx *types.Transaction, accountDB *account.AccountDB) error {
-	if common.IsProposal021() && tx.Type != types.TransactionTypeETHTX {
+	if common.CurrentRules().IsProposal021 && tx.Type != types.TransactionTypeETHTX {
 		//only jsonrpc tx validate nonce,other tx use request id keep sequence
 		return nil
 	}
 
-	if common.IsProposal018() {
+	if common.CurrentRules().IsProposal018 {
 		expectedNonce := accountDB.GetNonce(common.HexToAddress(tx.Source))
 		if expectedNonce > tx.Nonce {
 			logger.Debugf("Tx nonce too low.tx:%s,expected:%d,but:%d", tx.Hash.String(), expectedNonce, tx.Nonce)
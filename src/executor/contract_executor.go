@@ -39,8 +39,8 @@ type contractExecutor struct {
 
 const (
 	defaultGasLimit     uint64 = 6000000
-	p017defaultGasLimit uint64 = 30000000
-	p026defaultGasLimit uint64 = 900000000
+	p017defaultGasLimit uint64 = types.DefaultTxGasLimit
+	p026defaultGasLimit uint64 = types.MaxTxGasLimit
 )
 
 var (
@@ -61,6 +61,7 @@ type ContractRawData struct {
 	GasLimit      uint64
 	TransferValue *big.Int
 	AbiData       []byte
+	AccessList    types.AccessList
 }
 
 func getBlockHashFn(chain ChainContext) func(n uint64) common.Hash {
@@ -69,8 +70,16 @@ func getBlockHashFn(chain ChainContext) func(n uint64) common.Hash {
 	}
 }
 
+func getRandomFn(chain ChainContext, header *types.BlockHeader) func() common.Hash {
+	return func() common.Hash {
+		return chain.PrevRandao(header)
+	}
+}
+
 type ChainContext interface {
 	GetBlockHash(height uint64) common.Hash
+
+	PrevRandao(header *types.BlockHeader) common.Hash
 }
 
 func toHex(b []byte) string {
@@ -108,6 +117,7 @@ func (this *contractExecutor) Execute(transaction *types.Transaction, header *ty
 		}
 	}
 
+	rules := common.CurrentRules()
 	var contractCreation = false
 	if transaction.Target == "" {
 		contractCreation = true
@@ -118,8 +128,8 @@ func (this *contractExecutor) Execute(transaction *types.Transaction, header *ty
 	input := contractRawData.AbiData
 	var err error
 	var intrinsicGas uint64
-	if common.IsProposal015() {
-		intrinsicGas, err = IntrinsicGas(input, contractCreation)
+	if rules.IsProposal015 {
+		intrinsicGas, err = IntrinsicGas(input, contractRawData.AccessList, contractCreation)
 		if err != nil {
 			this.logger.Errorf("[ContractExecutor]IntrinsicGas error:%s", err.Error())
 			return false, err.Error()
@@ -142,14 +152,15 @@ func (this *contractExecutor) Execute(transaction *types.Transaction, header *ty
 	vmCtx.Time = new(big.Int).SetUint64(uint64(header.CurTime.Unix()))
 	//set constant value
 	vmCtx.Difficulty = new(big.Int).SetUint64(123)
+	vmCtx.GetRandom = getRandomFn(chainContext, header)
 	vmCtx.GasPrice = defaultGasPrice
 	vmCtx.GasLimit = defaultGasLimit
 	gasLimitTemp := gasLimit
-	if common.IsProposal015() {
-		if common.IsProposal017() && gasLimit > p017defaultGasLimit {
+	if rules.IsProposal015 {
+		if rules.IsProposal017 && gasLimit > p017defaultGasLimit {
 			gasLimit = p017defaultGasLimit
 		}
-		if common.IsProposal026() {
+		if rules.IsProposal026 {
 			gasLimit = gasLimitTemp
 			if gasLimit > p026defaultGasLimit {
 				gasLimit = p026defaultGasLimit
@@ -159,6 +170,9 @@ func (this *contractExecutor) Execute(transaction *types.Transaction, header *ty
 	}
 
 	vmInstance := vm.NewEVMWithNFT(vmCtx, accountdb, accountdb)
+	if tracer, ok := context["tracer"].(vm.Tracer); ok {
+		vmInstance.SetTracer(tracer)
+	}
 	caller := vm.AccountRef(vmCtx.Origin)
 	var (
 		result          []byte
@@ -166,6 +180,9 @@ func (this *contractExecutor) Execute(transaction *types.Transaction, header *ty
 		logs            []*types.Log
 		contractAddress = common.HexToAddress(transaction.Target)
 	)
+	if rules.IsProposal031 {
+		PrepareAccessList(accountdb, contractRawData.AccessList)
+	}
 
 	this.logger.Debugf("before vm instance,intrinsicGas:%d,gasLimit:%d", intrinsicGas, vmCtx.GasLimit)
 	if transaction.Target == "" {
@@ -174,7 +191,7 @@ func (this *contractExecutor) Execute(transaction *types.Transaction, header *ty
 
 		this.logger.Tracef("After execute contract create!Contract address:%s, leftOverGas: %d,error:%v", contractAddress.GetHexString(), leftOverGas, err)
 	} else {
-		if common.IsProposal007() {
+		if rules.IsProposal007 {
 			nonce := accountdb.GetNonce(caller.Address())
 			accountdb.SetNonce(caller.Address(), nonce+1)
 		}
@@ -184,7 +201,7 @@ func (this *contractExecutor) Execute(transaction *types.Transaction, header *ty
 	}
 
 	context["logs"] = logs
-	if common.IsProposal015() {
+	if rules.IsProposal015 {
 		gasUsed := gasLimit - leftOverGas
 		gasFeeUsed := new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), defaultGasPrice)
 		accountdb.SubBalance(common.HexToAddress(transaction.Source), gasFeeUsed)
@@ -201,7 +218,7 @@ func (this *contractExecutor) Execute(transaction *types.Transaction, header *ty
 }
 
 // IntrinsicGas computes the 'intrinsic gas' for a message with the given data.
-func IntrinsicGas(data []byte, contractCreation bool) (uint64, error) {
+func IntrinsicGas(data []byte, accessList types.AccessList, contractCreation bool) (uint64, error) {
 	// Set the starting gas for the raw transaction
 	var gas uint64
 	if contractCreation {
@@ -232,7 +249,12 @@ func IntrinsicGas(data []byte, contractCreation bool) (uint64, error) {
 		}
 		gas += z * vm.TxDataZeroGas
 	}
-	if common.IsProposal026() {
+	// the access list is charged since Proposal031
+	if accessList != nil && common.CurrentRules().IsProposal031 {
+		gas += uint64(len(accessList)) * vm.TxAccessListAddressGas
+		gas += uint64(accessList.StorageKeys()) * vm.TxAccessListStorageKeyGas
+	}
+	if common.CurrentRules().IsProposal026 {
 		return gas * common.GasMagnification, nil
 	}
 	return gas, nil
@@ -249,7 +271,7 @@ func (this *contractExecutor) decodeContractData(txData string) (*ContractRawDat
 	var rawGasLimit uint64
 	if data.GasLimit == "" || data.GasLimit == "0" {
 		rawGasLimit = defaultGasLimit
-		if common.IsProposal017() {
+		if common.CurrentRules().IsProposal017 {
 			rawGasLimit = p017defaultGasLimit
 		}
 	} else {
@@ -267,18 +289,28 @@ func (this *contractExecutor) decodeContractData(txData string) (*ContractRawDat
 	}
 
 	var input []byte
-	if common.IsProposal005() && (data.AbiData == "" || data.AbiData == "0x0") {
+	if common.CurrentRules().IsProposal005 && (data.AbiData == "" || data.AbiData == "0x0") {
 		input = []byte{}
 	} else {
 		input = common.FromHex(data.AbiData)
 	}
-	raw := &ContractRawData{rawGasLimit, transferValue, input}
+	raw := &ContractRawData{rawGasLimit, transferValue, input, data.AccessList}
 	return raw, ""
 }
 
+// PrepareAccessList warms the addresses and slots declared by an EIP-2930 access list
+func PrepareAccessList(accountdb *account.AccountDB, accessList types.AccessList) {
+	for _, el := range accessList {
+		accountdb.AddAddressToAccessList(el.Address)
+		for _, key := range el.StorageKeys {
+			accountdb.AddSlotToAccessList(el.Address, key)
+		}
+	}
+}
+
 //check if balance > (gasLimit * gasPrice) + transfer value
 func preCheckContractFee(tx *types.Transaction, accountDB *account.AccountDB, raw ContractRawData) error {
-	if common.IsProposal015() {
+	if common.CurrentRules().IsProposal015 {
 		balance := accountDB.GetBalance(common.HexToAddress(tx.Source))
 		gasFee := new(big.Int).Mul(new(big.Int).SetUint64(raw.GasLimit), defaultGasPrice)
 		if balance.Cmp(new(big.Int).Add(gasFee, raw.TransferValue)) < 0 {
@@ -0,0 +1,73 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/middleware/log"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"com.tuntun.rangers/node/src/service"
+	"com.tuntun.rangers/node/src/storage/account"
+	"encoding/json"
+	"fmt"
+)
+
+// evidenceExecutor slashes the group member proved misbehaving, no fee is charged for evidences
+type evidenceExecutor struct {
+	logger log.Logger
+}
+
+func (this *evidenceExecutor) BeforeExecute(tx *types.Transaction, header *types.BlockHeader, accountDB *account.AccountDB, context map[string]interface{}) (bool, bool, string) {
+	if !common.CurrentRules().IsProposal030 {
+		msg := fmt.Sprintf("evidence is not supported, tx: %s", tx.Hash.String())
+		this.logger.Errorf(msg)
+		return false, false, msg
+	}
+
+	evidence, err := this.decode(tx)
+	if nil != err {
+		msg := fmt.Sprintf("fail to unmarshal evidence, tx: %s, error: %s", tx.Hash.String(), err.Error())
+		this.logger.Errorf(msg)
+		return false, false, msg
+	}
+
+	situation, _ := context["situation"].(string)
+	if err := service.EvidenceManagerImpl.Verify(evidence, tx.Source, header.Height, situation, accountDB); nil != err {
+		msg := fmt.Sprintf("wrong evidence, tx: %s, error: %s", tx.Hash.String(), err.Error())
+		this.logger.Errorf(msg)
+		return false, false, msg
+	}
+	return true, true, ""
+}
+
+func (this *evidenceExecutor) Execute(tx *types.Transaction, header *types.BlockHeader, accountDB *account.AccountDB, context map[string]interface{}) (bool, string) {
+	evidence, err := this.decode(tx)
+	if nil != err {
+		return false, err.Error()
+	}
+
+	situation, _ := context["situation"].(string)
+	return service.EvidenceManagerImpl.Apply(evidence, header.Height, situation, accountDB)
+}
+
+func (this *evidenceExecutor) decode(tx *types.Transaction) (*types.Evidence, error) {
+	var evidence types.Evidence
+	if err := json.Unmarshal([]byte(tx.Data), &evidence); nil != err {
+		return nil, err
+	}
+	return &evidence, nil
+}
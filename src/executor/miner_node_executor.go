@@ -73,6 +73,7 @@ func (this *minerNodeExecutor) Execute(transaction *types.Transaction, header *t
 	vmCtx.BlockNumber = new(big.Int).SetUint64(header.Height)
 	vmCtx.Time = new(big.Int).SetUint64(uint64(header.CurTime.Unix()))
 	vmCtx.Difficulty = new(big.Int).SetUint64(123)
+	vmCtx.GetRandom = getRandomFn(context["chain"].(ChainContext), header)
 	vmCtx.GasPrice = defaultGasPrice
 	vmCtx.GasLimit = defaultGasLimit
 
@@ -46,6 +46,7 @@ func InitExecutors() {
 	executors[types.TransactionTypeMinerRefund] = &minerRefundExecutor{logger: logger}
 	executors[types.TransactionTypeMinerChangeAccount] = &minerChangeAccountExecutor{logger: logger}
 	executors[types.TransactionTypeOperatorNode] = &minerNodeExecutor{logger: logger}
+	executors[types.TransactionTypeEvidence] = &evidenceExecutor{logger: logger}
 
 	contractExecutorInstance := &contractExecutor{logger: logger}
 	executors[types.TransactionTypeContract] = contractExecutorInstance
@@ -18,6 +18,7 @@ package cli
 
 import (
 	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/common/keystore"
 	"com.tuntun.rangers/node/src/consensus/model"
 	"com.tuntun.rangers/node/src/middleware/db"
 	"com.tuntun.rangers/node/src/utility"
@@ -25,19 +26,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"sync"
 	"time"
 )
 
 const accountUnLockTime = time.Second * 120
 
-var encryptPrivateKey *common.PrivateKey
-var encryptPublicKey *common.PublicKey
+// legacyPrivateKey decrypts the accounts of the leveldb keystore used by the older versions
+var legacyPrivateKey *common.PrivateKey
 
 func init() {
-	encryptPrivateKey = common.HexStringToSecKey("0x4b3f1ea7c7fd3666ce8334301236c2437d9bed14e5a0793b51a9a6e7a4c46e70")
-	pk := encryptPrivateKey.GetPubKey()
-	encryptPublicKey = &pk
+	legacyPrivateKey = common.HexStringToSecKey("0x4b3f1ea7c7fd3666ce8334301236c2437d9bed14e5a0793b51a9a6e7a4c46e70")
 }
 
 const (
@@ -45,13 +45,18 @@ const (
 	statusUnLocked      = 1
 
 	defaultPassword = "123"
+
+	keystoreDir = "keystore"
+	// legacyKeystore is the leveldb keystore of the older versions, its accounts are moved to keystoreDir on unlock
+	legacyKeystore = "keystore"
 )
 
 type AccountManager struct {
 	unlockAccount *AccountInfo
 
 	accounts sync.Map
-	db       *db.LDBDatabase
+	ks       *keystore.KeyStore
+	legacy   *db.LDBDatabase
 
 	mu sync.Mutex
 }
@@ -63,11 +68,10 @@ type AccountInfo struct {
 }
 
 type Account struct {
-	Address  string
-	Pk       string
-	Sk       string
-	Password string
-	Miner    *MinerRaw
+	Address string
+	Pk      string
+	Sk      string
+	Miner   *MinerRaw
 }
 
 type MinerRaw struct {
@@ -78,6 +82,12 @@ type MinerRaw struct {
 	ID    []byte
 }
 
+// legacyAccount is the account stored in the leveldb keystore
+type legacyAccount struct {
+	Account
+	Password string
+}
+
 func getAccountByPrivateKey(pk string) Account {
 	var privateKey *common.PrivateKey
 	// secpk256
@@ -110,41 +120,63 @@ func getAccountByPrivateKey(pk string) Account {
 	return account
 }
 
+// NewAccount creates an account in the keystore.
+// The miner keys are derived from the account key, so every account can be used as a miner.
 func (am *AccountManager) NewAccount(password string, miner bool) *Result {
-	privateKey := common.GenerateKey("")
-	publicKey := privateKey.GetPubKey()
-	address := publicKey.GetAddress()
+	key, err := am.ks.NewAccount(password)
+	if err != nil {
+		return opError(err)
+	}
+	return opSuccess(key.Address.GetHexString())
+}
 
-	account := &Account{
-		Address:  address.GetHexString(),
-		Pk:       publicKey.GetHexString(),
-		Sk:       privateKey.GetHexString(),
-		Password: passwordSha(password),
-	}
-
-	if miner {
-		id := publicKey.GetID()
-		minerDO := model.NewSelfMinerInfo(privateKey)
-		minerRaw := &MinerRaw{
-			BPk:   minerDO.PubKey.GetHexString(),
-			BSk:   minerDO.SecKey.GetHexString(),
-			VrfPk: minerDO.VrfPK.GetHexString(),
-			VrfSk: minerDO.VrfSK.GetHexString(),
-			ID:    id,
-		}
-		account.Miner = minerRaw
+// ImportAccount imports a keystore json file, such as the ones of geth or metamask
+func (am *AccountManager) ImportAccount(keyJSON []byte, password string) *Result {
+	key, err := am.ks.Import(keyJSON, password)
+	if err == keystore.ErrDecrypt {
+		return opError(ErrPassword)
 	}
-	if err := am.storeAccount(account); err != nil {
+	if err != nil {
 		return opError(err)
 	}
-	return opSuccess(address.GetHexString())
+	return opSuccess(key.Address.GetHexString())
+}
+
+// ImportPrivateKey imports a hex private key, encrypted with the password
+func (am *AccountManager) ImportPrivateKey(privateKey string, password string) *Result {
+	key, err := keystore.NewKeyFromHex(privateKey)
+	if err != nil {
+		return opError(err)
+	}
+	if err := am.ks.StoreKey(key, password); err != nil {
+		return opError(err)
+	}
+	return opSuccess(key.Address.GetHexString())
+}
+
+// ExportAccount returns the keystore json of the account
+func (am *AccountManager) ExportAccount(addr string) *Result {
+	keyJSON, err := am.ks.Export(common.HexToAddress(addr))
+	if err == keystore.ErrNoMatch && am.hasLegacyAccount(addr) {
+		return opError(fmt.Errorf("please unlock the account to move it into the keystore first"))
+	}
+	if err != nil {
+		return opError(err)
+	}
+	return opSuccess(string(keyJSON))
 }
 
 func (am *AccountManager) AccountList() *Result {
-	iter := am.db.NewIterator()
 	addrs := make([]string, 0)
-	for iter.Next() {
-		addrs = append(addrs, string(iter.Key()))
+	for _, address := range am.ks.Accounts() {
+		addrs = append(addrs, address.GetHexString())
+	}
+	if nil != am.legacy {
+		iter := am.legacy.NewIterator()
+		for iter.Next() {
+			addrs = append(addrs, common.BytesToAddress(iter.Key()).GetHexString())
+		}
+		iter.Release()
 	}
 	return opSuccess(addrs)
 }
@@ -159,13 +191,13 @@ func (am *AccountManager) Lock(addr string) *Result {
 }
 
 func (am *AccountManager) UnLock(addr string, password string) *Result {
-	aci, err := am.getAccountInfo(addr)
+	key, err := am.getKey(addr, password)
 	if err != nil {
 		return opError(err)
 	}
-	if aci.Password != passwordSha(password) {
-		return opError(ErrPassword)
-	}
+	aci := &AccountInfo{Account: getAccountByPrivateKey(key.PrivateKey.GetHexString())}
+	am.accounts.Store(aci.Address, aci)
+
 	am.mu.Lock()
 	defer am.mu.Unlock()
 
@@ -208,19 +240,22 @@ func (am *AccountManager) DeleteAccount() *Result {
 	if !aci.unlocked() {
 		return opError(ErrUnlocked)
 	}
-	am.accounts.Delete(addr)
-	am.db.Delete([]byte(addr))
+	if err := am.ks.Delete(common.HexToAddress(addr)); err != nil {
+		return opError(err)
+	}
+	am.accounts.Delete(aci.Address)
 	return opSuccess(nil)
 }
 
 func (am *AccountManager) Close() {
-	am.db.Close()
+	if nil != am.legacy {
+		am.legacy.Close()
+	}
 }
 
 func initAccountManager(readyOnly bool) (*AccountManager, error) {
-	keystore := "keystore"
-	if readyOnly && !dirExists(keystore) {
-		accountManager, err := newAccountManager(keystore)
+	if readyOnly && !dirExists(keystoreDir) {
+		accountManager, err := newAccountManager(keystoreDir)
 		if err != nil {
 			panic(err)
 		}
@@ -233,8 +268,8 @@ func initAccountManager(readyOnly bool) (*AccountManager, error) {
 		return accountManager, nil
 	}
 
-	if accountManager, err := newAccountManager(keystore); err != nil {
-		fmt.Printf("new lelvel db error:%s\n", err.Error())
+	if accountManager, err := newAccountManager(keystoreDir); err != nil {
+		fmt.Printf("new keystore error:%s\n", err.Error())
 		return nil, err
 	} else {
 		return accountManager, nil
@@ -242,72 +277,88 @@ func initAccountManager(readyOnly bool) (*AccountManager, error) {
 }
 
 func newAccountManager(ks string) (*AccountManager, error) {
-	accountManagerDB, err := db.NewLDBDatabase(ks, 128, 128)
+	store, err := keystore.NewKeyStore(ks, keystore.StandardScryptN, keystore.StandardScryptP)
 	if err != nil {
-		fmt.Printf("new ldb failed:%v", err.Error())
-		return nil, fmt.Errorf("new ldb fail:%v", err.Error())
+		return nil, fmt.Errorf("new keystore fail:%v", err.Error())
 	}
-	return &AccountManager{db: accountManagerDB}, nil
-}
+	am := &AccountManager{ks: store}
 
-func (am *AccountManager) loadAccount(addr string) (*Account, error) {
-	//fmt.Printf("load account.addr:%v,key:%v", addr, common.FromHex(addr))
-	v, err := am.db.Get(common.FromHex(addr))
-	//v, err := am.db.Get([]byte(addr))
-	if err != nil {
-		fmt.Printf("load account err:%v\n", err.Error())
-		return nil, err
+	// open the leveldb keystore only if it exists, to move its accounts on unlock
+	if dirExists("storage" + strconv.Itoa(common.InstanceIndex) + "/" + legacyKeystore) {
+		am.legacy, err = db.NewLDBDatabase(legacyKeystore, 128, 128)
+		if err != nil {
+			return nil, fmt.Errorf("open legacy keystore fail:%v", err.Error())
+		}
 	}
+	return am, nil
+}
 
-	bs, err := encryptPrivateKey.Decrypt(rand.Reader, v)
-	if err != nil {
-		return nil, err
+// getKey decrypts the key of the account, moving it from the leveldb keystore if needed
+func (am *AccountManager) getKey(addr string, password string) (*keystore.Key, error) {
+	address := common.HexToAddress(addr)
+	if !am.ks.HasAddress(address) && am.hasLegacyAccount(addr) {
+		return am.migrateLegacyAccount(addr, password)
 	}
 
-	var acc = new(Account)
-	err = json.Unmarshal(bs, acc)
-	if err != nil {
-		return nil, err
+	key, err := am.ks.GetKey(address, password)
+	if err == keystore.ErrDecrypt {
+		return nil, ErrPassword
 	}
+	return key, err
+}
 
-	pk := common.HexStringToPubKey(acc.Pk)
-	address := pk.GetAddress()
-	acc.Address = address.String()
-
-	bs, _ = json.Marshal(acc)
-	fmt.Println("accout info:" + string(bs))
+func (am *AccountManager) hasLegacyAccount(addr string) bool {
+	return nil != am.legacyKey(addr)
+}
 
-	return acc, nil
+// legacyKey returns the key of the account in the leveldb keystore, which is the miner id ending with the address
+func (am *AccountManager) legacyKey(addr string) []byte {
+	if nil == am.legacy {
+		return nil
+	}
+	address := common.HexToAddress(addr)
+	iter := am.legacy.NewIterator()
+	defer iter.Release()
+	for iter.Next() {
+		if common.BytesToAddress(iter.Key()) == address {
+			return common.CopyBytes(iter.Key())
+		}
+	}
+	return nil
 }
 
-func (am *AccountManager) storeAccount(account *Account) error {
-	bs, err := json.Marshal(account)
+// migrateLegacyAccount checks the password of the account in the leveldb keystore,
+// then encrypts the account with the password into the keystore
+func (am *AccountManager) migrateLegacyAccount(addr string, password string) (*keystore.Key, error) {
+	legacyKey := am.legacyKey(addr)
+	v, err := am.legacy.Get(legacyKey)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	ct, err := common.Encrypt(rand.Reader, encryptPublicKey, bs)
+	bs, err := legacyPrivateKey.Decrypt(rand.Reader, v)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	var acc legacyAccount
+	if err := json.Unmarshal(bs, &acc); err != nil {
+		return nil, err
+	}
+	if acc.Password != passwordSha(password) {
+		return nil, ErrPassword
 	}
 
-	err = am.db.Put(account.Miner.ID[:], ct)
-	//fmt.Printf("store account:%v,key:%v,err:%v\n", account.Miner.ID[:], account.Miner.ID[:], err)
-	return err
-}
-
-func (am *AccountManager) getFirstMinerAccount() *Account {
-	iter := am.db.NewIterator()
-	for iter.Next() {
-		if ac, err := am.getAccountInfo(common.Bytes2Hex(iter.Key())); err != nil {
-			panic(fmt.Sprintf("getAccountInfo err,addr=%v,err=%v", iter.Key(), err.Error()))
-		} else {
-			if ac.Miner != nil {
-				return &ac.Account
-			}
-		}
+	key, err := keystore.NewKeyFromHex(acc.Sk)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	if err := am.ks.StoreKey(key, password); err != nil {
+		return nil, err
+	}
+	if err := am.legacy.Delete(legacyKey); err != nil {
+		return nil, err
+	}
+	fmt.Printf("account %s moved into keystore %s\n", key.Address.GetHexString(), am.ks.Dir())
+	return key, nil
 }
 
 func (am *AccountManager) resetExpireTime(addr string) {
@@ -318,21 +369,12 @@ func (am *AccountManager) resetExpireTime(addr string) {
 	acc.resetExpireTime()
 }
 
+// getAccountInfo returns the account unlocked before
 func (am *AccountManager) getAccountInfo(addr string) (*AccountInfo, error) {
-	var aci *AccountInfo
-	if v, ok := am.accounts.Load(addr); ok {
-		aci = v.(*AccountInfo)
-	} else {
-		acc, err := am.loadAccount(addr)
-		if err != nil {
-			return nil, err
-		}
-		aci = &AccountInfo{
-			Account: *acc,
-		}
-		am.accounts.Store(addr, aci)
+	if v, ok := am.accounts.Load(common.HexToAddress(addr).GetHexString()); ok {
+		return v.(*AccountInfo), nil
 	}
-	return aci, nil
+	return nil, ErrUnlocked
 }
 
 func (am *AccountManager) currentUnLockedAddr() string {
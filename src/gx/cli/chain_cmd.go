@@ -0,0 +1,148 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/consensus"
+	"com.tuntun.rangers/node/src/core"
+	"com.tuntun.rangers/node/src/middleware"
+	"com.tuntun.rangers/node/src/middleware/log"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"com.tuntun.rangers/node/src/service"
+	"com.tuntun.rangers/node/src/storage/account"
+	"com.tuntun.rangers/node/src/vm"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// initChain loads the local chain of a stopped node without connecting to the gateway
+func (gx *GX) initChain(instanceIndex int, configFile, env string) error {
+	common.Init(instanceIndex, configFile, env)
+
+	signer, minerInfo, err := gx.loadMinerKey()
+	if err != nil {
+		return err
+	}
+	common.GlobalConf.SetString(Section, "miner", minerInfo.ID.GetHexString())
+
+	account.Init()
+	middleware.InitMiddleware()
+	service.InitService()
+	vm.InitVM()
+
+	if err := core.InitCore(consensus.NewConsensusHelper(minerInfo.ID), signer, minerInfo.ID.GetHexString()); err != nil {
+		return err
+	}
+	if !consensus.InitConsensus(minerInfo, signer, common.GlobalConf) {
+		return fmt.Errorf("init consensus error")
+	}
+	return nil
+}
+
+func closeChain() {
+	if core.GetBlockChain() != nil {
+		core.GetBlockChain().Close()
+	}
+	service.Close()
+	middleware.Close()
+	log.Close()
+}
+
+// exportChain writes blocks in [from, to] into file. to 0 means the top block
+func exportChain(file string, from, to uint64) error {
+	if 0 == to {
+		to = math.MaxUint64
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	start := time.Now()
+	blocks, groups, err := core.ExportChain(f, from, to)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d blocks and %d groups to %s, cost: %s\n", blocks, groups, file, time.Since(start).String())
+	return f.Sync()
+}
+
+// importChain replays blocks in file on the local chain
+func importChain(file string, trusted bool) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	start := time.Now()
+	blocks, groups, err := core.ImportChain(f, trusted)
+	fmt.Printf("Imported %d blocks and %d groups from %s, cost: %s\n", blocks, groups, file, time.Since(start).String())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Top block height: %d, hash: %s\n", core.GetBlockChain().Height(), core.GetBlockChain().TopBlock().Hash.String())
+	return nil
+}
+
+// exportSnapshot writes the state at height into file. height 0 means the top block
+func exportSnapshot(file string, height uint64) error {
+	if 0 == height {
+		height = core.GetBlockChain().Height()
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	start := time.Now()
+	manifest, accounts, err := core.ExportSnapshot(f, height)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Exported snapshot of %d accounts to %s, cost: %s\n", accounts, file, time.Since(start).String())
+	fmt.Printf("Height: %d, hash: %s, state: %s\n", manifest.Height, manifest.Hash.String(), manifest.StateRoot.String())
+	return f.Sync()
+}
+
+// importSnapshot writes the snapshot into the databases of a new node.
+// A node with chain data ignores the snapshot
+func importSnapshot(file string, helper types.ConsensusHelper) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	start := time.Now()
+	manifest, err := core.ImportSnapshot(f, helper)
+	if err == core.ErrChainExisted {
+		fmt.Println("Chain data existed, snapshot ignored")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Imported snapshot from %s, cost: %s\n", file, time.Since(start).String())
+	fmt.Printf("Height: %d, hash: %s, state: %s\n", manifest.Height, manifest.Hash.String(), manifest.StateRoot.String())
+	return nil
+}
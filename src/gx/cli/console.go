@@ -53,7 +53,7 @@ func genNewAccountCmd() *newAccountCmd {
 		baseCmd: *genbaseCmd("newaccount", "create account"),
 	}
 	c.fs.StringVar(&c.password, "password", "", "password for the account")
-	c.fs.BoolVar(&c.miner, "miner", false, "create the account for miner if set")
+	c.fs.BoolVar(&c.miner, "miner", false, "deprecated, every account has the miner keys")
 	return c
 }
 
@@ -71,6 +71,69 @@ func (c *newAccountCmd) parse(args []string) bool {
 	return true
 }
 
+type importAccountCmd struct {
+	baseCmd
+	file       string
+	privateKey string
+	password   string
+}
+
+func genImportAccountCmd() *importAccountCmd {
+	c := &importAccountCmd{
+		baseCmd: *genbaseCmd("importaccount", "import a keystore json file or a private key"),
+	}
+	c.fs.StringVar(&c.file, "file", "", "the keystore json file, such as the ones of geth or metamask")
+	c.fs.StringVar(&c.privateKey, "key", "", "the hex private key")
+	c.fs.StringVar(&c.password, "password", "", "password of the keystore json file, or for the private key")
+	return c
+}
+
+func (c *importAccountCmd) parse(args []string) bool {
+	if err := c.fs.Parse(args); err != nil {
+		fmt.Println(err.Error())
+		return false
+	}
+	if (strings.TrimSpace(c.file) == "") == (strings.TrimSpace(c.privateKey) == "") {
+		fmt.Println("please input either the file or the private key")
+		c.fs.PrintDefaults()
+		return false
+	}
+	if strings.TrimSpace(c.password) == "" {
+		fmt.Println("please input the password")
+		c.fs.PrintDefaults()
+		return false
+	}
+	return true
+}
+
+type exportAccountCmd struct {
+	baseCmd
+	addr string
+	file string
+}
+
+func genExportAccountCmd() *exportAccountCmd {
+	c := &exportAccountCmd{
+		baseCmd: *genbaseCmd("exportaccount", "export the keystore json file of the account"),
+	}
+	c.fs.StringVar(&c.addr, "addr", "", "the account address")
+	c.fs.StringVar(&c.file, "file", "", "the file to write")
+	return c
+}
+
+func (c *exportAccountCmd) parse(args []string) bool {
+	if err := c.fs.Parse(args); err != nil {
+		fmt.Println(err.Error())
+		return false
+	}
+	if strings.TrimSpace(c.addr) == "" || strings.TrimSpace(c.file) == "" {
+		fmt.Println("please input the address and the file")
+		c.fs.PrintDefaults()
+		return false
+	}
+	return true
+}
+
 type unlockCmd struct {
 	baseCmd
 	addr string
@@ -494,6 +557,8 @@ func (c *viewContractCmd) parse(args []string) bool {
 var cmdNewAccount = genNewAccountCmd()
 var cmdExit = genbaseCmd("exit", "quit  gtas")
 var cmdHelp = genbaseCmd("help", "show help info")
+var cmdImportAccount = genImportAccountCmd()
+var cmdExportAccount = genExportAccountCmd()
 var cmdAccountList = genbaseCmd("accountlist", "list the account of the keystore")
 var cmdUnlock = genUnlockCmd()
 var cmdBalance = genBalanceCmd()
@@ -517,6 +582,8 @@ var list = make([]*baseCmd, 0)
 func init() {
 	list = append(list, cmdHelp)
 	list = append(list, &cmdNewAccount.baseCmd)
+	list = append(list, &cmdImportAccount.baseCmd)
+	list = append(list, &cmdExportAccount.baseCmd)
 	list = append(list, cmdAccountList)
 	list = append(list, &cmdUnlock.baseCmd)
 	list = append(list, &cmdBalance.baseCmd)
@@ -671,6 +738,34 @@ func loop(acm accountOp, chainOp chainOp) {
 			os.Exit(0)
 		case cmdHelp.name:
 			Usage()
+		case cmdImportAccount.name:
+			cmd := genImportAccountCmd()
+			if cmd.parse(args) {
+				handleCmd(func() *Result {
+					if cmd.privateKey != "" {
+						return acm.ImportPrivateKey(cmd.privateKey, cmd.password)
+					}
+					keyJSON, err := ioutil.ReadFile(cmd.file)
+					if err != nil {
+						return opError(err)
+					}
+					return acm.ImportAccount(keyJSON, cmd.password)
+				})
+			}
+		case cmdExportAccount.name:
+			cmd := genExportAccountCmd()
+			if cmd.parse(args) {
+				handleCmd(func() *Result {
+					ret := acm.ExportAccount(cmd.addr)
+					if !ret.IsSuccess() {
+						return ret
+					}
+					if err := ioutil.WriteFile(cmd.file, []byte(ret.Data.(string)), 0600); err != nil {
+						return opError(err)
+					}
+					return opSuccess(cmd.file)
+				})
+			}
 		case cmdAccountList.name:
 			handleCmd(func() *Result {
 				return acm.AccountList()
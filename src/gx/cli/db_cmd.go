@@ -0,0 +1,192 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"bytes"
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/core"
+	"com.tuntun.rangers/node/src/middleware/db"
+	"com.tuntun.rangers/node/src/storage/account"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// databases under storage<N> of a node
+const (
+	chainDBName = "chain"
+	stateDBName = "state"
+	txDBName    = "tx"
+
+	// scratch database of prune-state
+	pruneSetDBName = "prune_reachable"
+)
+
+var (
+	storageDBNames = []string{chainDBName, stateDBName, txDBName, "jgs", "pkp"}
+
+	// PrefixedDatabase prefixes in the chain database.
+	// Longer ones come first since "block" is also the beginning of "blockFork".
+	chainDBPrefixes = []string{"blockFork", "groupFork", "verifyHash", "tx_list_", "height", "block", "group", "tx"}
+
+	preimagePrefix = []byte("secure-key-")
+)
+
+type dbStat struct {
+	count int
+	size  common.StorageSize
+}
+
+func storageDir() string {
+	return "storage" + strconv.Itoa(common.InstanceIndex)
+}
+
+// openStorageDB opens an existing database of a stopped node
+func openStorageDB(name string) (*db.LDBDatabase, error) {
+	if _, err := os.Stat(storageDir() + "/" + name); err != nil {
+		return nil, err
+	}
+	return db.NewLDBDatabase(name, 16, 16)
+}
+
+func keyCategory(name string, key []byte) string {
+	switch name {
+	case chainDBName:
+		for _, prefix := range chainDBPrefixes {
+			if bytes.HasPrefix(key, []byte(prefix)) {
+				return prefix
+			}
+		}
+	case stateDBName:
+		if common.HashLength == len(key) {
+			return "trie nodes"
+		}
+		if bytes.HasPrefix(key, preimagePrefix) {
+			return "preimages"
+		}
+	case txDBName:
+		if common.HashLength == len(key) {
+			return "executed txs"
+		}
+	default:
+		return "all"
+	}
+	return "other"
+}
+
+// inspectDB prints key counts and sizes of every database grouped by key prefix
+func inspectDB() error {
+	var total dbStat
+	fmt.Printf("%-8s %-16s %14s %14s\n", "Database", "Category", "Keys", "Size")
+	for _, name := range storageDBNames {
+		ldb, err := openStorageDB(name)
+		if err != nil {
+			continue
+		}
+
+		stats := make(map[string]*dbStat)
+		it := ldb.NewIterator()
+		for it.Next() {
+			category := keyCategory(name, it.Key())
+			stat, ok := stats[category]
+			if !ok {
+				stat = &dbStat{}
+				stats[category] = stat
+			}
+			stat.count++
+			stat.size += common.StorageSize(len(it.Key()) + len(it.Value()))
+		}
+		err = it.Error()
+		it.Release()
+		ldb.Close()
+		if err != nil {
+			return err
+		}
+
+		categories := make([]string, 0, len(stats))
+		for category := range stats {
+			categories = append(categories, category)
+		}
+		sort.Strings(categories)
+		for _, category := range categories {
+			stat := stats[category]
+			fmt.Printf("%-8s %-16s %14d %14s\n", name, category, stat.count, stat.size.String())
+			total.count += stat.count
+			total.size += stat.size
+		}
+	}
+	fmt.Printf("%-8s %-16s %14d %14s\n", "Total", "", total.count, total.size.String())
+	return nil
+}
+
+// pruneStateDB deletes the state nodes which are unreachable from the latest block state
+func pruneStateDB() error {
+	chainDB, err := openStorageDB(chainDBName)
+	if err != nil {
+		return err
+	}
+	header := core.ReadLatestBlockHeader(chainDB)
+	chainDB.Close()
+	if nil == header {
+		return fmt.Errorf("latest block not found in %s", storageDir())
+	}
+
+	stateDB, err := openStorageDB(stateDBName)
+	if err != nil {
+		return err
+	}
+	defer stateDB.Close()
+
+	// the reachable hashes are kept on disk, a leftover of an interrupted run is dropped
+	setPath := storageDir() + "/" + pruneSetDBName
+	os.RemoveAll(setPath)
+	setDB, err := db.NewLDBDatabase(pruneSetDBName, 16, 16)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(setPath)
+	defer setDB.Close()
+
+	fmt.Printf("Pruning state. height: %d, state: %s\n", header.Height, header.StateTree.String())
+	count, size, err := account.PruneState(stateDB, setDB, header.StateTree)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Deleted %d nodes, %s\n", count, size.String())
+
+	fmt.Println("Compacting state database")
+	return stateDB.Compact(nil, nil)
+}
+
+// compactDB compacts every database of the node
+func compactDB() error {
+	for _, name := range storageDBNames {
+		ldb, err := openStorageDB(name)
+		if err != nil {
+			continue
+		}
+		fmt.Printf("Compacting %s\n", name)
+		err = ldb.Compact(nil, nil)
+		ldb.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -37,7 +37,9 @@ type ETHServer struct {
 
 func (server *ETHServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// preflight
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if origin := eth_rpc.AllowedOrigin(r.Header.Get("Origin")); origin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+	}
 	w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
 	w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
 	w.Header().Set("Content-Type", "application/json")
@@ -46,10 +48,14 @@ func (server *ETHServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if maxBodySize := eth_rpc.MaxBodySize(); maxBodySize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, int64(maxBodySize))
+	}
 	body, err := ioutil.ReadAll(r.Body)
 	defer r.Body.Close()
 	if err != nil {
 		common.DefaultLogger.Errorf("fail to get postdata: %s, %s", r.URL.Path, err.Error())
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
 		return
 	}
 
@@ -75,7 +81,7 @@ func (server *ETHServer) processJSONRPC(w http.ResponseWriter, r *http.Request,
 	err := json.Unmarshal(body, &message)
 	if err == nil {
 		common.DefaultLogger.Debugf("single data: %s, %s", r.URL.Path, utility.BytesToStr(body))
-		res := handler.ProcessSingleRequest(message, 0)
+		res := handler.ProcessSingleRequest(message, 0, eth_rpc.HTTPListener, r.RemoteAddr)
 		responseJson, err := json.Marshal(res)
 		if err != nil {
 			common.DefaultLogger.Errorf("fail to process postdata: %s, %s", r.URL.Path, err.Error())
@@ -91,7 +97,7 @@ func (server *ETHServer) processJSONRPC(w http.ResponseWriter, r *http.Request,
 	err = json.Unmarshal(body, &messages)
 	if err == nil {
 		common.DefaultLogger.Debugf("batch data: %s, %s", r.URL.Path, utility.BytesToStr(body))
-		res := handler.ProcessBatchRequest(messages, 0)
+		res := handler.ProcessBatchRequest(messages, 0, eth_rpc.HTTPListener, r.RemoteAddr)
 		responseJson, err := json.Marshal(res)
 		if err != nil {
 			common.DefaultLogger.Errorf("fail to process batch postdata: %s, %s", r.URL.Path, err.Error())
@@ -89,6 +89,7 @@ func (sub *Subscription) Unsubscribe() {
 				break uninstallLoop
 			case <-sub.f.logs:
 			case <-sub.f.headers:
+			case <-sub.f.hashes:
 			}
 		}
 
@@ -127,6 +128,7 @@ type subscription struct {
 	logsCrit  FilterQuery
 	logs      chan []*types.Log
 	headers   chan *types.BlockHeader
+	hashes    chan []common.Hash
 	installed chan struct{} // closed when the filter is installed
 	err       chan error    // closed when the filter is uninstalled
 }
@@ -140,6 +142,7 @@ type EventSystem struct {
 	logsCh    chan []*types.Log       // Channel to receive new log event
 	headerCh  chan *types.BlockHeader //channel to receive new block header
 	rmLogsCh  chan []*types.Log       // Channel to receive removed log event
+	txsCh     chan []common.Hash      // Channel to receive new pending tx hashes
 
 	logger log.Logger
 }
@@ -157,12 +160,15 @@ func newEventSystem() *EventSystem {
 		logsCh:    make(chan []*types.Log, logsChanSize),
 		headerCh:  make(chan *types.BlockHeader, chainEvChanSize),
 		rmLogsCh:  make(chan []*types.Log, rmLogsChanSize),
+		txsCh:     make(chan []common.Hash, txChanSize),
 	}
 	m.logger = log.GetLoggerByIndex(log.EventSubLogConfig, common.GlobalConf.GetString("instance", "index", ""))
 
 	go m.eventLoop()
 	notify.BUS.Subscribe(notify.VMEventNotify, m)
 	notify.BUS.Subscribe(notify.BlockHeaderNotify, m)
+	notify.BUS.Subscribe(notify.VMRemovedEventNotify, m)
+	notify.BUS.Subscribe(notify.NewPendingTxNotify, m)
 	return m
 }
 
@@ -172,6 +178,10 @@ func (es *EventSystem) HandleNetMessage(topic string, msg notify.Message) {
 		es.vmEventHandler(msg)
 	case notify.BlockHeaderNotify:
 		es.newBlockHeaderHandler(msg)
+	case notify.VMRemovedEventNotify:
+		es.vmRemovedEventHandler(msg)
+	case notify.NewPendingTxNotify:
+		es.newPendingTxHandler(msg)
 	}
 }
 
@@ -275,6 +285,21 @@ func (es *EventSystem) SubscribeNewHeads(headers chan *types.BlockHeader) *Subsc
 	return es.subscribe(sub)
 }
 
+// SubscribePendingTxs creates a subscription that writes transaction hashes for
+// transactions that enter the transaction pool.
+func (es *EventSystem) SubscribePendingTxs(hashes chan []common.Hash) *Subscription {
+	sub := &subscription{
+		id:        rpc.NewID(),
+		typ:       PendingTransactionsSubscription,
+		created:   time.Now(),
+		logs:      make(chan []*types.Log),
+		hashes:    hashes,
+		installed: make(chan struct{}),
+		err:       make(chan error),
+	}
+	return es.subscribe(sub)
+}
+
 // subscribe installs the subscription in the event broadcast loop.
 func (es *EventSystem) subscribe(sub *subscription) *Subscription {
 	es.install <- sub
@@ -317,6 +342,20 @@ func (es *EventSystem) vmRemovedEventHandler(msg notify.Message) {
 	es.rmLogsCh <- logs
 }
 
+func (es *EventSystem) newPendingTxHandler(msg notify.Message) {
+	msg, ok := msg.(*notify.NewPendingTxNotifyMessage)
+	if !ok {
+		es.logger.Errorf("NewPendingTxNotifyMessage GetData assert not ok!")
+		return
+	}
+	txs := msg.GetData().([]*types.Transaction)
+	hashes := make([]common.Hash, 0, len(txs))
+	for _, tx := range txs {
+		hashes = append(hashes, tx.Hash)
+	}
+	es.txsCh <- hashes
+}
+
 // eventLoop (un)installs filters and processes mux events.
 func (es *EventSystem) eventLoop() {
 	index := make(filterIndex)
@@ -332,6 +371,8 @@ func (es *EventSystem) eventLoop() {
 			es.handleNewBlockHeader(index, ev)
 		case ev := <-es.rmLogsCh:
 			es.handleRemovedLogs(index, ev)
+		case ev := <-es.txsCh:
+			es.handlePendingTxs(index, ev)
 		case f := <-es.install:
 			if f.typ == MinedAndPendingLogsSubscription {
 				// the type are logs and pending logs subscriptions
@@ -374,6 +415,12 @@ func (es *EventSystem) handleNewBlockHeader(filters filterIndex, header *types.B
 	}
 }
 
+func (es *EventSystem) handlePendingTxs(filters filterIndex, hashes []common.Hash) {
+	for _, f := range filters[PendingTransactionsSubscription] {
+		f.hashes <- hashes
+	}
+}
+
 func (es *EventSystem) handleRemovedLogs(filters filterIndex, ev []*types.Log) {
 	if len(ev) == 0 {
 		return
@@ -0,0 +1,35 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/utility"
+	"fmt"
+)
+
+// printForks prints the fork schedule of the chain, with the forks of genesis.json applied for a sub chain
+func printForks() {
+	fmt.Printf("Chain ID:%s, Network ID:%s\n", common.ChainId(utility.MaxUint64), common.NetworkId())
+	for _, fork := range common.LocalChainConfig.Forks.Forks() {
+		if fork.Block == common.NeverForked {
+			fmt.Printf("%s\tnever\n", fork.Name)
+		} else {
+			fmt.Printf("%s\t%d\n", fork.Name, fork.Block)
+		}
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"com.tuntun.rangers/node/src/eth_rpc"
 	"com.tuntun.rangers/node/src/middleware"
 	"com.tuntun.rangers/node/src/middleware/log"
+	"com.tuntun.rangers/node/src/middleware/metrics"
 	"com.tuntun.rangers/node/src/middleware/types"
 	"com.tuntun.rangers/node/src/network"
 	"com.tuntun.rangers/node/src/service"
@@ -35,6 +36,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"gopkg.in/alecthomas/kingpin.v2"
+	"net"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
@@ -49,10 +51,19 @@ const (
 type GX struct {
 	init    bool
 	account Account
+
+	// state snapshot to boot from
+	snapshot string
+
+	keyConfig minerKeyConfig
+
+	// the admin ipc endpoint, admin_shutdown exits as on SIGTERM
+	ipc      net.Listener
+	shutdown chan bool
 }
 
 func NewGX() *GX {
-	return &GX{}
+	return &GX{shutdown: make(chan bool, 1)}
 }
 
 func (gx *GX) Run() {
@@ -65,7 +76,10 @@ func (gx *GX) Run() {
 
 	configFile := app.Flag("config", "Config file").Default("rp.ini").String()
 
-	_ = app.Flag("metrics", "enable metrics").Bool()
+	metricsEnabled := app.Flag("metrics", "enable metrics").Bool()
+	metricsAddr := app.Flag("metricsaddr", "the metrics http address serving /metrics").Default("127.0.0.1:6060").String()
+	healthAddr := app.Flag("healthaddr", "the http address serving /healthz and /readyz, disabled if empty").String()
+	stallWindow := app.Flag("stallwindow", "the node is unhealthy if the block height stalls over the window").Default(defaultStallWindow.String()).Duration()
 	_ = app.Flag("dashboard", "enable metrics dashboard").Bool()
 
 	consoleCmd := app.Command("console", "start RangersProtocol console")
@@ -88,12 +102,57 @@ func (gx *GX) Run() {
 	gateAddrPoint := mineCmd.Flag("gateaddr", "the gate addr").String()
 	outerGateAddrPoint := mineCmd.Flag("outergateaddr", "the gate addr").String()
 	txAddrPoint := mineCmd.Flag("tx", "the tx queue addr").String()
+	keystoreFile := mineCmd.Flag("keystore", "the encrypted keystore file of the miner key").String()
+	passwordFile := mineCmd.Flag("passwordfile", "the file holding the keystore password, "+keystorePasswordEnv+" is used if not set").String()
+	signerEndpoint := mineCmd.Flag("signer", "the unix socket of the remote signer holding the miner key, unix:/path").String()
 
 	//fullnode
 	fullNodeCmd := app.Command("fullnode", "sync data full node")
 	fullNodeJSONPRCHttpPort := fullNodeCmd.Flag("httpport", "jsonrpc http port").Short('p').Default("7988").Uint()
 	fullNodeJSONPRCWSPort := fullNodeCmd.Flag("wsport", "jsonrpc ws port").Short('w').Default("7989").Uint()
 	fullNodeEnv := fullNodeCmd.Flag("env", "the environment application run in").String()
+	fullNodeSnapshot := fullNodeCmd.Flag("snapshot", "boot a new node from the state snapshot file").String()
+	fullNodeKeystore := fullNodeCmd.Flag("keystore", "the encrypted keystore file of the node key").String()
+	fullNodePasswordFile := fullNodeCmd.Flag("passwordfile", "the file holding the keystore password, "+keystorePasswordEnv+" is used if not set").String()
+	fullNodeSigner := fullNodeCmd.Flag("signer", "the unix socket of the remote signer holding the node key, unix:/path").String()
+
+	//remote signer
+	signerCmd := app.Command("signer", "serve the key in the keystore to the local nodes")
+	signerKeystore := signerCmd.Flag("keystore", "the encrypted keystore file").Required().String()
+	signerPasswordFile := signerCmd.Flag("passwordfile", "the file holding the keystore password, "+keystorePasswordEnv+" is used if not set").String()
+	signerListen := signerCmd.Flag("listen", "the unix socket to serve, unix:/path").Default("unix:signer.sock").String()
+
+	//db
+	dbCmd := app.Command("db", "inspect and maintain the database of a stopped node")
+	dbInstanceIndex := dbCmd.Flag("instance", "instance index").Short('i').Default("0").Int()
+	dbInspectCmd := dbCmd.Command("inspect", "show key counts and sizes of the databases")
+	dbPruneStateCmd := dbCmd.Command("prune-state", "delete state nodes unreachable from the latest block")
+	dbCompactCmd := dbCmd.Command("compact", "compact the databases")
+
+	//export and import
+	exportCmd := app.Command("export", "export blocks and groups of a stopped node into a file")
+	exportInstanceIndex := exportCmd.Flag("instance", "instance index").Short('i').Default("0").Int()
+	exportEnv := exportCmd.Flag("env", "the environment application run in").String()
+	exportFile := exportCmd.Arg("file", "archive file").Required().String()
+	exportFrom := exportCmd.Arg("from", "first block height").Default("0").Uint64()
+	exportTo := exportCmd.Arg("to", "last block height, 0 means the top block").Default("0").Uint64()
+	importCmd := app.Command("import", "import blocks and groups from a file into a stopped node")
+	importInstanceIndex := importCmd.Flag("instance", "instance index").Short('i').Default("0").Int()
+	importEnv := importCmd.Flag("env", "the environment application run in").String()
+	importTrusted := importCmd.Flag("trusted", "skip group signature verification of the trusted archive").Bool()
+	importFile := importCmd.Arg("file", "archive file").Required().String()
+
+	//snapshot
+	snapshotCmd := app.Command("snapshot", "state snapshot of a stopped node")
+	snapshotExportCmd := snapshotCmd.Command("export", "export the state, the recent headers and the groups at a block into a file")
+	snapshotInstanceIndex := snapshotExportCmd.Flag("instance", "instance index").Short('i').Default("0").Int()
+	snapshotEnv := snapshotExportCmd.Flag("env", "the environment application run in").String()
+	snapshotFile := snapshotExportCmd.Arg("file", "snapshot file").Required().String()
+	snapshotHeight := snapshotExportCmd.Arg("height", "block height, 0 means the top block").Default("0").Uint64()
+
+	//forks
+	forksCmd := app.Command("forks", "show the fork schedule of the chain")
+	forksEnv := forksCmd.Flag("env", "the environment application run in").String()
 
 	command, err := app.Parse(os.Args[1:])
 	if err != nil {
@@ -104,6 +163,10 @@ func (gx *GX) Run() {
 	case versionCmd.FullCommand():
 		fmt.Println("Version:", common.Version)
 		os.Exit(0)
+	case forksCmd.FullCommand():
+		common.Init(0, *configFile, *forksEnv)
+		printForks()
+		os.Exit(0)
 	case consoleCmd.FullCommand():
 		err := ConsoleInit(*remoteHost, *remotePort, *showRequest, *rpcPort)
 		if err != nil {
@@ -137,20 +200,82 @@ func (gx *GX) Run() {
 		txAddr := *txAddrPoint
 
 		walletManager = newWallets()
+		startMetrics(*metricsEnabled, *metricsAddr)
+		startHealthServer(*healthAddr, *stallWindow)
+		gx.keyConfig = minerKeyConfig{keystore: *keystoreFile, passwordFile: *passwordFile, signer: *signerEndpoint}
 		fmt.Println("Use config file: " + *configFile)
 		fmt.Printf("Env:%s, Chain ID:%s, Network ID:%s, Tx: %s\n", *env, common.ChainId(utility.MaxUint64), common.NetworkId(), txAddr)
 		gx.initMiner(*env, gateAddr, outerGateAddr, txAddr)
 
 		if *rpc {
-			err = StartRPC(addrRpc.String(), *portRpc, gx.account.Sk)
+			err = StartRPC(addrRpc.String(), *portRpc, gx.account)
 			if err != nil {
 				common.DefaultLogger.Infof(err.Error())
 				return
 			}
 		}
 	case fullNodeCmd.FullCommand():
+		startMetrics(*metricsEnabled, *metricsAddr)
+		startHealthServer(*healthAddr, *stallWindow)
+		gx.snapshot = *fullNodeSnapshot
+		gx.keyConfig = minerKeyConfig{keystore: *fullNodeKeystore, passwordFile: *fullNodePasswordFile, signer: *fullNodeSigner}
 		gx.initFullNode(*fullNodeEnv, *configFile, *fullNodeJSONPRCHttpPort, *fullNodeJSONPRCWSPort)
 		break
+	case signerCmd.FullCommand():
+		if err := runSigner(*signerKeystore, *signerPasswordFile, *signerListen); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	case dbInspectCmd.FullCommand(), dbPruneStateCmd.FullCommand(), dbCompactCmd.FullCommand():
+		common.InstanceIndex = *dbInstanceIndex
+		types.InitSerialzation()
+		switch command {
+		case dbInspectCmd.FullCommand():
+			err = inspectDB()
+		case dbPruneStateCmd.FullCommand():
+			err = pruneStateDB()
+		default:
+			err = compactDB()
+		}
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	case exportCmd.FullCommand():
+		err = gx.initChain(*exportInstanceIndex, *configFile, *exportEnv)
+		if err == nil {
+			err = exportChain(*exportFile, *exportFrom, *exportTo)
+		}
+		closeChain()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	case snapshotExportCmd.FullCommand():
+		err = gx.initChain(*snapshotInstanceIndex, *configFile, *snapshotEnv)
+		if err == nil {
+			err = exportSnapshot(*snapshotFile, *snapshotHeight)
+		}
+		closeChain()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	case importCmd.FullCommand():
+		err = gx.initChain(*importInstanceIndex, *configFile, *importEnv)
+		if err == nil {
+			err = importChain(*importFile, *importTrusted)
+		}
+		closeChain()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
 	}
 	<-quitChan
 }
@@ -161,11 +286,11 @@ func (gx *GX) initMiner(env, gateAddr, outerGateAddr, tx string) {
 		common.DefaultLogger.Infof("end initMiner")
 	}()
 
-	privateKey := common.GlobalConf.GetString(Section, "privateKey", "")
-	gx.getAccountInfo(privateKey)
+	signer, minerInfo, err := gx.loadMinerKey()
+	if err != nil {
+		panic("Init miner load key error:" + err.Error())
+	}
 	fmt.Println("Your Miner Address:", gx.account.Address)
-	sk := common.HexStringToSecKey(gx.account.Sk)
-	minerInfo := model.NewSelfMinerInfo(*sk)
 	common.GlobalConf.SetString(Section, "miner", minerInfo.ID.GetHexString())
 	gx.dumpAccountInfo(minerInfo)
 
@@ -175,11 +300,17 @@ func (gx *GX) initMiner(env, gateAddr, outerGateAddr, tx string) {
 
 	service.InitService()
 
+	if 0 != len(gx.snapshot) {
+		if err := importSnapshot(gx.snapshot, consensus.NewConsensusHelper(minerInfo.ID)); err != nil {
+			panic("Init miner import snapshot error:" + err.Error())
+		}
+	}
+
 	network.InitNetwork(cnet.MessageHandler, minerInfo.ID.Serialize(), env, gateAddr, outerGateAddr, 0 != len(outerGateAddr) && 0 != len(tx))
 
 	vm.InitVM()
 
-	err := core.InitCore(consensus.NewConsensusHelper(minerInfo.ID), *sk, minerInfo.ID.GetHexString())
+	err = core.InitCore(consensus.NewConsensusHelper(minerInfo.ID), signer, minerInfo.ID.GetHexString())
 	if err != nil {
 		panic("Init miner core init error:" + err.Error())
 	}
@@ -200,7 +331,7 @@ func (gx *GX) initMiner(env, gateAddr, outerGateAddr, tx string) {
 		network.GetNetInstance().InitTx(tx)
 	}
 
-	ok := consensus.InitConsensus(minerInfo, common.GlobalConf)
+	ok := consensus.InitConsensus(minerInfo, signer, common.GlobalConf)
 	if !ok {
 		panic("Init miner consensus init error!")
 
@@ -220,17 +351,31 @@ func (gx *GX) initMiner(env, gateAddr, outerGateAddr, tx string) {
 	}
 
 	eth_rpc.InitEthMsgHandler()
+	initFilterAPI()
+	if err := gx.startIPC(); err != nil {
+		panic("Init miner start ipc error:" + err.Error())
+	}
 	gx.init = true
 }
 
-func (gx *GX) getAccountInfo(sk string) {
-	if 0 == len(sk) {
-		privateKey := common.GenerateKey("")
-		sk = privateKey.GetHexString()
-		common.GlobalConf.SetString(Section, "privateKey", sk)
+func startMetrics(enabled bool, addr string) {
+	if !enabled {
+		return
 	}
+	if err := metrics.StartServer(addr); err != nil {
+		panic("start metrics server error:" + err.Error())
+	}
+	fmt.Printf("Metrics serving on http://%s/metrics\n", addr)
+}
 
-	gx.account = getAccountByPrivateKey(sk)
+func startHealthServer(addr string, stallWindow time.Duration) {
+	if 0 == len(addr) {
+		return
+	}
+	if err := startHealth(addr, stallWindow); err != nil {
+		panic("start health server error:" + err.Error())
+	}
+	fmt.Printf("Health serving on http://%s/healthz and /readyz\n", addr)
 }
 
 func syncChainInfo() {
@@ -258,7 +403,7 @@ func syncChainInfo() {
 				jsonObject.Put("localHeight", topBlock.Height)
 				jsonObject.Put("topBlockHash", topBlock.Hash.String())
 
-				if output && candidateHeight > 0 && topBlock.Height >= candidateHeight {
+				if output && isSynced(candidateHeight, topBlock.Height) {
 					fmt.Println("Sync data finished!")
 					fmt.Println("Start Mining...")
 					output = false
@@ -269,6 +414,24 @@ func syncChainInfo() {
 	}()
 }
 
+func isSynced(candidateHeight, localHeight uint64) bool {
+	return candidateHeight > 0 && localHeight >= candidateHeight
+}
+
+// syncStatus returns the candidate height, the local height and whether the local chain has caught up with the candidate
+func syncStatus() (uint64, uint64, bool) {
+	chain := core.GetBlockChain()
+	if nil == chain {
+		return 0, 0, false
+	}
+	candidateHeight := core.GetCandidateHeight()
+	topBlock := chain.TopBlock()
+	if nil == topBlock {
+		return candidateHeight, 0, false
+	}
+	return candidateHeight, topBlock.Height, isSynced(candidateHeight, topBlock.Height)
+}
+
 func checkStatus() {
 	go func() {
 		for range time.Tick(10 * time.Second) {
@@ -296,12 +459,10 @@ func checkStatus() {
 
 }
 
+// dumpAccountInfo logs the public keys of the miner, the secret keys must never be logged
 func (gx *GX) dumpAccountInfo(minerDO model.SelfMinerInfo) {
-	common.DefaultLogger.Infof("SecKey: %s", gx.account.Sk)
 	common.DefaultLogger.Infof("PubKey: %s", gx.account.Pk)
-	common.DefaultLogger.Infof("Miner SecKey: %s", minerDO.SecKey.GetHexString())
 	common.DefaultLogger.Infof("Miner PubKey: %s", minerDO.PubKey.GetHexString())
-	common.DefaultLogger.Infof("VRF PrivateKey: %s", minerDO.VrfSK.GetHexString())
 	common.DefaultLogger.Infof("VRF PubKey: %s", minerDO.VrfPK.GetHexString())
 	common.DefaultLogger.Infof("Miner ID: %s", minerDO.ID.GetHexString())
 
@@ -315,9 +476,13 @@ func (gx *GX) dumpAccountInfo(minerDO model.SelfMinerInfo) {
 }
 
 func (gx *GX) handleExit(ctrlC <-chan bool, quit chan<- bool) {
-	<-ctrlC
+	select {
+	case <-ctrlC:
+	case <-gx.shutdown:
+	}
 	fmt.Println("exiting...")
-	consensus.StopMiner()
+	gx.stopIPC()
+	consensus.CloseMiner()
 
 	if core.GetBlockChain() != nil {
 		core.GetBlockChain().Close()
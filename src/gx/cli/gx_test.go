@@ -178,7 +178,7 @@ func initTestingEnv() {
 		panic("Init miner core init error:" + err.Error())
 	}
 
-	ok := consensus.InitConsensus(minerInfo, common.GlobalConf)
+	ok := consensus.InitConsensus(minerInfo, *sk, common.GlobalConf)
 	if !ok {
 		panic("Init miner consensus init error!")
 
@@ -0,0 +1,150 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"com.tuntun.rangers/node/src/consensus"
+	"com.tuntun.rangers/node/src/core"
+	"com.tuntun.rangers/node/src/network"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultStallWindow = 5 * time.Minute
+	healthSampleRate   = 5 * time.Second
+)
+
+// healthChecker answers the liveness and readiness probes of the orchestrators.
+// The node is alive unless the block height stalls over the window,
+// and it is ready when it is synced, connected to the gateway and the miner is started.
+type healthChecker struct {
+	stallWindow time.Duration
+
+	lock       sync.Mutex
+	lastHeight uint64
+	lastChange time.Time
+}
+
+type healthStatus struct {
+	Status string          `json:"status"`
+	Checks map[string]bool `json:"checks"`
+
+	LocalHeight     uint64          `json:"localHeight"`
+	CandidateHeight uint64          `json:"candidateHeight,omitempty"`
+	Connections     map[string]bool `json:"connections,omitempty"`
+}
+
+func newHealthChecker(stallWindow time.Duration) *healthChecker {
+	if 0 == stallWindow {
+		stallWindow = defaultStallWindow
+	}
+	return &healthChecker{stallWindow: stallWindow, lastChange: time.Now()}
+}
+
+// startHealth serves /healthz and /readyz at the address in the background
+func startHealth(addr string, stallWindow time.Duration) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	checker := newHealthChecker(stallWindow)
+	go checker.loop()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", checker.handleHealthz)
+	mux.HandleFunc("/readyz", checker.handleReadyz)
+	go http.Serve(listener, mux)
+	return nil
+}
+
+func (checker *healthChecker) loop() {
+	for range time.Tick(healthSampleRate) {
+		checker.sample()
+	}
+}
+
+// sample records the time the block height last changed
+func (checker *healthChecker) sample() {
+	chain := core.GetBlockChain()
+	if nil == chain {
+		return
+	}
+	height := chain.Height()
+
+	checker.lock.Lock()
+	defer checker.lock.Unlock()
+	if height != checker.lastHeight {
+		checker.lastHeight = height
+		checker.lastChange = time.Now()
+	}
+}
+
+func (checker *healthChecker) liveness() healthStatus {
+	checker.lock.Lock()
+	height, stalled := checker.lastHeight, time.Since(checker.lastChange) > checker.stallWindow
+	checker.lock.Unlock()
+
+	status := healthStatus{Checks: map[string]bool{"blockHeight": !stalled}, LocalHeight: height}
+	status.Status = statusOf(status.Checks)
+	return status
+}
+
+func (checker *healthChecker) readiness() healthStatus {
+	candidateHeight, localHeight, synced := syncStatus()
+	status := healthStatus{
+		Checks: map[string]bool{
+			"synced":  synced,
+			"gateway": network.GatewayConnected(),
+			"miner":   consensus.Proc.Ready(),
+		},
+		LocalHeight:     localHeight,
+		CandidateHeight: candidateHeight,
+		Connections:     network.ConnStates(),
+	}
+	status.Status = statusOf(status.Checks)
+	return status
+}
+
+func (checker *healthChecker) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, checker.liveness())
+}
+
+func (checker *healthChecker) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, checker.readiness())
+}
+
+func statusOf(checks map[string]bool) string {
+	for _, ok := range checks {
+		if !ok {
+			return "fail"
+		}
+	}
+	return "ok"
+}
+
+func writeHealth(w http.ResponseWriter, status healthStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	if "ok" != status.Status {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHealthChecker(t *testing.T) {
+	checker := newHealthChecker(50 * time.Millisecond)
+
+	recorder := httptest.NewRecorder()
+	checker.handleHealthz(recorder, httptest.NewRequest("GET", "/healthz", nil))
+	if http.StatusOK != recorder.Code {
+		t.Fatalf("alive node reported %d", recorder.Code)
+	}
+
+	// the height does not change over the window
+	time.Sleep(100 * time.Millisecond)
+	checker.sample()
+	recorder = httptest.NewRecorder()
+	checker.handleHealthz(recorder, httptest.NewRequest("GET", "/healthz", nil))
+	if http.StatusServiceUnavailable != recorder.Code {
+		t.Fatalf("stalled node reported %d", recorder.Code)
+	}
+
+	// the node without chain, gateway and miner is not ready
+	recorder = httptest.NewRecorder()
+	checker.handleReadyz(recorder, httptest.NewRequest("GET", "/readyz", nil))
+	if http.StatusServiceUnavailable != recorder.Code {
+		t.Fatalf("unready node reported %d", recorder.Code)
+	}
+	var status healthStatus
+	if err := json.Unmarshal(recorder.Body.Bytes(), &status); err != nil {
+		t.Fatal(err)
+	}
+	if "fail" != status.Status || status.Checks["synced"] || status.Checks["gateway"] || status.Checks["miner"] {
+		t.Fatalf("wrong status: %+v", status)
+	}
+}
@@ -60,6 +60,12 @@ type MinerInfo struct {
 type accountOp interface {
 	NewAccount(password string, miner bool) *Result
 
+	ImportAccount(keyJSON []byte, password string) *Result
+
+	ImportPrivateKey(privateKey string, password string) *Result
+
+	ExportAccount(addr string) *Result
+
 	AccountList() *Result
 
 	Lock(addr string) *Result
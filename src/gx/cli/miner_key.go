@@ -0,0 +1,207 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/common/keystore"
+	"com.tuntun.rangers/node/src/consensus/model"
+	"com.tuntun.rangers/node/src/consensus/signer"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+const (
+	// keystorePasswordEnv holds the passphrase of the miner keystore if no password file is set
+	keystorePasswordEnv = "RANGERS_KEYSTORE_PASSWORD"
+)
+
+var errNoPassword = errors.New("no password for the miner keystore, set --passwordfile or " + keystorePasswordEnv)
+
+// minerKeyConfig tells where the miner key is, the command flags override the config file.
+// The key is loaded from the remote signer, or the encrypted keystore file,
+// or the plaintext privateKey of the older versions, which is moved into the keystore on loading.
+type minerKeyConfig struct {
+	keystore     string
+	passwordFile string
+	signer       string
+}
+
+func (conf *minerKeyConfig) load() {
+	if 0 == len(conf.keystore) {
+		conf.keystore = common.GlobalConf.GetString(Section, "keystore", "")
+	}
+	if 0 == len(conf.passwordFile) {
+		conf.passwordFile = common.GlobalConf.GetString(Section, "passwordFile", "")
+	}
+	if 0 == len(conf.signer) {
+		conf.signer = common.GlobalConf.GetString(Section, "signer", "")
+	}
+}
+
+// password reads the passphrase from the password file, or the environment
+func (conf *minerKeyConfig) password() (string, error) {
+	if 0 != len(conf.passwordFile) {
+		content, err := ioutil.ReadFile(conf.passwordFile)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+	if password, ok := os.LookupEnv(keystorePasswordEnv); ok {
+		return password, nil
+	}
+	return "", errNoPassword
+}
+
+// loadMinerKey loads the miner key, returning the signer of the node key and the miner info.
+// Only the public keys are kept in gx.account, and the secret keys are never logged.
+func (gx *GX) loadMinerKey() (common.Signer, model.SelfMinerInfo, error) {
+	gx.keyConfig.load()
+
+	if 0 != len(gx.keyConfig.signer) {
+		client, err := signer.Dial(gx.keyConfig.signer)
+		if err != nil {
+			return nil, model.SelfMinerInfo{}, fmt.Errorf("connect remote signer error: %s", err)
+		}
+		minerInfo, err := client.MinerInfo()
+		if err != nil {
+			return nil, model.SelfMinerInfo{}, fmt.Errorf("remote signer error: %s", err)
+		}
+		gx.account = publicAccount(client.Account().PublicKey, minerInfo)
+		return client, minerInfo, nil
+	}
+
+	privateKey, err := gx.loadPrivateKey()
+	if err != nil {
+		return nil, model.SelfMinerInfo{}, err
+	}
+	publicKey := privateKey.GetPubKey()
+	minerInfo := model.NewSelfMinerInfo(*privateKey)
+	gx.account = publicAccount(publicKey.GetHexString(), minerInfo)
+	return *privateKey, minerInfo, nil
+}
+
+func (gx *GX) loadPrivateKey() (*common.PrivateKey, error) {
+	if 0 != len(gx.keyConfig.keystore) {
+		password, err := gx.keyConfig.password()
+		if err != nil {
+			return nil, err
+		}
+		keyJSON, err := ioutil.ReadFile(gx.keyConfig.keystore)
+		if err != nil {
+			return nil, err
+		}
+		key, err := keystore.DecryptKey(keyJSON, password)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt miner keystore %s error: %s", gx.keyConfig.keystore, err)
+		}
+		return key.PrivateKey, nil
+	}
+
+	if sk := common.GlobalConf.GetString(Section, "privateKey", ""); 0 != len(sk) {
+		return gx.migrateMinerKey(sk)
+	}
+
+	return gx.storeMinerKey(keystore.NewKey())
+}
+
+// migrateMinerKey encrypts the plaintext privateKey of the older versions into the keystore,
+// and removes it from the config. The node fails to start without a password to encrypt it
+func (gx *GX) migrateMinerKey(sk string) (*common.PrivateKey, error) {
+	key, err := keystore.NewKeyFromHex(sk)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := gx.storeMinerKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("migrate the plaintext privateKey in the config error: %s", err)
+	}
+	common.GlobalConf.Del(Section, "privateKey")
+	gx.warn("the plaintext privateKey in the config is moved into the keystore " + gx.keyConfig.keystore)
+	return privateKey, nil
+}
+
+// storeMinerKey encrypts the miner key into the keystore and sets the keystore in the config.
+// The password is required, a miner key is never written in plaintext
+func (gx *GX) storeMinerKey(key *keystore.Key) (*common.PrivateKey, error) {
+	password, err := gx.keyConfig.password()
+	if err != nil {
+		return nil, fmt.Errorf("encrypt the miner key error: %s", err)
+	}
+
+	ks, err := keystore.NewKeyStore(keystoreDir, keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		return nil, err
+	}
+	if err := ks.StoreKey(key, password); err != nil {
+		return nil, err
+	}
+	file, err := ks.KeyFile(key.Address)
+	if err != nil {
+		return nil, err
+	}
+	common.GlobalConf.SetString(Section, "keystore", file)
+	gx.keyConfig.keystore = file
+	fmt.Println("Miner key is stored in", file)
+	return key.PrivateKey, nil
+}
+
+func (gx *GX) warn(msg string) {
+	fmt.Println("WARNING:", msg)
+	if nil != common.DefaultLogger {
+		common.DefaultLogger.Warn(msg)
+	}
+}
+
+// publicAccount returns the account with the public keys only
+func publicAccount(publicKey string, minerInfo model.SelfMinerInfo) Account {
+	pk := common.HexStringToPubKey(publicKey)
+	id := pk.GetID()
+	return Account{
+		Address: pk.GetAddress().GetHexString(),
+		Pk:      publicKey,
+		Miner: &MinerRaw{
+			BPk:   minerInfo.PubKey.GetHexString(),
+			VrfPk: minerInfo.VrfPK.GetHexString(),
+			ID:    id,
+		},
+	}
+}
+
+// runSigner serves the key in the keystore to the nodes at the local endpoint
+func runSigner(keystoreFile, passwordFile, endpoint string) error {
+	conf := minerKeyConfig{keystore: keystoreFile, passwordFile: passwordFile}
+	password, err := conf.password()
+	if err != nil {
+		return err
+	}
+	keyJSON, err := ioutil.ReadFile(keystoreFile)
+	if err != nil {
+		return err
+	}
+	key, err := keystore.DecryptKey(keyJSON, password)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Signer of %s serving on %s\n", key.Address.GetHexString(), endpoint)
+	return signer.NewServer(*key.PrivateKey).ListenAndServe(endpoint)
+}
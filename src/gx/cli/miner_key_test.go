@@ -0,0 +1,119 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/consensus/signer"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadMinerKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minerkey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(dir)
+
+	common.Init(0, "rp.ini", "dev")
+	os.Setenv(keystorePasswordEnv, "test")
+	defer os.Unsetenv(keystorePasswordEnv)
+
+	// a new node stores the key into the keystore
+	gx := NewGX()
+	_, minerInfo, err := gx.loadMinerKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if 0 != len(common.GlobalConf.GetString(Section, "privateKey", "")) || 0 != len(gx.account.Sk) {
+		t.Fatal("plaintext private key")
+	}
+	keyFile := common.GlobalConf.GetString(Section, "keystore", "")
+	if 0 == len(keyFile) {
+		t.Fatal("keystore not set")
+	}
+
+	// the restarted node loads the same key
+	restarted := NewGX()
+	_, restartedInfo, err := restarted.loadMinerKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restarted.account.Address != gx.account.Address || !restartedInfo.ID.IsEqual(minerInfo.ID) {
+		t.Fatal("miner key mismatch")
+	}
+
+	// a wrong password fails
+	passwordFile := filepath.Join(dir, "password")
+	ioutil.WriteFile(passwordFile, []byte("wrong\n"), 0600)
+	wrong := NewGX()
+	wrong.keyConfig.passwordFile = passwordFile
+	if _, _, err := wrong.loadMinerKey(); err == nil {
+		t.Fatal("wrong password accepted")
+	}
+
+	// the plaintext key of the older versions is moved into the keystore
+	plaintext := common.GenerateKey("")
+	common.GlobalConf.Del(Section, "keystore")
+	common.GlobalConf.SetString(Section, "privateKey", plaintext.GetHexString())
+	noPassword := NewGX()
+	noPassword.keyConfig.passwordFile = filepath.Join(dir, "none")
+	if _, _, err := noPassword.loadMinerKey(); err == nil {
+		t.Fatal("plaintext key loaded without a password to encrypt it")
+	}
+	migrated := NewGX()
+	if _, _, err := migrated.loadMinerKey(); err != nil {
+		t.Fatal(err)
+	}
+	if 0 != len(common.GlobalConf.GetString(Section, "privateKey", "")) || 0 == len(common.GlobalConf.GetString(Section, "keystore", "")) {
+		t.Fatal("plaintext key not migrated")
+	}
+	if migrated.account.Address != plaintext.GetPubKey().GetAddress().GetHexString() {
+		t.Fatal("migrated key mismatch")
+	}
+
+	// the remote signer holds the same key
+	privateKey := common.GenerateKey("")
+	endpoint := "unix:" + filepath.Join(dir, "signer.sock")
+	listener, err := signer.Listen(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go http.Serve(listener, signer.NewServer(privateKey))
+	remote := NewGX()
+	remote.keyConfig.signer = endpoint
+	nodeSigner, remoteInfo, err := remote.loadMinerKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remote.account.Address != privateKey.GetPubKey().GetAddress().GetHexString() || remoteInfo.SecKey.IsValid() {
+		t.Fatal("remote miner info mismatch")
+	}
+	hash := common.Sha256([]byte("miner key"))
+	sign := nodeSigner.Sign(hash)
+	if !privateKey.GetPubKey().Verify(hash, &sign) {
+		t.Fatal("remote sign failed")
+	}
+}
@@ -62,7 +62,7 @@ func startHTTP(endpoint string, apis []rpc.API, modules []string, cors []string,
 	return nil
 }
 
-func startHttps(httpPort uint, privateKey string) error {
+func startHttps(httpPort uint, account Account) error {
 	endpoint := fmt.Sprintf("0.0.0.0:%d", httpPort+1000)
 	fmt.Println("self http: " + endpoint)
 	var (
@@ -72,7 +72,7 @@ func startHttps(httpPort uint, privateKey string) error {
 	if listener, err = net.Listen("tcp", endpoint); err != nil {
 		return err
 	}
-	server := &http.Server{Handler: NewSelfServer(privateKey)}
+	server := &http.Server{Handler: NewSelfServer(account)}
 	go server.Serve(listener)
 	common.DefaultLogger.Infof("Self Http serving on %s for dev\n", endpoint)
 
@@ -81,10 +81,10 @@ func startHttps(httpPort uint, privateKey string) error {
 
 var GtasAPIImpl *GtasAPI
 
-func StartRPC(host string, port uint, privateKey string) error {
+func StartRPC(host string, port uint, account Account) error {
 	var err error
 	GtasAPIImpl = &GtasAPI{}
-	GtasAPIImpl.privateKey = privateKey
+	GtasAPIImpl.account = account
 	GtasAPIImpl.logger = log.GetLoggerByIndex(log.RPCLogConfig, common.GlobalConf.GetString("instance", "index", ""))
 
 	gxLock = &sync.RWMutex{}
@@ -113,10 +113,18 @@ func StartRPC(host string, port uint, privateKey string) error {
 		return err
 	}
 
-	err = startHttps(port, privateKey)
+	err = startHttps(port, account)
 	return err
 }
 
+var filterAPI *FilterAPI
+
+// initFilterAPI serves the polling filters to the eth rpc messages, sharing the event system with the ws subscriptions
+func initFilterAPI() {
+	filterAPI = newFilterAPI(newEventSystem(), filterTimeout)
+	eth_rpc.RegisterAPI("eth", filterAPI)
+}
+
 func StartJSONRPCHttp(port uint) error {
 	endpoint := fmt.Sprintf("0.0.0.0:%d", port)
 	var (
@@ -136,11 +144,12 @@ func StartJSONRPCWS(port uint) error {
 	endpoint := fmt.Sprintf("0.0.0.0:%d", port)
 
 	subscribeAPI := &SubscribeAPI{}
-	subscribeAPI.events = newEventSystem()
+	subscribeAPI.events = filterAPI.events
 	subscribeAPI.logger = log.GetLoggerByIndex(log.ETHRPCLogConfig, strconv.Itoa(common.InstanceIndex))
 	apis := []rpc.API{
 		{Namespace: "eth", Version: "1", Service: &eth_rpc.EthAPIService{}, Public: true},
 		{Namespace: "eth", Version: "1", Service: subscribeAPI, Public: true},
+		{Namespace: "eth", Version: "1", Service: filterAPI, Public: true},
 		{Namespace: "net", Version: "1", Service: &NetAPI{}, Public: true},
 		{Namespace: "web3", Version: "1", Service: &Web3API{}, Public: true},
 	}
@@ -163,8 +172,8 @@ func StartJSONRPCWS(port uint) error {
 	if listener, err = net.Listen("tcp", endpoint); err != nil {
 		return err
 	}
-	allowOrigins := []string{"*"}
-	go rpc.NewWSServer(allowOrigins, handler).Serve(listener)
+	handler.SetLimits(eth_rpc.RequestFilter(eth_rpc.WSListener), eth_rpc.RequestTimeout(), eth_rpc.MaxBatchSize(), eth_rpc.MaxBodySize())
+	go rpc.NewWSServer(eth_rpc.CorsOrigins(), handler).Serve(listener)
 	common.DefaultLogger.Infof("JSONRPC ws serving on %s \n", endpoint)
 	return nil
 }
@@ -0,0 +1,173 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/consensus"
+	"com.tuntun.rangers/node/src/core"
+	"com.tuntun.rangers/node/src/eth_rpc"
+	"com.tuntun.rangers/node/src/gx/rpc"
+	"com.tuntun.rangers/node/src/middleware/log"
+	"com.tuntun.rangers/node/src/network"
+	"com.tuntun.rangers/node/src/utility"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// ipcFile is the unix socket in the storage dir of the instance serving the admin apis
+const ipcFile = "gx.ipc"
+
+// AdminAPI manages the node, admin_*.
+// It is served on the ipc endpoint only, never on the public http port.
+type AdminAPI struct {
+	gx *GX
+}
+
+type AdminNodeInfo struct {
+	Version         string `json:"version"`
+	ChainId         string `json:"chainId"`
+	NetworkId       string `json:"networkId"`
+	MinerId         string `json:"minerId"`
+	Address         string `json:"address"`
+	InstanceIndex   int    `json:"instanceIndex"`
+	FullNode        bool   `json:"fullNode"`
+	LocalHeight     uint64 `json:"localHeight"`
+	CandidateHeight uint64 `json:"candidateHeight"`
+	Synced          bool   `json:"synced"`
+	Mining          bool   `json:"mining"`
+	LogLevel        string `json:"logLevel"`
+}
+
+type PeersInfo struct {
+	// the connections to the gateway, by path
+	Gateway map[string]bool `json:"gateway"`
+	// the group nets joined on the gateway
+	GroupNets []string `json:"groupNets"`
+}
+
+// NodeInfo admin_nodeInfo
+func (api *AdminAPI) NodeInfo() AdminNodeInfo {
+	candidateHeight, localHeight, synced := syncStatus()
+	return AdminNodeInfo{
+		Version:         common.Version,
+		ChainId:         common.ChainId(utility.MaxUint64),
+		NetworkId:       common.NetworkId(),
+		MinerId:         common.GlobalConf.GetString(Section, "miner", ""),
+		Address:         api.gx.account.Address,
+		InstanceIndex:   common.InstanceIndex,
+		FullNode:        common.IsFullNode(),
+		LocalHeight:     localHeight,
+		CandidateHeight: candidateHeight,
+		Synced:          synced,
+		Mining:          consensus.Proc.Ready(),
+		LogLevel:        log.GetLevel(),
+	}
+}
+
+// Peers admin_peers
+func (api *AdminAPI) Peers() PeersInfo {
+	return PeersInfo{Gateway: network.ConnStates(), GroupNets: network.JoinedGroupNets()}
+}
+
+// SetLogLevel sets the minimum level of all loggers, one of trace, debug, info, warn and error.
+// admin_setLogLevel
+func (api *AdminAPI) SetLogLevel(level string) (bool, error) {
+	if err := log.SetLevel(level); err != nil {
+		return false, err
+	}
+	common.DefaultLogger.Warnf("log level set to %s", level)
+	return true, nil
+}
+
+// Shutdown stops the node as on SIGTERM, the response is sent before the node exits.
+// admin_shutdown
+func (api *AdminAPI) Shutdown() bool {
+	select {
+	case api.gx.shutdown <- true:
+		common.DefaultLogger.Warnf("shutdown by admin")
+	default:
+	}
+	return true
+}
+
+// MinerAPI starts and stops mining, miner_*
+type MinerAPI struct {
+}
+
+// Start miner_start
+func (api *MinerAPI) Start() bool {
+	return consensus.StartMiner()
+}
+
+// Stop miner_stop
+func (api *MinerAPI) Stop() bool {
+	consensus.StopMiner()
+	return true
+}
+
+// AdminDebugAPI holds the debug_* calls changing the chain
+type AdminDebugAPI struct {
+}
+
+// SetHead rewinds the main chain to the block number, the node syncs the removed blocks again.
+// It returns the height of the new top block.
+// debug_setHead
+func (api *AdminDebugAPI) SetHead(number utility.Uint64) (utility.Uint64, error) {
+	height, err := core.SetHead(uint64(number))
+	return utility.Uint64(height), err
+}
+
+// startIPC serves the admin apis on the unix socket in the storage dir.
+// The socket is only accessible to the user running the node.
+func (gx *GX) startIPC() error {
+	endpoint := "storage" + strconv.Itoa(common.InstanceIndex) + "/" + ipcFile
+	apis := []rpc.API{
+		{Namespace: "admin", Version: "1", Service: &AdminAPI{gx: gx}},
+		{Namespace: "miner", Version: "1", Service: &MinerAPI{}},
+		{Namespace: "debug", Version: "1", Service: &AdminDebugAPI{}},
+		{Namespace: "debug", Version: "1", Service: &eth_rpc.DebugAPIService{}},
+	}
+
+	handler := rpc.NewServer()
+	for _, api := range apis {
+		if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
+			return err
+		}
+	}
+	listener, err := rpc.CreateIPCListener(endpoint)
+	if err != nil {
+		return err
+	}
+	gx.ipc = listener
+	go handler.ServeListener(listener)
+	common.DefaultLogger.Infof("IPC serving on %s", endpoint)
+	fmt.Printf("IPC serving on %s\n", endpoint)
+	return nil
+}
+
+// stopIPC closes the ipc endpoint and removes the socket file, so the next start can listen on it
+func (gx *GX) stopIPC() {
+	if nil == gx.ipc {
+		return
+	}
+	endpoint := gx.ipc.Addr().String()
+	gx.ipc.Close()
+	os.Remove(endpoint)
+	gx.ipc = nil
+}
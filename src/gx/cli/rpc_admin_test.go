@@ -0,0 +1,93 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/gx/rpc"
+	"com.tuntun.rangers/node/src/middleware/log"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAdminIPC(t *testing.T) {
+	dir, err := ioutil.TempDir("", "adminipc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(dir)
+
+	common.Init(0, "rp.ini", "dev")
+	gx := NewGX()
+	gx.init = true
+	if err := gx.startIPC(); err != nil {
+		t.Fatal(err)
+	}
+	quit := make(chan bool, 1)
+	go gx.handleExit(make(chan bool), quit)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client, err := rpc.DialIPC(ctx, "storage0/"+ipcFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var info AdminNodeInfo
+	if err := client.Call(&info, "admin_nodeInfo"); err != nil {
+		t.Fatal(err)
+	}
+	if common.Version != info.Version || info.Mining {
+		t.Fatalf("wrong node info: %+v", info)
+	}
+
+	var peers PeersInfo
+	if err := client.Call(&peers, "admin_peers"); err != nil {
+		t.Fatal(err)
+	}
+
+	var ok bool
+	if err := client.Call(&ok, "admin_setLogLevel", "warn"); err != nil || !ok {
+		t.Fatalf("fail to set log level, %v", err)
+	}
+	defer log.SetLevel("trace")
+	if "warn" != log.GetLevel() {
+		t.Fatalf("wrong log level: %s", log.GetLevel())
+	}
+	if err := client.Call(&ok, "admin_setLogLevel", "verbose"); err == nil {
+		t.Fatal("unknown log level accepted")
+	}
+
+	if err := client.Call(&ok, "admin_shutdown"); err != nil || !ok {
+		t.Fatalf("fail to shutdown, %v", err)
+	}
+	select {
+	case <-quit:
+	case <-time.After(5 * time.Second):
+		t.Fatal("node not exited on shutdown")
+	}
+	if _, err := os.Stat("storage0/" + ipcFile); !os.IsNotExist(err) {
+		t.Fatalf("ipc socket not removed, %v", err)
+	}
+}
@@ -48,8 +48,8 @@ func failResult(err string) (*Result, error) {
 
 // GtasAPI is a single-method API handler to be returned by test services.
 type GtasAPI struct {
-	privateKey string
-	logger     log.Logger
+	account Account
+	logger  log.Logger
 }
 
 var gxLock *sync.RWMutex
@@ -412,7 +412,7 @@ func (api *GtasAPI) Dashboard() (*Result, error) {
 	groupHeight := core.GetGroupChain().Count()
 	workNum := len(consensus.Proc.GetCastQualifiedGroups(blockHeight))
 	nodeResult, _ := api.NodeInfo()
-	_, addr, self := walletManager.newWalletByPrivateKey(api.privateKey)
+	addr, self := walletManager.minerOf(api.account)
 
 	dash := &Dashboard{
 		BlockHeight: blockHeight,
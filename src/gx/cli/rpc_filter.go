@@ -0,0 +1,226 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/core"
+	"com.tuntun.rangers/node/src/gx/rpc"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"errors"
+	"sync"
+	"time"
+)
+
+// filterTimeout is the idle time after which a polling filter is uninstalled
+const filterTimeout = 5 * time.Minute
+
+var errFilterNotFound = errors.New("filter not found")
+
+// filter is a polling filter, keeping the events since the last poll
+type filter struct {
+	typ      Type
+	deadline *time.Timer
+	crit     types.FilterCriteria
+	hashes   []common.Hash
+	logs     []*types.Log
+	s        *Subscription
+}
+
+// FilterAPI serves the eth_newFilter family for clients without subscriptions, such as plain http.
+// Filters are fed by the event system and removed after filterTimeout without polling.
+type FilterAPI struct {
+	events    *EventSystem
+	filters   map[rpc.ID]*filter
+	filtersMu sync.Mutex
+	timeout   time.Duration
+}
+
+func newFilterAPI(events *EventSystem, timeout time.Duration) *FilterAPI {
+	api := &FilterAPI{
+		events:  events,
+		filters: make(map[rpc.ID]*filter),
+		timeout: timeout,
+	}
+	go api.timeoutLoop()
+	return api
+}
+
+// timeoutLoop uninstalls the filters not polled for timeout
+func (api *FilterAPI) timeoutLoop() {
+	ticker := time.NewTicker(api.timeout)
+	defer ticker.Stop()
+	for {
+		<-ticker.C
+		expired := make([]*Subscription, 0)
+		api.filtersMu.Lock()
+		for id, f := range api.filters {
+			select {
+			case <-f.deadline.C:
+				delete(api.filters, id)
+				expired = append(expired, f.s)
+			default:
+			}
+		}
+		api.filtersMu.Unlock()
+
+		for _, sub := range expired {
+			sub.Unsubscribe()
+		}
+	}
+}
+
+// NewPendingTransactionFilter creates a filter that fetches the hashes of transactions entering the pool.
+// eth_newPendingTransactionFilter
+func (api *FilterAPI) NewPendingTransactionFilter() rpc.ID {
+	hashes := make(chan []common.Hash)
+	sub := api.events.SubscribePendingTxs(hashes)
+	api.install(&filter{typ: PendingTransactionsSubscription, hashes: make([]common.Hash, 0), s: sub})
+
+	go func() {
+		for {
+			select {
+			case ev := <-hashes:
+				api.filtersMu.Lock()
+				if f, found := api.filters[sub.ID]; found {
+					f.hashes = append(f.hashes, ev...)
+				}
+				api.filtersMu.Unlock()
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return sub.ID
+}
+
+// NewBlockFilter creates a filter that fetches the hashes of blocks added to the chain.
+// eth_newBlockFilter
+func (api *FilterAPI) NewBlockFilter() rpc.ID {
+	headers := make(chan *types.BlockHeader)
+	sub := api.events.SubscribeNewHeads(headers)
+	api.install(&filter{typ: BlocksSubscription, hashes: make([]common.Hash, 0), s: sub})
+
+	go func() {
+		for {
+			select {
+			case header := <-headers:
+				api.filtersMu.Lock()
+				if f, found := api.filters[sub.ID]; found {
+					f.hashes = append(f.hashes, header.Hash)
+				}
+				api.filtersMu.Unlock()
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return sub.ID
+}
+
+// NewFilter creates a filter that fetches the new logs matching the criteria.
+// eth_newFilter
+func (api *FilterAPI) NewFilter(crit types.FilterCriteria) (rpc.ID, error) {
+	logs := make(chan []*types.Log)
+	sub, err := api.events.SubscribeLogs(FilterQuery(crit), logs)
+	if err != nil {
+		return "", err
+	}
+	api.install(&filter{typ: LogsSubscription, crit: crit, logs: make([]*types.Log, 0), s: sub})
+
+	go func() {
+		for {
+			select {
+			case ev := <-logs:
+				api.filtersMu.Lock()
+				if f, found := api.filters[sub.ID]; found {
+					f.logs = append(f.logs, ev...)
+				}
+				api.filtersMu.Unlock()
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return sub.ID, nil
+}
+
+// GetFilterLogs returns all the logs matching the criteria of the log filter.
+// eth_getFilterLogs
+func (api *FilterAPI) GetFilterLogs(id rpc.ID) ([]*types.Log, error) {
+	api.filtersMu.Lock()
+	f, found := api.filters[id]
+	api.filtersMu.Unlock()
+
+	if !found || f.typ != LogsSubscription {
+		return nil, errFilterNotFound
+	}
+	return core.GetLogs(f.crit)
+}
+
+// GetFilterChanges returns the events since the last poll, and keeps the filter alive.
+// Block and pending transaction filters return hashes, log filters return logs.
+// eth_getFilterChanges
+func (api *FilterAPI) GetFilterChanges(id rpc.ID) (interface{}, error) {
+	api.filtersMu.Lock()
+	defer api.filtersMu.Unlock()
+
+	f, found := api.filters[id]
+	if !found {
+		return nil, errFilterNotFound
+	}
+	if !f.deadline.Stop() {
+		// expired but not yet removed by the timeout loop
+		<-f.deadline.C
+	}
+	f.deadline.Reset(api.timeout)
+
+	switch f.typ {
+	case PendingTransactionsSubscription, BlocksSubscription:
+		hashes := f.hashes
+		f.hashes = make([]common.Hash, 0)
+		return hashes, nil
+	case LogsSubscription:
+		logs := f.logs
+		f.logs = make([]*types.Log, 0)
+		return logs, nil
+	}
+	return []interface{}{}, nil
+}
+
+// UninstallFilter removes the filter, returns false if it does not exist.
+// eth_uninstallFilter
+func (api *FilterAPI) UninstallFilter(id rpc.ID) bool {
+	api.filtersMu.Lock()
+	f, found := api.filters[id]
+	if found {
+		delete(api.filters, id)
+	}
+	api.filtersMu.Unlock()
+
+	if found {
+		f.s.Unsubscribe()
+	}
+	return found
+}
+
+func (api *FilterAPI) install(f *filter) {
+	f.deadline = time.NewTimer(api.timeout)
+	api.filtersMu.Lock()
+	api.filters[f.s.ID] = f
+	api.filtersMu.Unlock()
+}
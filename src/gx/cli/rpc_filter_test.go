@@ -0,0 +1,119 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/gx/rpc"
+	"com.tuntun.rangers/node/src/middleware/log"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"testing"
+	"time"
+)
+
+func newTestEventSystem() *EventSystem {
+	es := &EventSystem{
+		install:   make(chan *subscription),
+		uninstall: make(chan *subscription),
+		logsCh:    make(chan []*types.Log, logsChanSize),
+		headerCh:  make(chan *types.BlockHeader, chainEvChanSize),
+		rmLogsCh:  make(chan []*types.Log, rmLogsChanSize),
+		txsCh:     make(chan []common.Hash, txChanSize),
+		logger:    log.GetLoggerByIndex(log.EventSubLogConfig, "0"),
+	}
+	go es.eventLoop()
+	return es
+}
+
+// pollChanges polls the filter until it returns some events
+func pollChanges(t *testing.T, api *FilterAPI, id rpc.ID) interface{} {
+	for i := 0; i < 100; i++ {
+		changes, err := api.GetFilterChanges(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch changes := changes.(type) {
+		case []common.Hash:
+			if 0 != len(changes) {
+				return changes
+			}
+		case []*types.Log:
+			if 0 != len(changes) {
+				return changes
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("no changes")
+	return nil
+}
+
+func TestFilterAPI(t *testing.T) {
+	es := newTestEventSystem()
+	api := newFilterAPI(es, time.Hour)
+
+	blockFilter := api.NewBlockFilter()
+	header := &types.BlockHeader{Hash: common.BytesToHash([]byte("block"))}
+	es.headerCh <- header
+	if hashes := pollChanges(t, api, blockFilter).([]common.Hash); 1 != len(hashes) || hashes[0] != header.Hash {
+		t.Fatalf("unexpected block hashes: %v", hashes)
+	}
+
+	txFilter := api.NewPendingTransactionFilter()
+	txHash := common.BytesToHash([]byte("tx"))
+	es.txsCh <- []common.Hash{txHash}
+	if hashes := pollChanges(t, api, txFilter).([]common.Hash); 1 != len(hashes) || hashes[0] != txHash {
+		t.Fatalf("unexpected tx hashes: %v", hashes)
+	}
+
+	contract := common.BytesToAddress([]byte("contract"))
+	logFilter, err := api.NewFilter(types.FilterCriteria{Addresses: []common.Address{contract}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	es.logsCh <- []*types.Log{{Address: common.BytesToAddress([]byte("other"))}, {Address: contract}}
+	if logs := pollChanges(t, api, logFilter).([]*types.Log); 1 != len(logs) || logs[0].Address != contract {
+		t.Fatalf("unexpected logs: %v", logs)
+	}
+
+	if !api.UninstallFilter(blockFilter) {
+		t.Fatal("fail to uninstall filter")
+	}
+	if api.UninstallFilter(blockFilter) {
+		t.Fatal("filter uninstalled twice")
+	}
+	if _, err := api.GetFilterChanges(blockFilter); err != errFilterNotFound {
+		t.Fatalf("expected %v, got %v", errFilterNotFound, err)
+	}
+	es.headerCh <- header
+}
+
+func TestFilterAPITimeout(t *testing.T) {
+	api := newFilterAPI(newTestEventSystem(), 50*time.Millisecond)
+
+	polled := api.NewBlockFilter()
+	idle := api.NewBlockFilter()
+	for i := 0; i < 6; i++ {
+		time.Sleep(25 * time.Millisecond)
+		if _, err := api.GetFilterChanges(polled); err != nil {
+			t.Fatalf("polled filter removed: %v", err)
+		}
+	}
+	if _, err := api.GetFilterChanges(idle); err != errFilterNotFound {
+		t.Fatalf("idle filter not removed, err: %v", err)
+	}
+}
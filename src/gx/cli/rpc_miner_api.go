@@ -159,3 +159,23 @@ func (api *GtasAPI) GetMiner(minerId string) (*Result, error) {
 
 	return successResult(miner)
 }
+
+func (api *GtasAPI) GetEvidence(hash string) (*Result, error) {
+	accountDB := middleware.AccountDBManagerInstance.GetLatestStateDB()
+	record := service.EvidenceManagerImpl.GetEvidence(common.HexToHash(hash), accountDB)
+
+	if nil == record {
+		return failResult(fmt.Sprintf("evidence: %s does not exist", hash))
+	}
+
+	return successResult(record)
+}
+
+func (api *GtasAPI) GetMinerEvidences(minerId string) (*Result, error) {
+	accountDB := middleware.AccountDBManagerInstance.GetLatestStateDB()
+	return successResult(service.EvidenceManagerImpl.GetMemberEvidences(common.FromHex(minerId), accountDB))
+}
+
+func (api *GtasAPI) PendingEvidences() (*Result, error) {
+	return successResult(consensus.Proc.PendingEvidences())
+}
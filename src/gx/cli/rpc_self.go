@@ -29,10 +29,10 @@ import (
 	"strings"
 )
 
-func NewSelfServer(privateKey string) *SelfServer {
-	server := SelfServer{privateKey: privateKey}
+func NewSelfServer(account Account) *SelfServer {
+	server := SelfServer{}
 
-	_, _, self := walletManager.newWalletByPrivateKey(server.privateKey)
+	_, self := walletManager.minerOf(account)
 	server.minerInfo = self
 
 	var miner types.Miner
@@ -43,9 +43,8 @@ func NewSelfServer(privateKey string) *SelfServer {
 }
 
 type SelfServer struct {
-	privateKey string
-	minerInfo  string
-	id         types.HexBytes
+	minerInfo string
+	id        types.HexBytes
 }
 
 func (server *SelfServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
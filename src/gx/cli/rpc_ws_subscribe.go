@@ -18,6 +18,8 @@ package cli
 
 import (
 	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/core"
+	"com.tuntun.rangers/node/src/eth_rpc"
 	"com.tuntun.rangers/node/src/gx/rpc"
 	"com.tuntun.rangers/node/src/middleware/log"
 	"com.tuntun.rangers/node/src/middleware/types"
@@ -160,6 +162,10 @@ type RPCBlockHeader struct {
 	TotalDifficulty  *utility.Big   `json:"totalDifficulty"`
 	TransactionsRoot common.Hash    `json:"transactionsRoot"`
 	Uncles           []string       `json:"uncles"`
+
+	// the finalized head at the time the header is pushed
+	FinalizedNumber *utility.Uint64 `json:"finalizedNumber,omitempty"`
+	FinalizedHash   *common.Hash    `json:"finalizedHash,omitempty"`
 }
 
 func adaptRPCBlockHeader(header *types.BlockHeader) *RPCBlockHeader {
@@ -171,7 +177,7 @@ func adaptRPCBlockHeader(header *types.BlockHeader) *RPCBlockHeader {
 		Hash:         header.Hash,
 		Bloom:        "0x00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
 		Miner:        common.BytesToAddress(header.Castor),
-		MixHash:      "0x0000000000000000000000000000000000000000000000000000000000000000",
+		MixHash:      eth_rpc.MixHash(header),
 		Nonce:        utility.Bytes(nonce[:]),
 		Number:       utility.Uint64(header.Height),
 		ParentHash:   header.PreHash,
@@ -184,11 +190,20 @@ func adaptRPCBlockHeader(header *types.BlockHeader) *RPCBlockHeader {
 		TotalDifficulty: &totalDifficulty,
 		Uncles:          []string{},
 	}
+	if 0 != header.GasLimit {
+		rpcBlockHeader.GasLimit = utility.Uint64(header.GasLimit)
+		rpcBlockHeader.GasUsed = utility.Uint64(header.GasUsed)
+	}
 	if len(header.Transactions) == 0 {
 		//transactionsRoot  has to be this value(EmptyRootHash) for pass go ethereum client verify because tx uncles is empty
 		rpcBlockHeader.TransactionsRoot = common.HexToHash("0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
 	} else {
 		rpcBlockHeader.TransactionsRoot = header.TxTree
 	}
+	if finalized := core.GetBlockChain().FinalizedBlock(); finalized != nil {
+		number := utility.Uint64(finalized.Height)
+		rpcBlockHeader.FinalizedNumber = &number
+		rpcBlockHeader.FinalizedHash = &finalized.Hash
+	}
 	return &rpcBlockHeader
 }
@@ -18,7 +18,9 @@ package cli
 
 import (
 	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/consensus/groupsig"
 	"com.tuntun.rangers/node/src/consensus/model"
+	"com.tuntun.rangers/node/src/consensus/vrf"
 	"com.tuntun.rangers/node/src/core"
 	"com.tuntun.rangers/node/src/middleware/types"
 	"com.tuntun.rangers/node/src/utility"
@@ -85,6 +87,22 @@ func (ws *wallets) newWalletByPrivateKey(privateKey string) (privKeyStr, walletA
 	return
 }
 
+// minerOf returns the address and the miner apply info of the account from its public keys
+func (ws *wallets) minerOf(account Account) (walletAddress, minerString string) {
+	var miner types.Miner
+	if nil != account.Miner {
+		miner.Id = account.Miner.ID
+		var pubKey groupsig.Pubkey
+		if err := pubKey.SetHexString(account.Miner.BPk); err == nil {
+			miner.PublicKey = pubKey.Serialize()
+		}
+		miner.VrfPublicKey = vrf.Hex2VRFPublicKey(account.Miner.VrfPk)
+	}
+
+	minerJson, _ := json.Marshal(miner)
+	return account.Address, string(minerJson)
+}
+
 func (ws *wallets) getBalance(addr []byte) string {
 	account := common.BytesToAddress(addr)
 	balance := core.GetBlockChain().GetBalance(account)
@@ -64,3 +64,19 @@ type shutdownError struct{}
 func (e *shutdownError) ErrorCode() int { return -32000 }
 
 func (e *shutdownError) Error() string { return "server is shutting down" }
+
+// issued when a batch holds more requests than allowed
+type batchTooLargeError struct{ limit int }
+
+func (e *batchTooLargeError) ErrorCode() int { return -32600 }
+
+func (e *batchTooLargeError) Error() string {
+	return fmt.Sprintf("batch too large, at most %d requests", e.limit)
+}
+
+// issued when the execution runs over the request timeout
+type timeoutError struct{}
+
+func (e *timeoutError) ErrorCode() int { return -32002 }
+
+func (e *timeoutError) Error() string { return "request timed out" }
@@ -213,7 +213,7 @@ func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("content-type", contentType)
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Headers", "X-Requested-With,X_Requested_With,Content-Type")
-	srv.ServeSingleRequest(codec, OptionMethodInvocation)
+	srv.serveRequest(codec, true, OptionMethodInvocation, r.RemoteAddr)
 }
 
 // validateRequest returns a non-zero response code and error message if the
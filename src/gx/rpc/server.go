@@ -27,6 +27,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 const MetadataApi = "rpc"
@@ -57,6 +58,22 @@ func NewServer() *Server {
 	return server
 }
 
+// RequestFilter decides whether the request of the method from the remote address is served.
+// A returned error rejects the request and is sent back to the client.
+type RequestFilter func(remote string, method string) error
+
+// remoteKey is used to store the remote address within the connection context.
+type remoteKey struct{}
+
+// SetLimits guards the server against abusive clients.
+// Zero timeout, batchLimit and payloadLimit mean no limit, payloadLimit applies to websocket messages.
+func (s *Server) SetLimits(filter RequestFilter, timeout time.Duration, batchLimit int, payloadLimit int) {
+	s.filter = filter
+	s.timeout = timeout
+	s.batchLimit = batchLimit
+	s.payloadLimit = payloadLimit
+}
+
 type RPCService struct {
 	server *Server
 }
@@ -112,7 +129,7 @@ func (s *Server) RegisterName(name string, rcvr interface{}) error {
 	return nil
 }
 
-func (s *Server) serveRequest(codec ServerCodec, singleShot bool, options CodecOption) error {
+func (s *Server) serveRequest(codec ServerCodec, singleShot bool, options CodecOption, remote string) error {
 	var pend sync.WaitGroup
 
 	defer func() {
@@ -131,6 +148,7 @@ func (s *Server) serveRequest(codec ServerCodec, singleShot bool, options CodecO
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	ctx = context.WithValue(ctx, remoteKey{}, remote)
 
 	if options&OptionSubscriptions == OptionSubscriptions {
 		ctx = context.WithValue(ctx, notifierKey{}, newNotifier(codec))
@@ -169,6 +187,13 @@ func (s *Server) serveRequest(codec ServerCodec, singleShot bool, options CodecO
 			}
 			return nil
 		}
+		if batch && s.batchLimit > 0 && len(reqs) > s.batchLimit {
+			codec.Write(codec.CreateErrorResponse(nil, &batchTooLargeError{s.batchLimit}))
+			if singleShot {
+				return nil
+			}
+			continue
+		}
 		// If a single shot request is executing, run and return immediately
 		if singleShot {
 			if batch {
@@ -194,12 +219,17 @@ func (s *Server) serveRequest(codec ServerCodec, singleShot bool, options CodecO
 }
 
 func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
+	s.serveCodec(codec, options, "")
+}
+
+// serveCodec serves the codec of a connection from the remote address
+func (s *Server) serveCodec(codec ServerCodec, options CodecOption, remote string) {
 	defer codec.Close()
-	s.serveRequest(codec, false, options)
+	s.serveRequest(codec, false, options, remote)
 }
 
 func (s *Server) ServeSingleRequest(codec ServerCodec, options CodecOption) {
-	s.serveRequest(codec, true, options)
+	s.serveRequest(codec, true, options, "")
 }
 
 func (s *Server) Stop() {
@@ -249,6 +279,13 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 		return codec.CreateErrorResponse(&req.id, &invalidParamsError{"Expected subscription id as first argument"}), nil
 	}
 
+	if s.filter != nil {
+		remote, _ := ctx.Value(remoteKey{}).(string)
+		if err := s.filter(remote, req.method); err != nil {
+			return codec.CreateErrorResponse(&req.id, asError(err)), nil
+		}
+	}
+
 	if req.callb.isSubscribe {
 		subid, err := s.createSubscription(ctx, codec, req)
 		if err != nil {
@@ -272,6 +309,11 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 		return codec.CreateErrorResponse(&req.id, rpcErr), nil
 	}
 
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
 	arguments := []reflect.Value{req.callb.rcvr}
 	if req.callb.hasCtx {
 		arguments = append(arguments, reflect.ValueOf(ctx))
@@ -280,8 +322,17 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 		arguments = append(arguments, req.args...)
 	}
 
-	// execute RPC method and return result
-	reply := req.callb.method.Func.Call(arguments)
+	// execute RPC method and return result, the method watching the context stops on timeout itself
+	replies := make(chan []reflect.Value, 1)
+	go func() {
+		replies <- req.callb.method.Func.Call(arguments)
+	}()
+	var reply []reflect.Value
+	select {
+	case reply = <-replies:
+	case <-ctx.Done():
+		return codec.CreateErrorResponse(&req.id, &timeoutError{}), nil
+	}
 	if len(reply) == 0 {
 		return codec.CreateResponse(req.id, nil), nil
 	}
@@ -382,7 +433,7 @@ func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, Error)
 
 		if r.isPubSub { // eth_subscribe, r.method contains the subscription method name
 			if callb, ok := svc.subscriptions[r.method]; ok {
-				requests[i] = &serverRequest{id: r.id, svcname: svc.name, callb: callb}
+				requests[i] = &serverRequest{id: r.id, svcname: svc.name, method: r.service + subscribeMethodSuffix, callb: callb}
 				if r.params != nil && len(callb.argTypes) > 0 {
 					argTypes := []reflect.Type{reflect.TypeOf("")}
 					argTypes = append(argTypes, callb.argTypes...)
@@ -399,7 +450,7 @@ func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, Error)
 		}
 
 		if callb, ok := svc.callbacks[r.method]; ok { // lookup RPC method
-			requests[i] = &serverRequest{id: r.id, svcname: svc.name, callb: callb}
+			requests[i] = &serverRequest{id: r.id, svcname: svc.name, method: r.service + serviceMethodSeparator + r.method, callb: callb}
 			if r.params != nil && len(callb.argTypes) > 0 {
 				if args, err := codec.ParseRequestArguments(callb.argTypes, r.params); err == nil {
 					requests[i].args = args
@@ -415,3 +466,11 @@ func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, Error)
 
 	return requests, batch, nil
 }
+
+// asError keeps the code of an error carrying one, others are taken as callback errors
+func asError(err error) Error {
+	if e, ok := err.(Error); ok {
+		return e
+	}
+	return &callbackError{err.Error()}
+}
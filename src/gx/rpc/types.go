@@ -19,6 +19,7 @@ package rpc
 import (
 	"reflect"
 	"sync"
+	"time"
 
 	"gopkg.in/fatih/set.v0"
 )
@@ -50,6 +51,7 @@ type service struct {
 type serverRequest struct {
 	id            interface{}
 	svcname       string
+	method        string // full method name, service and method joined by the separator
 	callb         *callback
 	args          []reflect.Value
 	isUnsubscribe bool
@@ -66,6 +68,12 @@ type Server struct {
 	run      int32
 	codecsMu sync.Mutex
 	codecs   set.Interface
+
+	// limits of the requests, zero values mean no limit
+	filter       RequestFilter
+	timeout      time.Duration
+	batchLimit   int
+	payloadLimit int
 }
 
 type rpcRequest struct {
@@ -35,7 +35,10 @@ func (srv *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 	return websocket.Server{
 		Handshake: wsHandshakeValidator(allowedOrigins),
 		Handler: func(conn *websocket.Conn) {
-			srv.ServeCodec(NewJSONCodec(conn), OptionMethodInvocation|OptionSubscriptions)
+			if srv.payloadLimit > 0 {
+				conn.MaxPayloadBytes = srv.payloadLimit
+			}
+			srv.serveCodec(NewJSONCodec(conn), OptionMethodInvocation|OptionSubscriptions, conn.Request().RemoteAddr)
 		},
 	}
 }
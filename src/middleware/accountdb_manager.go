@@ -36,6 +36,8 @@ type AccountDBManager struct {
 	logger log.Logger
 
 	waitingTxs *PriorityQueue
+
+	pruner *statePruner
 }
 
 var AccountDBManagerInstance AccountDBManager
@@ -52,6 +54,7 @@ func initAccountDBManager() {
 	}
 	AccountDBManagerInstance.db = db
 	AccountDBManagerInstance.stateDB = account.NewDatabase(db)
+	AccountDBManagerInstance.pruner = newStatePruner(AccountDBManagerInstance.stateDB.TrieDB(), AccountDBManagerInstance.logger)
 
 	AccountDBManagerInstance.waitingTxs = NewPriorityQueue()
 
@@ -59,13 +62,29 @@ func initAccountDBManager() {
 }
 
 func (manager *AccountDBManager) Close() {
+	if nil != manager.pruner {
+		manager.pruner.flush()
+	}
 	if nil != manager.db {
 		manager.db.Close()
 	}
 }
 
 func (manager *AccountDBManager) GetAccountDBByHash(hash common.Hash) (*account.AccountDB, error) {
-	return account.NewAccountDB(hash, manager.stateDB)
+	accountDB, err := account.NewAccountDB(hash, manager.stateDB)
+	if _, missing := err.(*trie.MissingNodeError); missing && nil != manager.pruner {
+		return nil, ErrStatePruned
+	}
+	return accountDB, err
+}
+
+// CommitState writes the state of the given block to the trie database.
+// In gcmode full the state is kept in memory and garbage collected once out of the retention window.
+func (manager *AccountDBManager) CommitState(root common.Hash, height uint64) error {
+	if nil == manager.pruner {
+		return manager.stateDB.TrieDB().Commit(root, false)
+	}
+	return manager.pruner.commit(root, height)
 }
 
 func (manager *AccountDBManager) GetLatestStateDB() *account.AccountDB {
@@ -76,6 +95,11 @@ func (manager *AccountDBManager) GetTrieDB() *trie.NodeDatabase {
 	return manager.stateDB.TrieDB()
 }
 
+// GetStateDiskDB returns the disk database of the state, the trie nodes are written without the trie database
+func (manager *AccountDBManager) GetStateDiskDB() *db.LDBDatabase {
+	return manager.db
+}
+
 func (manager *AccountDBManager) SetLatestStateDB(latestStateDB *account.AccountDB, nonces map[string]uint64, height uint64) {
 	key := "fixed"
 	nonce := nonces[key]
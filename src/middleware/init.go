@@ -19,6 +19,7 @@ package middleware
 import (
 	"com.tuntun.rangers/node/src/common"
 	"com.tuntun.rangers/node/src/middleware/log"
+	"com.tuntun.rangers/node/src/middleware/logindex"
 	"com.tuntun.rangers/node/src/middleware/mysql"
 	"com.tuntun.rangers/node/src/middleware/notify"
 	"com.tuntun.rangers/node/src/middleware/types"
@@ -45,6 +46,7 @@ func InitMiddleware() error {
 	types.InitSerialzation()
 	notify.BUS = notify.NewBus()
 	mysql.InitMySql()
+	logindex.InitLogIndex()
 
 	InitLock()
 	InitDataChannel()
@@ -54,6 +56,7 @@ func InitMiddleware() error {
 
 func Close() {
 	AccountDBManagerInstance.Close()
+	logindex.Close()
 	mysql.CloseMysql()
 }
 
@@ -253,6 +253,15 @@ const (
 						</formats>
 					</seelog>`
 
+	EvidenceLogConfig = `<seelog minlevel="debug">
+						<outputs formatid="default">
+							<rollingfile type="size" filename="./logs/evidenceLOG_INDEX.log" maxsize="100000000" maxrolls="1"/>
+						</outputs>
+						<formats>
+							<format id="default" format="%Date(2006-01-02 15:04:05.000)[%File:%Line]%Msg%n" />
+						</formats>
+					</seelog>`
+
 	GroupCreateDebugLogConfig = `<seelog minlevel="debug">
 						<outputs formatid="default">
 							<rollingfile type="size" filename="./logs/group_create_debugLOG_INDEX.log" maxsize="50000000" maxrolls="1"/>
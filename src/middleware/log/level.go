@@ -0,0 +1,115 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package log
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/cihub/seelog"
+)
+
+// level is the minimum level of all loggers, it only filters above the minlevel of the config
+var level = int32(seelog.TraceLvl)
+
+// SetLevel sets the minimum level of all loggers, one of trace, debug, info, warn and error
+func SetLevel(name string) error {
+	lvl, ok := seelog.LogLevelFromString(name)
+	if !ok || lvl > seelog.ErrorLvl {
+		return fmt.Errorf("unknown log level: %s", name)
+	}
+	atomic.StoreInt32(&level, int32(lvl))
+	return nil
+}
+
+// GetLevel returns the minimum level of all loggers
+func GetLevel() string {
+	return seelog.LogLevel(atomic.LoadInt32(&level)).String()
+}
+
+func enabled(lvl seelog.LogLevel) bool {
+	return int32(lvl) >= atomic.LoadInt32(&level)
+}
+
+// leveledLogger drops the messages below the level before formatting them
+type leveledLogger struct {
+	seelog.LoggerInterface
+}
+
+func newLeveledLogger(l seelog.LoggerInterface) Logger {
+	// skip the frame of leveledLogger for %File:%Line
+	l.SetAdditionalStackDepth(1)
+	return leveledLogger{l}
+}
+
+func (l leveledLogger) Tracef(format string, params ...interface{}) {
+	if enabled(seelog.TraceLvl) {
+		l.LoggerInterface.Tracef(format, params...)
+	}
+}
+
+func (l leveledLogger) Debugf(format string, params ...interface{}) {
+	if enabled(seelog.DebugLvl) {
+		l.LoggerInterface.Debugf(format, params...)
+	}
+}
+
+func (l leveledLogger) Infof(format string, params ...interface{}) {
+	if enabled(seelog.InfoLvl) {
+		l.LoggerInterface.Infof(format, params...)
+	}
+}
+
+func (l leveledLogger) Warnf(format string, params ...interface{}) error {
+	if enabled(seelog.WarnLvl) {
+		return l.LoggerInterface.Warnf(format, params...)
+	}
+	return nil
+}
+
+func (l leveledLogger) Errorf(format string, params ...interface{}) error {
+	if enabled(seelog.ErrorLvl) {
+		return l.LoggerInterface.Errorf(format, params...)
+	}
+	return nil
+}
+
+func (l leveledLogger) Debug(v ...interface{}) {
+	if enabled(seelog.DebugLvl) {
+		l.LoggerInterface.Debug(v...)
+	}
+}
+
+func (l leveledLogger) Info(v ...interface{}) {
+	if enabled(seelog.InfoLvl) {
+		l.LoggerInterface.Info(v...)
+	}
+}
+
+func (l leveledLogger) Warn(v ...interface{}) error {
+	if enabled(seelog.WarnLvl) {
+		return l.LoggerInterface.Warn(v...)
+	}
+	return nil
+}
+
+func (l leveledLogger) Error(v ...interface{}) error {
+	if enabled(seelog.ErrorLvl) {
+		return l.LoggerInterface.Error(v...)
+	}
+	return nil
+}
@@ -101,7 +101,7 @@ func newLoggerByConfig(config string) Logger {
 		fmt.Printf("Get logger error:%s\n", err.Error())
 		panic(err)
 	}
-	return l
+	return newLeveledLogger(l)
 }
 
 func register(name string, logger Logger) {
@@ -19,6 +19,8 @@ package log
 import (
 	"testing"
 	"time"
+
+	"github.com/cihub/seelog"
 )
 
 // seelog wiki:https://github.com/cihub/seelog/wiki
@@ -126,3 +128,16 @@ func TestGetLoggerBySameName(t *testing.T) {
 	l.Error("TestGetLoggerBySameName logFunc error output")
 	Close()
 }
+
+func TestSetLevel(t *testing.T) {
+	defer SetLevel("trace")
+	if err := SetLevel("warn"); err != nil {
+		t.Fatal(err)
+	}
+	if "warn" != GetLevel() || enabled(seelog.InfoLvl) || !enabled(seelog.ErrorLvl) {
+		t.Fatalf("wrong level: %s", GetLevel())
+	}
+	if err := SetLevel("critical"); err == nil {
+		t.Fatal("unsupported level accepted")
+	}
+}
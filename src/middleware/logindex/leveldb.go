@@ -0,0 +1,288 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package logindex
+
+import (
+	"bytes"
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/middleware/db"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"com.tuntun.rangers/node/src/utility"
+	"encoding/binary"
+	"encoding/json"
+	"sync"
+)
+
+// The leveldb log index keeps the logs of a block under its height.
+// For every section of blocks and every bit of types.Bloom, a bit vector marks the blocks whose bloom has the bit set.
+// A filter reads the vectors of its addresses and topics, and only loads the blocks matching all of them.
+// Bits of removed blocks are not cleared, they only cause false positives.
+const (
+	ldbLogIndexName = "logindex"
+
+	sectionSize      = 4096
+	sectionBytes     = sectionSize / 8
+	defaultLDBLimit  = 100000
+	bloomBitsLength  = types.BloomByteLength * 8
+	blockLogsPrefix  = "l"
+	blockHashPrefix  = "h"
+	bloomBitsPrefix  = "b"
+	blockLogsKeySize = 9
+)
+
+type blockLogs struct {
+	Hash common.Hash  `json:"hash"`
+	Logs []*types.Log `json:"logs"`
+}
+
+type ldbLogIndex struct {
+	db    db.Database
+	limit uint64
+	lock  sync.Mutex
+}
+
+func newLDBLogIndex() (*ldbLogIndex, error) {
+	ldb, err := db.NewLDBDatabase(ldbLogIndexName, 128, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := uint64(defaultLDBLimit)
+	if nil != common.GlobalConf {
+		limit = uint64(common.GlobalConf.GetInt(common.ConfigSec, "logIndexBlockLimit", defaultLDBLimit))
+	}
+	return &ldbLogIndex{db: ldb, limit: limit}, nil
+}
+
+func blockLogsKey(height uint64) []byte {
+	key := make([]byte, blockLogsKeySize)
+	key[0] = blockLogsPrefix[0]
+	binary.BigEndian.PutUint64(key[1:], height)
+	return key
+}
+
+func blockHashKey(hash common.Hash) []byte {
+	return append([]byte(blockHashPrefix), hash.Bytes()...)
+}
+
+func bloomBitsKey(bit uint, section uint64) []byte {
+	key := make([]byte, 11)
+	key[0] = bloomBitsPrefix[0]
+	binary.BigEndian.PutUint16(key[1:], uint16(bit))
+	binary.BigEndian.PutUint64(key[3:], section)
+	return key
+}
+
+func (index *ldbLogIndex) InsertLogs(height uint64, receipts types.Receipts, blockHash common.Hash) {
+	logs := make([]*types.Log, 0)
+	valid := make(types.Receipts, 0, len(receipts))
+	for _, receipt := range receipts {
+		if nil == receipt {
+			continue
+		}
+		valid = append(valid, receipt)
+		for i, log := range receipt.Logs {
+			item := *log
+			item.BlockHash = blockHash
+			item.BlockNumber = height
+			item.TxHash = receipt.TxHash
+			item.Index = uint(i)
+			logs = append(logs, &item)
+		}
+	}
+	if 0 == len(logs) {
+		return
+	}
+
+	data, err := json.Marshal(blockLogs{Hash: blockHash, Logs: logs})
+	if err != nil {
+		logger.Errorf("fail to marshal logs, height: %d, hash: %s, err: %s", height, blockHash.String(), err)
+		return
+	}
+
+	index.lock.Lock()
+	defer index.lock.Unlock()
+
+	batch := index.db.NewBatch()
+	batch.Put(blockLogsKey(height), data)
+	batch.Put(blockHashKey(blockHash), utility.UInt64ToByte(height))
+
+	section, offset := height/sectionSize, height%sectionSize
+	bloom := types.CreateBloom(valid)
+	for _, bit := range bloom.Bits() {
+		key := bloomBitsKey(bit, section)
+		vector, _ := index.db.Get(key)
+		if sectionBytes != len(vector) {
+			vector = make([]byte, sectionBytes)
+		}
+		vector[offset/8] |= 1 << (offset % 8)
+		batch.Put(key, vector)
+	}
+	if err := batch.Write(); err != nil {
+		logger.Errorf("fail to write logs, height: %d, hash: %s, err: %s", height, blockHash.String(), err)
+		return
+	}
+	logger.Debugf("inserted logs, height: %d, hash: %s, logs: %d", height, blockHash.String(), len(logs))
+}
+
+func (index *ldbLogIndex) DeleteLogs(height uint64, blockHash common.Hash) {
+	index.lock.Lock()
+	defer index.lock.Unlock()
+
+	stored := index.getBlockLogs(height)
+	if nil == stored || stored.Hash != blockHash {
+		return
+	}
+	index.db.Delete(blockLogsKey(height))
+	index.db.Delete(blockHashKey(blockHash))
+	logger.Debugf("deleted logs, height: %d, hash: %s", height, blockHash.String())
+}
+
+func (index *ldbLogIndex) SelectLogs(from, to uint64, addresses []common.Address, topics [][]common.Hash) []*types.Log {
+	result := make([]*types.Log, 0)
+	if from > to {
+		return result
+	}
+
+	matchers := bloomMatchers(addresses, topics)
+	if 0 == len(matchers) {
+		it := index.db.NewIteratorWithPrefix([]byte(blockLogsPrefix))
+		defer it.Release()
+		end := blockLogsKey(to)
+		for ok := it.Seek(blockLogsKey(from)); ok && bytes.Compare(it.Key(), end) <= 0; ok = it.Next() {
+			var stored blockLogs
+			if err := json.Unmarshal(it.Value(), &stored); err != nil {
+				logger.Errorf("fail to unmarshal logs, key: %x, err: %s", it.Key(), err)
+				continue
+			}
+			result = append(result, filterLogs(stored.Logs, addresses, topics)...)
+		}
+		return result
+	}
+
+	for section := from / sectionSize; section <= to/sectionSize; section++ {
+		vector := index.matchSection(section, matchers)
+		for offset := uint64(0); offset < sectionSize; offset++ {
+			if 0 == vector[offset/8]&(1<<(offset%8)) {
+				continue
+			}
+			height := section*sectionSize + offset
+			if height < from || height > to {
+				continue
+			}
+			if stored := index.getBlockLogs(height); nil != stored {
+				result = append(result, filterLogs(stored.Logs, addresses, topics)...)
+			}
+		}
+	}
+	return result
+}
+
+func (index *ldbLogIndex) SelectLogsByHash(blockHash common.Hash, addresses []common.Address, topics [][]common.Hash) []*types.Log {
+	result := make([]*types.Log, 0)
+	data, _ := index.db.Get(blockHashKey(blockHash))
+	if 8 != len(data) {
+		return result
+	}
+	stored := index.getBlockLogs(binary.BigEndian.Uint64(data))
+	if nil == stored || stored.Hash != blockHash {
+		return result
+	}
+	return append(result, filterLogs(stored.Logs, addresses, topics)...)
+}
+
+func (index *ldbLogIndex) BlockLimit() uint64 {
+	return index.limit
+}
+
+func (index *ldbLogIndex) Close() {
+	index.db.Close()
+}
+
+func (index *ldbLogIndex) getBlockLogs(height uint64) *blockLogs {
+	data, _ := index.db.Get(blockLogsKey(height))
+	if nil == data {
+		return nil
+	}
+	var stored blockLogs
+	if err := json.Unmarshal(data, &stored); err != nil {
+		logger.Errorf("fail to unmarshal logs, height: %d, err: %s", height, err)
+		return nil
+	}
+	return &stored
+}
+
+// bloomMatchers returns the bloom bits of the filter.
+// A block matches if for every group, all bits of any of the items are set.
+func bloomMatchers(addresses []common.Address, topics [][]common.Hash) [][][]uint {
+	matchers := make([][][]uint, 0)
+	if 0 != len(addresses) {
+		group := make([][]uint, 0, len(addresses))
+		for _, address := range addresses {
+			group = append(group, types.BytesBloom(address.Bytes()).Bits())
+		}
+		matchers = append(matchers, group)
+	}
+	for _, sub := range topics {
+		if 0 == len(sub) {
+			continue
+		}
+		group := make([][]uint, 0, len(sub))
+		for _, topic := range sub {
+			group = append(group, types.BytesBloom(topic.Bytes()).Bits())
+		}
+		matchers = append(matchers, group)
+	}
+	return matchers
+}
+
+// matchSection returns the bit vector of the blocks in the section matching all the matchers
+func (index *ldbLogIndex) matchSection(section uint64, matchers [][][]uint) []byte {
+	vectors := make(map[uint][]byte)
+	load := func(bit uint) []byte {
+		if vector, ok := vectors[bit]; ok {
+			return vector
+		}
+		vector, _ := index.db.Get(bloomBitsKey(bit, section))
+		if sectionBytes != len(vector) {
+			vector = make([]byte, sectionBytes)
+		}
+		vectors[bit] = vector
+		return vector
+	}
+
+	result := bytes.Repeat([]byte{0xff}, sectionBytes)
+	for _, group := range matchers {
+		matched := make([]byte, sectionBytes)
+		for _, bits := range group {
+			all := bytes.Repeat([]byte{0xff}, sectionBytes)
+			for _, bit := range bits {
+				vector := load(bit)
+				for i := range all {
+					all[i] &= vector[i]
+				}
+			}
+			for i := range matched {
+				matched[i] |= all[i]
+			}
+		}
+		for i := range result {
+			result[i] &= matched[i]
+		}
+	}
+	return result
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package logindex
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/middleware/db"
+	"com.tuntun.rangers/node/src/middleware/log"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"os"
+	"testing"
+)
+
+func TestLDBLogIndex(t *testing.T) {
+	defer os.RemoveAll("storage0")
+	defer os.RemoveAll("logs")
+	logger = log.GetLoggerByIndex(log.MysqlLogConfig, "0")
+
+	ldb, err := db.NewLDBDatabase(ldbLogIndexName, 16, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := &ldbLogIndex{db: ldb, limit: defaultLDBLimit}
+	defer index.Close()
+
+	contract1 := common.BytesToAddress([]byte("contract1"))
+	contract2 := common.BytesToAddress([]byte("contract2"))
+	transfer := common.BytesToHash([]byte("transfer"))
+	approve := common.BytesToHash([]byte("approve"))
+	owner := common.BytesToHash([]byte("owner"))
+
+	receipts := func(logs ...*types.Log) types.Receipts {
+		return types.Receipts{&types.Receipt{TxHash: common.BytesToHash([]byte("tx")), Logs: logs}}
+	}
+	hash1 := common.BytesToHash([]byte("block1"))
+	hash2 := common.BytesToHash([]byte("block2"))
+	hash3 := common.BytesToHash([]byte("block3"))
+	index.InsertLogs(1, receipts(&types.Log{Address: contract1, Topics: []common.Hash{transfer, owner}}), hash1)
+	index.InsertLogs(sectionSize+2, receipts(&types.Log{Address: contract2, Topics: []common.Hash{approve}}), hash2)
+	index.InsertLogs(sectionSize+3, receipts(&types.Log{Address: contract1, Topics: []common.Hash{approve, owner}}), hash3)
+
+	cases := []struct {
+		from, to  uint64
+		addresses []common.Address
+		topics    [][]common.Hash
+		expected  []uint64
+	}{
+		{0, 2 * sectionSize, nil, nil, []uint64{1, sectionSize + 2, sectionSize + 3}},
+		{2, 2 * sectionSize, nil, nil, []uint64{sectionSize + 2, sectionSize + 3}},
+		{0, 2 * sectionSize, []common.Address{contract1}, nil, []uint64{1, sectionSize + 3}},
+		{0, 2 * sectionSize, nil, [][]common.Hash{{approve}}, []uint64{sectionSize + 2, sectionSize + 3}},
+		{0, 2 * sectionSize, nil, [][]common.Hash{{transfer, approve}, {owner}}, []uint64{1, sectionSize + 3}},
+		{0, 2 * sectionSize, []common.Address{contract2}, [][]common.Hash{{}, {owner}}, []uint64{}},
+		{0, sectionSize + 2, []common.Address{contract1, contract2}, [][]common.Hash{{approve}}, []uint64{sectionSize + 2}},
+	}
+	for i, c := range cases {
+		logs := index.SelectLogs(c.from, c.to, c.addresses, c.topics)
+		if len(logs) != len(c.expected) {
+			t.Fatalf("case %d: expected %d logs, got %d", i, len(c.expected), len(logs))
+		}
+		for j, log := range logs {
+			if log.BlockNumber != c.expected[j] {
+				t.Fatalf("case %d: expected height %d, got %d", i, c.expected[j], log.BlockNumber)
+			}
+		}
+	}
+
+	if logs := index.SelectLogsByHash(hash3, nil, [][]common.Hash{{approve}}); 1 != len(logs) || logs[0].BlockHash != hash3 {
+		t.Fatalf("unexpected logs by hash: %v", logs)
+	}
+
+	index.DeleteLogs(sectionSize+3, hash2)
+	if logs := index.SelectLogsByHash(hash3, nil, nil); 1 != len(logs) {
+		t.Fatal("logs deleted by another block hash")
+	}
+	index.DeleteLogs(sectionSize+3, hash3)
+	if logs := index.SelectLogsByHash(hash3, nil, nil); 0 != len(logs) {
+		t.Fatal("logs not deleted")
+	}
+	if logs := index.SelectLogs(0, 2*sectionSize, []common.Address{contract1}, nil); 1 != len(logs) {
+		t.Fatalf("expected 1 log after delete, got %d", len(logs))
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package logindex
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/middleware/log"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"strconv"
+)
+
+// log index backends, set by "logIndex" in the chain section
+const (
+	SQLite  = "sqlite"
+	LevelDB = "leveldb"
+)
+
+// LogIndex stores the contract logs of the main chain blocks and answers log filters
+type LogIndex interface {
+	InsertLogs(height uint64, receipts types.Receipts, blockHash common.Hash)
+
+	// DeleteLogs removes the logs of a block removed from the main chain
+	DeleteLogs(height uint64, blockHash common.Hash)
+
+	// SelectLogs returns the logs in [from, to] of the contracts, matching the topics by position
+	SelectLogs(from, to uint64, addresses []common.Address, topics [][]common.Hash) []*types.Log
+
+	SelectLogsByHash(blockHash common.Hash, addresses []common.Address, topics [][]common.Hash) []*types.Log
+
+	// BlockLimit is the max block range of SelectLogs
+	BlockLimit() uint64
+
+	Close()
+}
+
+var (
+	instance LogIndex
+	logger   log.Logger
+)
+
+func InitLogIndex() {
+	logger = log.GetLoggerByIndex(log.MysqlLogConfig, strconv.Itoa(common.InstanceIndex))
+
+	backend := SQLite
+	if nil != common.GlobalConf {
+		backend = common.GlobalConf.GetString(common.ConfigSec, "logIndex", SQLite)
+	}
+	switch backend {
+	case LevelDB:
+		index, err := newLDBLogIndex()
+		if err != nil {
+			panic("Init log index error:" + err.Error())
+		}
+		instance = index
+	default:
+		instance = &sqliteLogIndex{}
+	}
+	logger.Infof("log index: %s", backend)
+}
+
+func GetLogIndex() LogIndex {
+	return instance
+}
+
+func Close() {
+	if nil != instance {
+		instance.Close()
+	}
+}
+
+// filterLogs keeps the logs of the contracts matching the topics
+func filterLogs(logs []*types.Log, addresses []common.Address, topics [][]common.Hash) []*types.Log {
+	if 0 != len(addresses) {
+		result := make([]*types.Log, 0, len(logs))
+		for _, log := range logs {
+			for _, address := range addresses {
+				if log.Address == address {
+					result = append(result, log)
+					break
+				}
+			}
+		}
+		logs = result
+	}
+	if 0 == len(topics) {
+		return logs
+	}
+	return types.FilterLogsByTopics(logs, topics)
+}
@@ -0,0 +1,52 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package logindex
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/middleware/mysql"
+	"com.tuntun.rangers/node/src/middleware/types"
+)
+
+const sqliteBlockLimit = 1000
+
+// sqliteLogIndex keeps logs in the contractlogs table of storage<N>/logs/logs.db
+type sqliteLogIndex struct{}
+
+func (index *sqliteLogIndex) InsertLogs(height uint64, receipts types.Receipts, blockHash common.Hash) {
+	mysql.InsertLogs(height, receipts, blockHash)
+}
+
+func (index *sqliteLogIndex) DeleteLogs(height uint64, blockHash common.Hash) {
+	mysql.DeleteLogs(height, blockHash)
+}
+
+func (index *sqliteLogIndex) SelectLogs(from, to uint64, addresses []common.Address, topics [][]common.Hash) []*types.Log {
+	return mysql.SelectLogsByTopics(from, to, addresses, topics)
+}
+
+func (index *sqliteLogIndex) SelectLogsByHash(blockHash common.Hash, addresses []common.Address, topics [][]common.Hash) []*types.Log {
+	return mysql.SelectLogsByHashAndTopics(blockHash, addresses, topics)
+}
+
+func (index *sqliteLogIndex) BlockLimit() uint64 {
+	return sqliteBlockLimit
+}
+
+// the sqlite db is closed with the group index in mysql.CloseMysql
+func (index *sqliteLogIndex) Close() {
+}
@@ -0,0 +1,417 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const namespace = "rangers"
+
+// DefBuckets are the histogram buckets in seconds, for the durations from milliseconds to a minute
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60}
+
+type collector interface {
+	metricType() string
+	write(w io.Writer, name string)
+}
+
+// Registry keeps the metrics by name and writes them in the prometheus text format
+type Registry struct {
+	lock       sync.RWMutex
+	collectors map[string]collector
+	helps      map[string]string
+}
+
+var DefaultRegistry = NewRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]collector), helps: make(map[string]string)}
+}
+
+// register adds the metric, replacing the former one of the same name,
+// so that the components can be initialized again in the tests
+func (r *Registry) register(name, help string, c collector) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	name = namespace + "_" + name
+	r.collectors[name] = c
+	r.helps[name] = help
+}
+
+// Write writes all the metrics sorted by name
+func (r *Registry) Write(w io.Writer) error {
+	r.lock.RLock()
+	names := make([]string, 0, len(r.collectors))
+	for name := range r.collectors {
+		names = append(names, name)
+	}
+	r.lock.RUnlock()
+	sort.Strings(names)
+
+	buf := bufio.NewWriter(w)
+	for _, name := range names {
+		r.lock.RLock()
+		c, help := r.collectors[name], r.helps[name]
+		r.lock.RUnlock()
+
+		fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
+		fmt.Fprintf(buf, "# TYPE %s %s\n", name, c.metricType())
+		c.write(buf, name)
+	}
+	return buf.Flush()
+}
+
+// value is a float64 updated atomically
+type value struct {
+	bits uint64
+}
+
+func (v *value) add(delta float64) {
+	for {
+		old := atomic.LoadUint64(&v.bits)
+		next := math.Float64bits(math.Float64frombits(old) + delta)
+		if atomic.CompareAndSwapUint64(&v.bits, old, next) {
+			return
+		}
+	}
+}
+
+func (v *value) set(f float64) {
+	atomic.StoreUint64(&v.bits, math.Float64bits(f))
+}
+
+func (v *value) get() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&v.bits))
+}
+
+// Counter only goes up
+type Counter struct {
+	value
+}
+
+func NewCounter(name, help string) *Counter {
+	c := &Counter{}
+	DefaultRegistry.register(name, help, c)
+	return c
+}
+
+func (c *Counter) Inc() {
+	c.add(1)
+}
+
+func (c *Counter) Add(delta float64) {
+	if delta < 0 {
+		return
+	}
+	c.add(delta)
+}
+
+func (c *Counter) Value() float64 {
+	return c.get()
+}
+
+func (c *Counter) metricType() string {
+	return "counter"
+}
+
+func (c *Counter) write(w io.Writer, name string) {
+	writeSample(w, name, "", c.get())
+}
+
+// Gauge goes up and down
+type Gauge struct {
+	value
+}
+
+func NewGauge(name, help string) *Gauge {
+	g := &Gauge{}
+	DefaultRegistry.register(name, help, g)
+	return g
+}
+
+func (g *Gauge) Set(f float64) {
+	g.set(f)
+}
+
+func (g *Gauge) Inc() {
+	g.add(1)
+}
+
+func (g *Gauge) Dec() {
+	g.add(-1)
+}
+
+func (g *Gauge) Value() float64 {
+	return g.get()
+}
+
+func (g *Gauge) metricType() string {
+	return "gauge"
+}
+
+func (g *Gauge) write(w io.Writer, name string) {
+	writeSample(w, name, "", g.get())
+}
+
+// gaugeFunc reads the value when scraped
+type gaugeFunc func() float64
+
+// NewGaugeFunc registers a gauge whose value is read from the function when scraped
+func NewGaugeFunc(name, help string, f func() float64) {
+	DefaultRegistry.register(name, help, gaugeFunc(f))
+}
+
+func (f gaugeFunc) metricType() string {
+	return "gauge"
+}
+
+func (f gaugeFunc) write(w io.Writer, name string) {
+	writeSample(w, name, "", f())
+}
+
+// Histogram counts the observations in the buckets
+type Histogram struct {
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     value
+}
+
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	h := newHistogram(buckets)
+	DefaultRegistry.register(name, help, h)
+	return h
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	return &Histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (h *Histogram) Observe(v float64) {
+	i := sort.SearchFloat64s(h.buckets, v)
+	if i < len(h.buckets) {
+		atomic.AddUint64(&h.counts[i], 1)
+	}
+	atomic.AddUint64(&h.count, 1)
+	h.sum.add(v)
+}
+
+// UpdateSince observes the seconds elapsed since the start
+func (h *Histogram) UpdateSince(start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
+
+func (h *Histogram) Count() uint64 {
+	return atomic.LoadUint64(&h.count)
+}
+
+func (h *Histogram) metricType() string {
+	return "histogram"
+}
+
+func (h *Histogram) write(w io.Writer, name string) {
+	h.writeLabeled(w, name, "")
+}
+
+func (h *Histogram) writeLabeled(w io.Writer, name, labels string) {
+	var cumulative uint64
+	for i, bound := range h.buckets {
+		cumulative += atomic.LoadUint64(&h.counts[i])
+		writeSample(w, name+"_bucket", joinLabels(labels, `le="`+formatFloat(bound)+`"`), float64(cumulative))
+	}
+	count := atomic.LoadUint64(&h.count)
+	writeSample(w, name+"_bucket", joinLabels(labels, `le="+Inf"`), float64(count))
+	writeSample(w, name+"_sum", labels, h.sum.get())
+	writeSample(w, name+"_count", labels, float64(count))
+}
+
+// vec keeps the children of a metric by the label values
+type vec struct {
+	labels   []string
+	lock     sync.RWMutex
+	children map[string]interface{}
+	keys     map[string]string
+	newChild func() interface{}
+}
+
+func newVec(labels []string, newChild func() interface{}) *vec {
+	return &vec{labels: labels, children: make(map[string]interface{}), keys: make(map[string]string), newChild: newChild}
+}
+
+func (v *vec) with(values []string) interface{} {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %d label values for %d labels", len(values), len(v.labels)))
+	}
+	key := strings.Join(values, "\xff")
+
+	v.lock.RLock()
+	child, ok := v.children[key]
+	v.lock.RUnlock()
+	if ok {
+		return child
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	if child, ok = v.children[key]; ok {
+		return child
+	}
+	pairs := make([]string, len(values))
+	for i, label := range v.labels {
+		pairs[i] = label + `="` + escapeLabel(values[i]) + `"`
+	}
+	child = v.newChild()
+	v.children[key] = child
+	v.keys[key] = strings.Join(pairs, ",")
+	return child
+}
+
+func (v *vec) each(f func(labels string, child interface{})) {
+	v.lock.RLock()
+	keys := make([]string, 0, len(v.children))
+	for key := range v.children {
+		keys = append(keys, key)
+	}
+	v.lock.RUnlock()
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		v.lock.RLock()
+		labels, child := v.keys[key], v.children[key]
+		v.lock.RUnlock()
+		f(labels, child)
+	}
+}
+
+// CounterVec is a counter partitioned by the labels
+type CounterVec struct {
+	*vec
+}
+
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{newVec(labels, func() interface{} { return &Counter{} })}
+	DefaultRegistry.register(name, help, c)
+	return c
+}
+
+func (c *CounterVec) WithLabelValues(values ...string) *Counter {
+	return c.with(values).(*Counter)
+}
+
+func (c *CounterVec) metricType() string {
+	return "counter"
+}
+
+func (c *CounterVec) write(w io.Writer, name string) {
+	c.each(func(labels string, child interface{}) {
+		writeSample(w, name, labels, child.(*Counter).get())
+	})
+}
+
+// GaugeVec is a gauge partitioned by the labels
+type GaugeVec struct {
+	*vec
+}
+
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	g := &GaugeVec{newVec(labels, func() interface{} { return &Gauge{} })}
+	DefaultRegistry.register(name, help, g)
+	return g
+}
+
+func (g *GaugeVec) WithLabelValues(values ...string) *Gauge {
+	return g.with(values).(*Gauge)
+}
+
+func (g *GaugeVec) metricType() string {
+	return "gauge"
+}
+
+func (g *GaugeVec) write(w io.Writer, name string) {
+	g.each(func(labels string, child interface{}) {
+		writeSample(w, name, labels, child.(*Gauge).get())
+	})
+}
+
+// HistogramVec is a histogram partitioned by the labels
+type HistogramVec struct {
+	*vec
+}
+
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{newVec(labels, func() interface{} { return newHistogram(buckets) })}
+	DefaultRegistry.register(name, help, h)
+	return h
+}
+
+func (h *HistogramVec) WithLabelValues(values ...string) *Histogram {
+	return h.with(values).(*Histogram)
+}
+
+func (h *HistogramVec) metricType() string {
+	return "histogram"
+}
+
+func (h *HistogramVec) write(w io.Writer, name string) {
+	h.each(func(labels string, child interface{}) {
+		child.(*Histogram).writeLabeled(w, name, labels)
+	})
+}
+
+func writeSample(w io.Writer, name, labels string, v float64) {
+	if 0 == len(labels) {
+		fmt.Fprintf(w, "%s %s\n", name, formatFloat(v))
+		return
+	}
+	fmt.Fprintf(w, "%s{%s} %s\n", name, labels, formatFloat(v))
+}
+
+func joinLabels(labels, label string) string {
+	if 0 == len(labels) {
+		return label
+	}
+	return labels + "," + label
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func escapeLabel(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, "\n", `\n`, -1)
+	return strings.Replace(s, `"`, `\"`, -1)
+}
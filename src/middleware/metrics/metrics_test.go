@@ -0,0 +1,76 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistryWrite(t *testing.T) {
+	counter := NewCounter("test_counter", "a counter")
+	counter.Inc()
+	counter.Add(2)
+	counter.Add(-1)
+
+	gauge := NewGauge("test_gauge", "a gauge")
+	gauge.Set(5)
+	gauge.Dec()
+
+	NewGaugeFunc("test_func", "a gauge func", func() float64 { return 7 })
+
+	histogram := NewHistogram("test_histogram", "a histogram", []float64{1, 2})
+	histogram.Observe(0.5)
+	histogram.Observe(1.5)
+	histogram.Observe(3)
+
+	vec := NewCounterVec("test_vec", "a counter vec", "result")
+	vec.WithLabelValues("done").Inc()
+	vec.WithLabelValues(`say "hi"`).Add(2)
+
+	var buf bytes.Buffer
+	if err := DefaultRegistry.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+	for _, line := range []string{
+		"# TYPE rangers_test_counter counter",
+		"rangers_test_counter 3",
+		"rangers_test_gauge 4",
+		"rangers_test_func 7",
+		"# TYPE rangers_test_histogram histogram",
+		`rangers_test_histogram_bucket{le="1"} 1`,
+		`rangers_test_histogram_bucket{le="2"} 2`,
+		`rangers_test_histogram_bucket{le="+Inf"} 3`,
+		"rangers_test_histogram_sum 5",
+		"rangers_test_histogram_count 3",
+		`rangers_test_vec{result="done"} 1`,
+		`rangers_test_vec{result="say \"hi\""} 2`,
+	} {
+		if !strings.Contains(output, line+"\n") {
+			t.Fatalf("missing %q in\n%s", line, output)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	if recorder.Body.String() != output {
+		t.Fatal("handler output mismatch")
+	}
+}
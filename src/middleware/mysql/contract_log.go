@@ -21,62 +21,39 @@ import (
 	"com.tuntun.rangers/node/src/middleware/types"
 	"com.tuntun.rangers/node/src/utility"
 	"encoding/json"
+	"strings"
 )
 
-func SelectLogs(from, to uint64, contractAddresses []common.Address) []*types.Log {
-	if nil == mysqlDBLog {
-		return nil
-	}
-
-	sql := "select height,logindex, blockhash,txhash,contractaddress,topic,data FROM contractlogs WHERE (height>=? and height<=?) "
-	if 0 != len(contractAddresses) {
-		sql += "and( "
-		for _, contractAddress := range contractAddresses {
-			sql += " contractaddress = \"" + contractAddress.GetHexString() + "\" " + "or"
-		}
-		sql = sql[:len(sql)-2] + ")"
-	}
-
-	rows, err := mysqlDBLog.Query(sql, from, to)
-	if err != nil {
-		return nil
-	}
-	defer rows.Close()
+var topicColumns = []string{"topic0", "topic1", "topic2", "topic3"}
 
-	result := make([]*types.Log, 0)
-	for rows.Next() {
-		var (
-			height, index                                   uint64
-			blockhash, txhash, contractaddress, topic, data string
-		)
-		err := rows.Scan(&height, &index, &blockhash, &txhash, &contractaddress, &topic, &data)
-		if err != nil {
-			logger.Errorf("scan failed, err: %v", err)
-			return nil
-		}
+func SelectLogs(from, to uint64, contractAddresses []common.Address) []*types.Log {
+	return SelectLogsByTopics(from, to, contractAddresses, nil)
+}
 
-		log := types.Log{
-			Address:     common.HexToAddress(contractaddress),
-			Data:        common.FromHex(data),
-			TxHash:      common.HexToHash(txhash),
-			BlockHash:   common.HexToHash(blockhash),
-			BlockNumber: height,
-			Index:       uint(index),
-		}
+func SelectLogsByHash(blockhash common.Hash, contractAddresses []common.Address) []*types.Log {
+	return SelectLogsByHashAndTopics(blockhash, contractAddresses, nil)
+}
 
-		json.Unmarshal(utility.StrToBytes(topic), &log.Topics)
-		result = append(result, &log)
-	}
+// SelectLogsByTopics selects logs in [from, to] of the contracts, matching the topics by position
+func SelectLogsByTopics(from, to uint64, contractAddresses []common.Address, topics [][]common.Hash) []*types.Log {
+	sql := "select height,logindex, blockhash,txhash,contractaddress,topic,data FROM contractlogs WHERE (height>=? and height<=?) "
+	return selectLogs(sql, []interface{}{from, to}, contractAddresses, topics)
+}
 
-	return result
+// SelectLogsByHashAndTopics selects logs in the block of the contracts, matching the topics by position
+func SelectLogsByHashAndTopics(blockhash common.Hash, contractAddresses []common.Address, topics [][]common.Hash) []*types.Log {
+	sql := "select height,logindex, blockhash,txhash,contractaddress,topic,data FROM contractlogs WHERE blockhash = ? "
+	return selectLogs(sql, []interface{}{blockhash.Hex()}, contractAddresses, topics)
 }
 
-func SelectLogsByHash(blockhash common.Hash, contractAddresses []common.Address) []*types.Log {
+func selectLogs(sql string, args []interface{}, contractAddresses []common.Address, topics [][]common.Hash) []*types.Log {
 	if nil == mysqlDBLog {
 		return nil
 	}
+	if len(topics) > len(topicColumns) {
+		return []*types.Log{}
+	}
 
-	sql := "select height,logindex, blockhash,txhash,contractaddress,topic,data FROM contractlogs WHERE blockhash = ? "
 	if 0 != len(contractAddresses) {
 		sql += "and( "
 		for _, contractAddress := range contractAddresses {
@@ -84,8 +61,19 @@ func SelectLogsByHash(blockhash common.Hash, contractAddresses []common.Address)
 		}
 		sql = sql[:len(sql)-2] + ")"
 	}
+	for i, sub := range topics {
+		// empty rule set is wildcard, but the log must have the topic
+		if 0 == len(sub) {
+			sql += " and " + topicColumns[i] + " != ''"
+			continue
+		}
+		sql += " and " + topicColumns[i] + " in (?" + strings.Repeat(",?", len(sub)-1) + ")"
+		for _, topic := range sub {
+			args = append(args, topic.String())
+		}
+	}
 
-	rows, err := mysqlDBLog.Query(sql, blockhash.Hex())
+	rows, err := mysqlDBLog.Query(sql, args...)
 	if err != nil {
 		return nil
 	}
@@ -111,6 +99,7 @@ func SelectLogsByHash(blockhash common.Hash, contractAddresses []common.Address)
 			BlockNumber: height,
 			Index:       uint(index),
 		}
+
 		json.Unmarshal(utility.StrToBytes(topic), &log.Topics)
 		result = append(result, &log)
 	}
@@ -61,4 +61,6 @@ const (
 	BlockHeaderNotify = "blockHeaderNotify"
 	//vm removed event notify
 	VMRemovedEventNotify = "vmRemovedEventNotify"
+	//new tx in pool notify
+	NewPendingTxNotify = "newPendingTxNotify"
 )
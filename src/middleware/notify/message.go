@@ -337,3 +337,15 @@ func (m *VMRemovedEventNotifyMessage) GetRaw() []byte {
 func (m *VMRemovedEventNotifyMessage) GetData() interface{} {
 	return m.Logs
 }
+
+type NewPendingTxNotifyMessage struct {
+	Transactions []*types.Transaction
+}
+
+func (m *NewPendingTxNotifyMessage) GetRaw() []byte {
+	// never use it
+	return nil
+}
+func (m *NewPendingTxNotifyMessage) GetData() interface{} {
+	return m.Transactions
+}
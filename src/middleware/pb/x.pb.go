@@ -304,6 +304,8 @@ type BlockHeader struct {
 	ProveRoot        []byte             `protobuf:"bytes,18,opt,name=ProveRoot" json:"ProveRoot,omitempty"`
 	EvictedTxs       *Hashes            `protobuf:"bytes,19,opt,name=EvictedTxs" json:"EvictedTxs,omitempty"`
 	RequestIds       []byte             `protobuf:"bytes,20,opt,name=RequestIds" json:"RequestIds,omitempty"`
+	GasLimit         *uint64            `protobuf:"varint,21,opt,name=GasLimit" json:"GasLimit,omitempty"`
+	GasUsed          *uint64            `protobuf:"varint,22,opt,name=GasUsed" json:"GasUsed,omitempty"`
 	XXX_unrecognized []byte             `json:"-"`
 }
 
@@ -452,6 +454,20 @@ func (m *BlockHeader) GetRequestIds() []byte {
 	return nil
 }
 
+func (m *BlockHeader) GetGasLimit() uint64 {
+	if m != nil && m.GasLimit != nil {
+		return *m.GasLimit
+	}
+	return 0
+}
+
+func (m *BlockHeader) GetGasUsed() uint64 {
+	if m != nil && m.GasUsed != nil {
+		return *m.GasUsed
+	}
+	return 0
+}
+
 type TransactionHash struct {
 	Hash             []byte `protobuf:"bytes,1,opt,name=hash" json:"hash,omitempty"`
 	SubHash          []byte `protobuf:"bytes,2,opt,name=subHash" json:"subHash,omitempty"`
@@ -1232,6 +1248,7 @@ type ConsensusVerifyMessage struct {
 	BlockHash        []byte    `protobuf:"bytes,1,req,name=BlockHash" json:"BlockHash,omitempty"`
 	RandomSign       []byte    `protobuf:"bytes,2,req,name=RandomSign" json:"RandomSign,omitempty"`
 	Sign             *SignData `protobuf:"bytes,3,req,name=Sign" json:"Sign,omitempty"`
+	Vote             []byte    `protobuf:"bytes,4,opt,name=Vote" json:"Vote,omitempty"`
 	XXX_unrecognized []byte    `json:"-"`
 }
 
@@ -1261,6 +1278,13 @@ func (m *ConsensusVerifyMessage) GetSign() *SignData {
 	return nil
 }
 
+func (m *ConsensusVerifyMessage) GetVote() []byte {
+	if m != nil {
+		return m.Vote
+	}
+	return nil
+}
+
 type ConsensusBlockMessage struct {
 	Block            *Block `protobuf:"bytes,1,req,name=Block" json:"Block,omitempty"`
 	XXX_unrecognized []byte `json:"-"`
@@ -1835,103 +1859,105 @@ func init() {
 func init() { proto.RegisterFile("x.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1562 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0x4f, 0x6f, 0xdb, 0x56,
-	0x12, 0x07, 0x29, 0xea, 0xdf, 0x88, 0xb6, 0x6c, 0xda, 0x89, 0xe9, 0x04, 0x9b, 0x15, 0xb8, 0xc9,
-	0xc2, 0xd9, 0xdd, 0x18, 0x81, 0x91, 0xcb, 0x16, 0xbd, 0xc4, 0xb2, 0x93, 0x18, 0x89, 0x13, 0x55,
-	0x16, 0xdc, 0x43, 0x4f, 0x4f, 0xd4, 0x44, 0x26, 0x6c, 0x91, 0x32, 0x1f, 0x99, 0xd8, 0x87, 0x02,
-	0xbd, 0x35, 0x3d, 0x17, 0xe8, 0xad, 0xed, 0xa5, 0x97, 0x7e, 0x8b, 0x7e, 0xb4, 0xe2, 0xcd, 0x7b,
-	0x8f, 0xa4, 0x64, 0xd9, 0x92, 0x91, 0x9c, 0x04, 0x3e, 0xce, 0x9b, 0xf9, 0xcd, 0xcc, 0x6f, 0xfe,
-	0x50, 0x50, 0xbd, 0xd8, 0x1e, 0xc7, 0x51, 0x12, 0x39, 0x4b, 0xa3, 0x60, 0x30, 0x38, 0xc3, 0x8f,
-	0x2c, 0xc6, 0xed, 0x71, 0xdf, 0x7b, 0x04, 0xd5, 0x43, 0xe4, 0x9c, 0x0d, 0xd1, 0xb1, 0xc1, 0x6a,
-	0x47, 0x03, 0x74, 0x8d, 0x96, 0xb1, 0xb5, 0x24, 0x9e, 0x76, 0xa3, 0xc1, 0xa5, 0x6b, 0xb6, 0x8c,
-	0x2d, 0xdb, 0xfb, 0xd9, 0x84, 0x46, 0x2f, 0x66, 0x21, 0x67, 0x7e, 0x12, 0x44, 0xa1, 0x78, 0xbb,
-	0xc7, 0x12, 0x46, 0xb2, 0x75, 0x67, 0x09, 0xca, 0x6f, 0xa3, 0xd0, 0x47, 0x12, 0xb6, 0x9c, 0x65,
-	0xa8, 0x1c, 0x45, 0x69, 0xec, 0xa3, 0x5b, 0x12, 0x97, 0xc5, 0x73, 0x8f, 0xc5, 0x43, 0x4c, 0x5c,
-	0x8b, 0xc4, 0x6d, 0xb0, 0x7a, 0x97, 0x63, 0x74, 0xcb, 0x2d, 0x73, 0xab, 0x2c, 0x9e, 0x5e, 0x31,
-	0x7e, 0xe2, 0x56, 0x48, 0x76, 0x15, 0xea, 0xfb, 0x17, 0x49, 0xcc, 0x48, 0x7b, 0x95, 0x8e, 0xee,
-	0xc0, 0x52, 0x76, 0x44, 0xf7, 0x6a, 0x2d, 0x43, 0xde, 0x3b, 0x0a, 0x86, 0xa1, 0x5b, 0x27, 0x21,
-	0xa1, 0x33, 0x18, 0xa1, 0x0b, 0x64, 0x61, 0x15, 0xea, 0x5d, 0x3c, 0x4f, 0x91, 0x27, 0x07, 0x03,
-	0xb7, 0x41, 0xa0, 0x36, 0xa0, 0x79, 0x14, 0xf9, 0xa7, 0x98, 0xe4, 0x2f, 0x6c, 0x92, 0x15, 0x2f,
-	0xd2, 0x7e, 0xc1, 0x39, 0xee, 0x2e, 0x91, 0xca, 0x26, 0x54, 0x8f, 0xd2, 0x3e, 0x61, 0x5b, 0xd6,
-	0x07, 0xed, 0x13, 0x16, 0x84, 0x07, 0x03, 0xb7, 0x29, 0xae, 0x7a, 0xbf, 0x18, 0xb0, 0x59, 0xb8,
-	0xa8, 0x34, 0xeb, 0x78, 0xfe, 0x1f, 0x56, 0x0b, 0x2f, 0x85, 0x1e, 0xe4, 0xae, 0xd1, 0x2a, 0x6d,
-	0x35, 0x76, 0x1e, 0x6c, 0x4f, 0x64, 0x61, 0x7b, 0x4a, 0xce, 0x71, 0x61, 0xa5, 0x9d, 0xc6, 0x31,
-	0x86, 0xc9, 0xee, 0x59, 0xe4, 0x9f, 0x12, 0x06, 0xb3, 0x65, 0x6e, 0xd9, 0xce, 0x1a, 0x34, 0xe4,
-	0x11, 0x06, 0xc3, 0x93, 0xc4, 0x2d, 0xb5, 0xcc, 0x2d, 0x4b, 0x00, 0xa3, 0xc3, 0xce, 0x07, 0xd7,
-	0x12, 0x52, 0xde, 0x1e, 0xac, 0x14, 0x54, 0x1e, 0x9d, 0x05, 0x3e, 0x3a, 0x4f, 0xc1, 0x4e, 0x8a,
-	0x4e, 0x4a, 0x24, 0xf7, 0xae, 0x47, 0xe2, 0xfd, 0x59, 0xca, 0x8c, 0xb1, 0x01, 0xc6, 0x59, 0xa6,
-	0x0c, 0x9d, 0x55, 0x05, 0x42, 0x66, 0xbd, 0x09, 0xd5, 0x4e, 0x8c, 0x24, 0x50, 0xd2, 0xe1, 0xea,
-	0xc4, 0x48, 0x59, 0xb1, 0xe8, 0xc0, 0x01, 0xe8, 0xc4, 0xd1, 0x07, 0x3c, 0x66, 0x67, 0xa9, 0xc8,
-	0xbe, 0x12, 0xea, 0x45, 0x09, 0x3b, 0xfb, 0xe6, 0x2d, 0x11, 0x80, 0xd4, 0xb4, 0xd3, 0x98, 0x6e,
-	0x55, 0xb5, 0x9d, 0x36, 0xe3, 0x49, 0x14, 0x53, 0xde, 0xe9, 0xc6, 0xcb, 0x38, 0x4a, 0xc7, 0x07,
-	0x03, 0x95, 0xfa, 0x55, 0xa8, 0x0b, 0x22, 0xb0, 0x24, 0x8d, 0x65, 0xfe, 0xed, 0x9c, 0x90, 0x32,
-	0xf7, 0xcf, 0xa6, 0x5c, 0xb7, 0x17, 0x4a, 0x82, 0xa0, 0xed, 0x45, 0x2f, 0x46, 0x54, 0x7c, 0x58,
-	0x83, 0x46, 0x17, 0x7d, 0x0c, 0xc6, 0x09, 0x1d, 0x2e, 0x67, 0xc6, 0x13, 0x96, 0x20, 0x1d, 0x35,
-	0xaf, 0x52, 0x78, 0x45, 0xfb, 0xd0, 0x65, 0xe1, 0x20, 0x1a, 0xb9, 0xab, 0x5a, 0x84, 0x22, 0xd1,
-	0x8d, 0xa2, 0xc4, 0x75, 0xe8, 0xe8, 0x31, 0xc0, 0xfe, 0x87, 0xc0, 0x4f, 0x70, 0xd0, 0xbb, 0xe0,
-	0xee, 0x5a, 0xcb, 0xd8, 0x6a, 0xec, 0xdc, 0x99, 0x42, 0x28, 0x39, 0x24, 0xe2, 0x98, 0x91, 0x98,
-	0xbb, 0xeb, 0x54, 0xa0, 0x4f, 0xa1, 0x39, 0x8d, 0xdf, 0x06, 0xeb, 0x24, 0x4f, 0x57, 0x13, 0xaa,
-	0x5c, 0xb1, 0x59, 0x96, 0x34, 0x42, 0x99, 0x92, 0xeb, 0xfc, 0x47, 0x24, 0x52, 0x24, 0xd8, 0x35,
-	0x5a, 0xe6, 0x0c, 0x4a, 0x14, 0x29, 0x30, 0x4d, 0x22, 0x73, 0x2e, 0x89, 0x1e, 0x42, 0xe5, 0x10,
-	0x47, 0x7d, 0x8c, 0x1d, 0x00, 0xf3, 0x60, 0x40, 0x36, 0x28, 0x20, 0x9d, 0xb4, 0xff, 0x1a, 0x2f,
-	0x25, 0xad, 0xbd, 0x3f, 0x0c, 0x68, 0x50, 0x56, 0xaf, 0xa3, 0x5a, 0x87, 0x89, 0x6a, 0x90, 0xd0,
-	0x9d, 0x15, 0xa8, 0x75, 0x62, 0x24, 0x79, 0xc5, 0xb5, 0x0d, 0x68, 0xb6, 0x63, 0x64, 0x09, 0xe6,
-	0xf5, 0x62, 0xe9, 0x48, 0xef, 0xe2, 0x30, 0x08, 0x89, 0x50, 0x65, 0x4d, 0x43, 0x89, 0x88, 0xa2,
-	0x5f, 0x21, 0x3c, 0xeb, 0x60, 0xcb, 0xfb, 0x8a, 0xd2, 0x55, 0x5d, 0x57, 0xfb, 0x17, 0x09, 0x86,
-	0x03, 0x4e, 0xdc, 0xab, 0x7b, 0x9f, 0x0c, 0x28, 0x93, 0xd9, 0xb9, 0x41, 0x2b, 0x3a, 0x23, 0x1d,
-	0x37, 0x33, 0x57, 0xa4, 0xe3, 0xa5, 0xab, 0xe4, 0xb5, 0x74, 0xa6, 0x24, 0x3e, 0xee, 0x96, 0x5b,
-	0x25, 0x49, 0x3c, 0xa5, 0x8e, 0xb0, 0x51, 0x9d, 0x78, 0xfb, 0x50, 0x97, 0x65, 0x10, 0xbe, 0x8f,
-	0x9c, 0x87, 0x50, 0xa1, 0x07, 0x5d, 0xd5, 0xeb, 0xb3, 0xd0, 0x08, 0xc7, 0x0f, 0x78, 0x2f, 0x1a,
-	0xcb, 0xc0, 0x89, 0xc0, 0xd7, 0xbc, 0x1d, 0x00, 0x7a, 0x94, 0x3d, 0x62, 0x21, 0x3d, 0x5e, 0x0b,
-	0x6c, 0x55, 0x81, 0xf2, 0xd6, 0x0a, 0xd4, 0xd4, 0xb3, 0xbc, 0x67, 0x7b, 0x0c, 0x56, 0xe8, 0x44,
-	0xd3, 0x54, 0x60, 0xdc, 0x84, 0x55, 0xd5, 0xd3, 0x34, 0x84, 0x8c, 0x0d, 0x3b, 0xa2, 0xc3, 0x07,
-	0x3c, 0xc9, 0xb4, 0x98, 0x44, 0xff, 0xfb, 0xb3, 0xac, 0x2b, 0xa3, 0x9e, 0x0b, 0x15, 0x55, 0x0e,
-	0xcb, 0x50, 0x39, 0xc9, 0x9b, 0xab, 0xed, 0x1d, 0x43, 0x4d, 0x84, 0x54, 0x94, 0x9f, 0x80, 0x26,
-	0x7e, 0x15, 0x97, 0x4c, 0xc9, 0x1d, 0x71, 0x42, 0xa3, 0x43, 0xb6, 0x54, 0x07, 0x40, 0x3c, 0xc9,
-	0x98, 0x53, 0x47, 0x15, 0x67, 0xd5, 0x63, 0x8c, 0x79, 0x10, 0x85, 0x94, 0x94, 0xf2, 0x57, 0xc6,
-	0x53, 0xef, 0xbf, 0x00, 0x32, 0x75, 0xe4, 0x8e, 0x48, 0xea, 0x9e, 0xd2, 0x29, 0xca, 0x39, 0xed,
-	0x9f, 0x05, 0x7e, 0x4e, 0x68, 0x84, 0xbb, 0xed, 0x28, 0xe4, 0x18, 0xf2, 0x94, 0xab, 0x3c, 0x05,
-	0x32, 0x0e, 0xb7, 0x61, 0xce, 0x04, 0x3b, 0x24, 0x5a, 0x1b, 0xac, 0x43, 0x1c, 0x71, 0xb7, 0x44,
-	0xbe, 0x7e, 0x04, 0x77, 0xd2, 0x4c, 0x97, 0x7d, 0xd4, 0xf3, 0xe7, 0x19, 0x94, 0x5f, 0x0a, 0x8b,
-	0xca, 0xce, 0xa3, 0x29, 0x3b, 0xd7, 0xc0, 0x7b, 0x04, 0x16, 0x17, 0xb1, 0x29, 0xd1, 0xa5, 0x8d,
-	0xa9, 0x4b, 0x3a, 0xb0, 0xde, 0xff, 0x00, 0x8e, 0x4e, 0x58, 0x8c, 0x9d, 0x00, 0x7d, 0xa4, 0x89,
-	0x8f, 0xfe, 0x29, 0x5e, 0x4e, 0x94, 0xf7, 0x69, 0x16, 0x8d, 0xdf, 0x0d, 0xb8, 0x97, 0xd9, 0xcb,
-	0xef, 0x69, 0xa4, 0x4b, 0x50, 0x7e, 0x59, 0x48, 0x91, 0x58, 0x2e, 0x90, 0x27, 0xca, 0xe1, 0x27,
-	0x45, 0x4b, 0x0a, 0xd6, 0xe6, 0x34, 0xac, 0x09, 0x28, 0x87, 0x38, 0x6a, 0x87, 0x09, 0x8d, 0xc2,
-	0xb2, 0xf0, 0x87, 0x72, 0x5d, 0xbe, 0xd9, 0x9f, 0x1f, 0x27, 0x10, 0x06, 0xc3, 0x50, 0xa6, 0xfa,
-	0x1a, 0x84, 0xd9, 0x0c, 0xda, 0x53, 0x20, 0x45, 0x00, 0xc4, 0xa5, 0xd7, 0x8a, 0x3f, 0xd3, 0x28,
-	0x1e, 0xe7, 0x9c, 0x9c, 0x87, 0xe4, 0x3b, 0xf8, 0xc7, 0x34, 0x90, 0x53, 0xbc, 0xec, 0xe2, 0xb9,
-	0xc6, 0x52, 0x30, 0x2e, 0xd1, 0x14, 0x95, 0x9b, 0x37, 0x2b, 0xdf, 0x07, 0x47, 0x8c, 0xab, 0xc0,
-	0x97, 0x45, 0x9f, 0x8e, 0x46, 0x2c, 0xbe, 0xbc, 0xaa, 0x51, 0x1f, 0x74, 0x5e, 0x2b, 0xff, 0x32,
-	0xff, 0xc9, 0x3d, 0xef, 0x2f, 0x03, 0xee, 0x5f, 0xe5, 0x0f, 0x0e, 0x16, 0x0d, 0x57, 0x41, 0x7f,
-	0x69, 0x66, 0xff, 0xb5, 0xa8, 0xff, 0x8a, 0x85, 0x81, 0xfa, 0x7e, 0x96, 0x41, 0xdd, 0x1d, 0x0f,
-	0x19, 0x3f, 0x55, 0xad, 0x3b, 0x0f, 0x75, 0x75, 0x22, 0xe1, 0xb5, 0x9b, 0x23, 0xf1, 0xc9, 0x80,
-	0x8d, 0xcc, 0x05, 0xd5, 0x98, 0x66, 0x46, 0x38, 0x5f, 0x65, 0x0a, 0x6b, 0x58, 0x61, 0xb7, 0x29,
-	0xcd, 0xda, 0xcb, 0x24, 0x7e, 0x0d, 0xa5, 0x72, 0x33, 0x94, 0x9f, 0x0c, 0x58, 0xcf, 0xa1, 0xb0,
-	0x7c, 0x83, 0xfc, 0x37, 0x98, 0xbb, 0x27, 0x0b, 0x4c, 0xe5, 0x89, 0xf8, 0x0a, 0xbc, 0xda, 0xf0,
-	0xcd, 0x45, 0x9c, 0xad, 0x21, 0x6a, 0x5e, 0x8a, 0x86, 0xf2, 0xbe, 0xd0, 0xb7, 0x8e, 0x31, 0x0e,
-	0xde, 0x67, 0x25, 0x20, 0x26, 0x69, 0x36, 0x5c, 0x0d, 0xdd, 0x39, 0xe5, 0x5a, 0x53, 0xe8, 0xa6,
-	0x8b, 0x99, 0xf6, 0xbe, 0x86, 0x3b, 0x99, 0x1d, 0x52, 0xab, 0xcd, 0xfc, 0x4b, 0xad, 0x25, 0xca,
-	0xed, 0xf5, 0x59, 0x6e, 0x7b, 0xdf, 0xc3, 0x83, 0x3c, 0x60, 0xc4, 0x9b, 0x2f, 0xdb, 0xfc, 0xe6,
-	0x54, 0xd1, 0xb7, 0xf0, 0xcf, 0x59, 0xe6, 0xe5, 0x14, 0x99, 0x59, 0x01, 0x0b, 0x2a, 0xfe, 0xcd,
-	0x80, 0xba, 0xfc, 0xc4, 0x10, 0x68, 0xd6, 0xc1, 0xee, 0x45, 0xe3, 0xe9, 0xa0, 0x67, 0x1b, 0xb3,
-	0xd4, 0x66, 0x39, 0x77, 0x61, 0x39, 0x13, 0x9b, 0xfa, 0x2a, 0xd0, 0xa4, 0xa5, 0xaf, 0x02, 0x25,
-	0x58, 0x5c, 0x25, 0xca, 0x24, 0xa8, 0xfa, 0x07, 0x85, 0x6b, 0x0e, 0x55, 0xdf, 0x81, 0x43, 0x86,
-	0x08, 0x24, 0x35, 0xdc, 0x2e, 0x9e, 0x17, 0x3e, 0x05, 0x8c, 0x2b, 0x0a, 0xe7, 0x78, 0xfc, 0xab,
-	0x01, 0xcd, 0x29, 0x8d, 0x62, 0xc9, 0x2c, 0xb0, 0xfb, 0xba, 0x2f, 0x95, 0x62, 0x01, 0x3c, 0x81,
-	0x7a, 0x2f, 0x52, 0x43, 0x53, 0x59, 0xbc, 0x49, 0xbc, 0x88, 0xaf, 0x34, 0xaf, 0x61, 0xd6, 0xe8,
-	0xe6, 0x67, 0xba, 0x19, 0xc3, 0x8a, 0x64, 0x39, 0x1f, 0x76, 0x91, 0x8f, 0x05, 0x79, 0x84, 0xba,
-	0x03, 0xfe, 0x86, 0x71, 0xa9, 0xae, 0x96, 0x33, 0x5f, 0x6e, 0x3f, 0x33, 0x99, 0x7f, 0x1b, 0xe8,
-	0xef, 0xc0, 0xa1, 0x5c, 0x7f, 0xb1, 0x5c, 0xf5, 0xa1, 0x39, 0xa5, 0x70, 0xc1, 0xc5, 0xf3, 0x16,
-	0x36, 0xf6, 0xd5, 0x2e, 0xf9, 0xf9, 0xf1, 0x26, 0x35, 0x73, 0xe2, 0xad, 0x37, 0x61, 0x63, 0x21,
-	0xe8, 0x73, 0xe2, 0x7d, 0x01, 0x77, 0x0b, 0xcd, 0xa0, 0x13, 0x84, 0x43, 0xdd, 0x0c, 0xd6, 0xa0,
-	0xf1, 0x22, 0x8e, 0x46, 0x93, 0x33, 0x56, 0xec, 0x48, 0x41, 0x38, 0x54, 0x33, 0xb1, 0x2e, 0x9a,
-	0xe9, 0x2e, 0xe3, 0x38, 0x51, 0xc2, 0xba, 0x99, 0x5a, 0xf3, 0x56, 0x86, 0x09, 0xcb, 0x51, 0x6e,
-	0x39, 0x37, 0x62, 0x90, 0x11, 0x00, 0xb3, 0xc7, 0x6f, 0xd7, 0xa9, 0xdf, 0xc0, 0x7a, 0x17, 0xcf,
-	0xe7, 0x2e, 0x6d, 0x5a, 0xdb, 0x9c, 0xc4, 0xfc, 0x60, 0xc0, 0xa6, 0xce, 0xc8, 0x5c, 0x9d, 0x93,
-	0xab, 0x9f, 0x39, 0x6f, 0xf5, 0x5b, 0xd0, 0xa1, 0x57, 0x00, 0x47, 0x69, 0xff, 0xb9, 0xef, 0x47,
-	0x69, 0x98, 0xd0, 0x7f, 0x27, 0xec, 0x8c, 0x85, 0x3e, 0xaa, 0x59, 0x7f, 0xf5, 0xcf, 0xac, 0xe7,
-	0x9c, 0x63, 0xc2, 0xd5, 0x07, 0x1b, 0x80, 0xf9, 0x42, 0xfe, 0x91, 0x65, 0xff, 0x1d, 0x00, 0x00,
-	0xff, 0xff, 0xaa, 0xb7, 0x30, 0x53, 0x55, 0x13, 0x00, 0x00,
+	// 1591 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0x4d, 0x6f, 0xdb, 0x46,
+	0x13, 0x06, 0x29, 0xea, 0x6b, 0x44, 0x5b, 0x36, 0xfd, 0xb5, 0x4e, 0xf0, 0xe6, 0x15, 0xf8, 0x26,
+	0x2f, 0x94, 0xb6, 0x31, 0x02, 0x23, 0x97, 0x16, 0xbd, 0xc4, 0xb2, 0xe3, 0x18, 0x89, 0x13, 0x55,
+	0x56, 0xdd, 0x43, 0x4f, 0x2b, 0x69, 0x23, 0x13, 0xb6, 0x48, 0x99, 0x4b, 0x3a, 0x76, 0x81, 0x02,
+	0xbd, 0x35, 0x3d, 0x17, 0xe8, 0xad, 0xed, 0xa5, 0x3f, 0xa4, 0x3f, 0xa0, 0x3f, 0xaa, 0xd8, 0xd9,
+	0x5d, 0x92, 0xa2, 0x65, 0x4b, 0x46, 0x72, 0x12, 0xb8, 0x9a, 0x8f, 0x67, 0x66, 0x9e, 0x99, 0x1d,
+	0x12, 0xca, 0x97, 0x5b, 0xe3, 0x30, 0x88, 0x02, 0x67, 0x61, 0xe4, 0x0d, 0x06, 0x67, 0xec, 0x3d,
+	0x0d, 0xd9, 0xd6, 0xb8, 0xe7, 0x3e, 0x82, 0xf2, 0x21, 0xe3, 0x9c, 0x0e, 0x99, 0x63, 0x83, 0xd5,
+	0x0a, 0x06, 0x8c, 0x18, 0x0d, 0xa3, 0xb9, 0x20, 0x9e, 0x76, 0x82, 0xc1, 0x15, 0x31, 0x1b, 0x46,
+	0xd3, 0x76, 0x7f, 0x35, 0xa1, 0xd6, 0x0d, 0xa9, 0xcf, 0x69, 0x3f, 0xf2, 0x02, 0x5f, 0xfc, 0xbb,
+	0x4b, 0x23, 0x8a, 0xb2, 0x55, 0x67, 0x01, 0x8a, 0x6f, 0x02, 0xbf, 0xcf, 0x50, 0xd8, 0x72, 0x16,
+	0xa1, 0x74, 0x14, 0xc4, 0x61, 0x9f, 0x91, 0x82, 0x50, 0x16, 0xcf, 0x5d, 0x1a, 0x0e, 0x59, 0x44,
+	0x2c, 0x14, 0xb7, 0xc1, 0xea, 0x5e, 0x8d, 0x19, 0x29, 0x36, 0xcc, 0x66, 0x51, 0x3c, 0xbd, 0xa4,
+	0xfc, 0x84, 0x94, 0x50, 0x76, 0x19, 0xaa, 0x7b, 0x97, 0x51, 0x48, 0xd1, 0x7a, 0x19, 0x8f, 0xd6,
+	0x60, 0x21, 0x39, 0x42, 0xbd, 0x4a, 0xc3, 0x90, 0x7a, 0x47, 0xde, 0xd0, 0x27, 0x55, 0x14, 0x12,
+	0x36, 0xbd, 0x11, 0x23, 0x80, 0x1e, 0x96, 0xa1, 0xda, 0x61, 0xe7, 0x31, 0xe3, 0xd1, 0xc1, 0x80,
+	0xd4, 0x10, 0xd4, 0x06, 0xd4, 0x8f, 0x82, 0xfe, 0x29, 0x8b, 0xd2, 0x3f, 0x6c, 0x94, 0x15, 0x7f,
+	0xc4, 0xbd, 0x4c, 0x70, 0x9c, 0x2c, 0xa0, 0xc9, 0x3a, 0x94, 0x8f, 0xe2, 0x1e, 0x62, 0x5b, 0xd4,
+	0x07, 0xad, 0x13, 0xea, 0xf9, 0x07, 0x03, 0x52, 0x17, 0xaa, 0xee, 0x6f, 0x06, 0x6c, 0x66, 0x14,
+	0x95, 0x65, 0x9d, 0xcf, 0x2f, 0x61, 0x39, 0xf3, 0xa7, 0xb0, 0xc3, 0x38, 0x31, 0x1a, 0x85, 0x66,
+	0x6d, 0xfb, 0xc1, 0xd6, 0x44, 0x15, 0xb6, 0x72, 0x72, 0x0e, 0x81, 0xa5, 0x56, 0x1c, 0x86, 0xcc,
+	0x8f, 0x76, 0xce, 0x82, 0xfe, 0x29, 0x62, 0x30, 0x1b, 0x66, 0xd3, 0x76, 0x56, 0xa0, 0x26, 0x8f,
+	0x98, 0x37, 0x3c, 0x89, 0x48, 0xa1, 0x61, 0x36, 0x2d, 0x01, 0x0c, 0x0f, 0xdb, 0x17, 0xc4, 0x12,
+	0x52, 0xee, 0x2e, 0x2c, 0x65, 0x4c, 0x1e, 0x9d, 0x79, 0x7d, 0xe6, 0x3c, 0x05, 0x3b, 0xca, 0x06,
+	0x29, 0x91, 0xdc, 0xbb, 0x19, 0x89, 0xfb, 0x4f, 0x21, 0x71, 0x46, 0x07, 0x2c, 0x4c, 0x2a, 0x65,
+	0xe8, 0xaa, 0x2a, 0x10, 0xb2, 0xea, 0x75, 0x28, 0xb7, 0x43, 0x86, 0x02, 0x05, 0x9d, 0xae, 0x76,
+	0xc8, 0xb0, 0x2a, 0x16, 0x1e, 0x38, 0x00, 0xed, 0x30, 0xb8, 0x60, 0xc7, 0xf4, 0x2c, 0x16, 0xd5,
+	0x57, 0x42, 0xdd, 0x20, 0xa2, 0x67, 0xdf, 0xbc, 0x21, 0x25, 0x6d, 0xa6, 0x15, 0x87, 0xa8, 0x55,
+	0xd6, 0x7e, 0x5a, 0x94, 0x47, 0x41, 0x48, 0x2a, 0x5a, 0x63, 0x3f, 0x0c, 0xe2, 0xf1, 0xc1, 0x40,
+	0x95, 0x7e, 0x19, 0xaa, 0x82, 0x08, 0x34, 0x8a, 0x43, 0x59, 0x7f, 0x3b, 0x25, 0xa4, 0xac, 0xfd,
+	0xb3, 0x5c, 0xe8, 0xf6, 0x5c, 0x45, 0x10, 0xb4, 0xbd, 0xec, 0x86, 0x8c, 0x29, 0x3e, 0xac, 0x40,
+	0xad, 0xc3, 0xfa, 0xcc, 0x1b, 0x47, 0x78, 0xb8, 0x98, 0x38, 0x8f, 0x68, 0xc4, 0xf0, 0xa8, 0x7e,
+	0x9d, 0xc2, 0x4b, 0x3a, 0x86, 0x0e, 0xf5, 0x07, 0xc1, 0x88, 0x2c, 0x6b, 0x11, 0xcc, 0x44, 0x27,
+	0x08, 0x22, 0xe2, 0xe0, 0xd1, 0x63, 0x80, 0xbd, 0x0b, 0xaf, 0x1f, 0xb1, 0x41, 0xf7, 0x92, 0x93,
+	0x95, 0x86, 0xd1, 0xac, 0x6d, 0xaf, 0xe5, 0x10, 0x4a, 0x0e, 0x89, 0x3c, 0x26, 0x24, 0xe6, 0x64,
+	0x15, 0xd5, 0x97, 0xa0, 0xb2, 0x4f, 0xf9, 0x6b, 0x6f, 0xe4, 0x45, 0x64, 0x4d, 0x27, 0x72, 0x9f,
+	0xf2, 0x6f, 0x39, 0x1b, 0x90, 0x75, 0x71, 0xe0, 0x3e, 0x85, 0x7a, 0x3e, 0x44, 0x1b, 0xac, 0x93,
+	0xb4, 0xa2, 0x75, 0x28, 0x73, 0x45, 0x78, 0xd9, 0xf5, 0x0c, 0x8a, 0x58, 0x7f, 0xe7, 0x33, 0x28,
+	0x49, 0x0e, 0x10, 0xa3, 0x61, 0x4e, 0x61, 0x4d, 0x96, 0x25, 0x79, 0x9e, 0x99, 0x33, 0x79, 0xf6,
+	0x10, 0x4a, 0x87, 0x6c, 0xd4, 0x63, 0xa1, 0x03, 0x60, 0x1e, 0x0c, 0xd0, 0x07, 0xe6, 0xac, 0x1d,
+	0xf7, 0x5e, 0xb1, 0x2b, 0xc9, 0x7c, 0xf7, 0x2f, 0x03, 0x6a, 0x58, 0xf8, 0x9b, 0xd8, 0xd8, 0xa6,
+	0xa2, 0x61, 0x88, 0xa9, 0xf3, 0xd1, 0x0e, 0x19, 0xca, 0x2b, 0x3a, 0x6e, 0x40, 0xbd, 0x15, 0x32,
+	0x1a, 0xb1, 0xb4, 0xa5, 0x2c, 0x5d, 0x8c, 0x1d, 0x36, 0xf4, 0x7c, 0xe4, 0x5c, 0x51, 0x33, 0x55,
+	0x22, 0xc2, 0x02, 0x95, 0x10, 0xcf, 0x2a, 0xd8, 0x52, 0x5f, 0xb1, 0xbe, 0xac, 0x5b, 0x6f, 0xef,
+	0x32, 0x62, 0xfe, 0x80, 0x23, 0x3d, 0xab, 0xee, 0x07, 0x03, 0x8a, 0xe8, 0x76, 0x66, 0xd2, 0xb2,
+	0xc1, 0xc8, 0xc0, 0xcd, 0x86, 0x31, 0x11, 0x78, 0xe1, 0x3a, 0xbf, 0x2d, 0x5d, 0x29, 0x89, 0x8f,
+	0x93, 0x62, 0xa3, 0x20, 0xb9, 0xa9, 0xcc, 0x21, 0x36, 0x6c, 0x25, 0x77, 0x0f, 0xaa, 0xb2, 0x53,
+	0xfc, 0x77, 0x81, 0xf3, 0x10, 0x4a, 0xf8, 0xa0, 0x1b, 0x7f, 0x75, 0x1a, 0x1a, 0x11, 0xf8, 0x01,
+	0xef, 0x06, 0x63, 0x7c, 0xc2, 0xc4, 0x57, 0xdc, 0x6d, 0x00, 0x7c, 0x94, 0x63, 0x64, 0x2e, 0x3b,
+	0x6e, 0x03, 0x6c, 0xd5, 0xa4, 0x52, 0x4b, 0xd0, 0x53, 0x3e, 0x4b, 0x3d, 0xdb, 0xa5, 0xb0, 0x84,
+	0x27, 0x9a, 0xc9, 0x02, 0xe3, 0x26, 0x2c, 0xab, 0xb1, 0xa7, 0x21, 0x24, 0x6c, 0xd8, 0x16, 0x97,
+	0x80, 0xc7, 0xa3, 0xc4, 0x8a, 0x89, 0x1d, 0x72, 0x7f, 0x9a, 0x77, 0xe5, 0xd4, 0x25, 0x50, 0x52,
+	0x1d, 0xb3, 0x08, 0xa5, 0x93, 0x74, 0xfe, 0xda, 0xee, 0x31, 0x54, 0x44, 0x4a, 0x45, 0x87, 0x0a,
+	0x68, 0xe2, 0x57, 0x71, 0xc9, 0x6c, 0xda, 0xfa, 0x44, 0x48, 0xa8, 0xa9, 0xeb, 0x00, 0x88, 0x27,
+	0x99, 0x73, 0x52, 0x50, 0x67, 0xe5, 0x63, 0x16, 0x72, 0x2f, 0xf0, 0xb1, 0x28, 0xc5, 0xaf, 0x8c,
+	0xa7, 0xee, 0xe7, 0x00, 0xb2, 0x74, 0x18, 0x8e, 0x28, 0xea, 0xae, 0xb2, 0x29, 0x3a, 0x3e, 0xee,
+	0x9d, 0x79, 0xfd, 0x94, 0xd0, 0x0c, 0xd6, 0x5b, 0x81, 0xcf, 0x99, 0xcf, 0x63, 0xae, 0xea, 0xe4,
+	0xc9, 0x3c, 0xdc, 0x85, 0x39, 0x13, 0xec, 0x90, 0x68, 0x6d, 0xb0, 0x0e, 0xd9, 0x88, 0x93, 0x02,
+	0xc6, 0xfa, 0x1e, 0xc8, 0xa4, 0x9b, 0x0e, 0x7d, 0xaf, 0xaf, 0xa8, 0x67, 0x50, 0xdc, 0x17, 0x1e,
+	0x95, 0x9f, 0x47, 0x39, 0x3f, 0x37, 0xc0, 0x7b, 0x04, 0x16, 0x17, 0xb9, 0x29, 0xa0, 0xd2, 0x46,
+	0x4e, 0x49, 0x27, 0xd6, 0xfd, 0x02, 0xe0, 0xe8, 0x84, 0x86, 0xac, 0xed, 0xb1, 0x3e, 0xc3, 0xa5,
+	0x80, 0xf5, 0x4f, 0xd9, 0xd5, 0x44, 0x7b, 0x9f, 0x26, 0xd9, 0xf8, 0xd3, 0x80, 0x7b, 0x89, 0xbf,
+	0x54, 0x4f, 0x23, 0x5d, 0x80, 0xe2, 0x7e, 0xa6, 0x44, 0x62, 0xff, 0x60, 0x3c, 0x52, 0x01, 0x3f,
+	0xc9, 0x7a, 0x52, 0xb0, 0x36, 0xf3, 0xb0, 0x26, 0xa0, 0x1c, 0xb2, 0x51, 0xcb, 0x8f, 0xf0, 0xb6,
+	0x2c, 0x8a, 0x78, 0xb0, 0xd6, 0xc5, 0xdb, 0xe3, 0xf9, 0x79, 0x02, 0xa1, 0x37, 0xf4, 0x65, 0xa9,
+	0x6f, 0x40, 0x98, 0x5c, 0x53, 0xbb, 0x0a, 0xa4, 0x48, 0x80, 0x50, 0x7a, 0xa5, 0xf8, 0x93, 0x47,
+	0xf1, 0x38, 0xe5, 0xe4, 0x2c, 0x24, 0xdf, 0xc3, 0x7f, 0xf2, 0x40, 0x4e, 0xd9, 0x55, 0x87, 0x9d,
+	0x6b, 0x2c, 0x19, 0xe7, 0x12, 0x4d, 0xd6, 0xb8, 0x79, 0xbb, 0xf1, 0x3d, 0x70, 0xc4, 0x8d, 0xe6,
+	0xf5, 0x65, 0xd3, 0xc7, 0xa3, 0x11, 0x0d, 0xaf, 0xae, 0x5b, 0xd4, 0x07, 0xed, 0x57, 0x2a, 0xbe,
+	0x24, 0x7e, 0x0c, 0xcf, 0xfd, 0xdb, 0x80, 0xfb, 0xd7, 0xf9, 0xc3, 0x06, 0xf3, 0xa6, 0x2b, 0x63,
+	0xbf, 0x30, 0x75, 0xfe, 0x5a, 0x38, 0x7f, 0xc5, 0x4e, 0x81, 0x73, 0x3f, 0xa9, 0xa0, 0x9e, 0x8e,
+	0x87, 0x94, 0x9f, 0x92, 0x52, 0x2e, 0xd5, 0xe5, 0x89, 0x82, 0x57, 0x6e, 0xcf, 0xc4, 0x07, 0x03,
+	0x36, 0x92, 0x10, 0xd4, 0x60, 0x9a, 0x9a, 0xe1, 0x74, 0xdb, 0xc9, 0x6c, 0x6a, 0x99, 0xf5, 0xa7,
+	0x30, 0x6d, 0x75, 0x93, 0xf8, 0x35, 0x94, 0xd2, 0xed, 0x50, 0x7e, 0x31, 0x60, 0x35, 0x85, 0x42,
+	0xd3, 0x25, 0xf3, 0xff, 0x60, 0xee, 0x9c, 0xcc, 0x71, 0x2b, 0x4f, 0xe4, 0x57, 0xe0, 0xd5, 0x8e,
+	0x6f, 0x6f, 0xe2, 0x64, 0x53, 0x51, 0xf7, 0xa5, 0x18, 0x28, 0x3f, 0x64, 0xe6, 0xd6, 0x31, 0x0b,
+	0xbd, 0x77, 0x49, 0x0b, 0x88, 0x9b, 0x34, 0xb9, 0x5c, 0x0d, 0x3d, 0x39, 0xe5, 0xe6, 0x93, 0x99,
+	0xa6, 0x73, 0xba, 0x76, 0xc0, 0x3a, 0x0e, 0x22, 0x75, 0xe5, 0x75, 0xac, 0x8b, 0x20, 0x62, 0xee,
+	0xd7, 0xb0, 0x96, 0xf8, 0x46, 0x57, 0xda, 0xf5, 0xff, 0xd4, 0xaa, 0xa2, 0x52, 0xb1, 0x3a, 0x2d,
+	0x15, 0xee, 0x8f, 0xf0, 0x20, 0x4d, 0x22, 0x72, 0xe9, 0xd3, 0x0e, 0xc4, 0x19, 0x9d, 0xf5, 0x1d,
+	0xfc, 0x77, 0x9a, 0x7b, 0x79, 0xb3, 0x4c, 0xed, 0x8a, 0x39, 0x0d, 0xff, 0x61, 0x40, 0x55, 0xbe,
+	0x99, 0x08, 0x34, 0xab, 0x60, 0x77, 0x83, 0x71, 0xbe, 0x10, 0xc9, 0xa2, 0x2d, 0xad, 0x59, 0xce,
+	0x3a, 0x2c, 0x26, 0x62, 0xb9, 0x97, 0x09, 0x4d, 0x64, 0x7c, 0x99, 0x50, 0x82, 0xd9, 0xf5, 0xa2,
+	0x88, 0x82, 0x6a, 0xa6, 0x60, 0xba, 0x66, 0xd0, 0xf7, 0x2d, 0x38, 0xe8, 0x08, 0x41, 0xe2, 0x10,
+	0xee, 0xb0, 0xf3, 0xcc, 0x1b, 0x84, 0x71, 0xcd, 0xe0, 0x8c, 0x88, 0x7f, 0x37, 0xa0, 0x9e, 0xb3,
+	0x28, 0x16, 0xcf, 0x0c, 0xe3, 0x6f, 0x7a, 0xc1, 0xc9, 0x36, 0xc5, 0x13, 0xa8, 0x76, 0x03, 0x75,
+	0x91, 0x2a, 0x8f, 0xb7, 0x89, 0x67, 0xf1, 0x15, 0x66, 0x0d, 0xd1, 0x0a, 0x6a, 0x7e, 0x64, 0x98,
+	0x21, 0x2c, 0x49, 0x96, 0xf3, 0x61, 0x87, 0xf1, 0xb1, 0x20, 0x8f, 0x30, 0x77, 0xc0, 0x5f, 0x53,
+	0x2e, 0xcd, 0x55, 0x52, 0xe6, 0xcb, 0x8d, 0x68, 0x2a, 0xf3, 0xef, 0x02, 0xfd, 0x2d, 0x38, 0x58,
+	0xeb, 0x4f, 0x56, 0xab, 0x1e, 0xd4, 0x73, 0x06, 0xe7, 0x5c, 0x46, 0xef, 0xe0, 0x63, 0x4f, 0xed,
+	0x97, 0x1f, 0x9f, 0x6f, 0x34, 0x33, 0x23, 0xdf, 0x7a, 0x3b, 0x36, 0xe6, 0x82, 0x3e, 0x23, 0xdf,
+	0x97, 0xb0, 0x9e, 0x19, 0x06, 0x6d, 0xcf, 0x1f, 0xea, 0x61, 0xb0, 0x02, 0xb5, 0x17, 0x61, 0x30,
+	0x9a, 0xbc, 0x77, 0xc5, 0xde, 0xe4, 0xf9, 0x43, 0x75, 0x4f, 0x56, 0xc5, 0x80, 0xdd, 0xa1, 0x9c,
+	0x4d, 0xb4, 0xb0, 0x1e, 0xb0, 0xd6, 0xac, 0x35, 0x62, 0xc2, 0x73, 0x90, 0x7a, 0x4e, 0x9d, 0x18,
+	0xe8, 0x04, 0xc0, 0xec, 0xf2, 0x3b, 0x4d, 0x6f, 0xf7, 0x35, 0xac, 0x76, 0xd8, 0xf9, 0xcc, 0x45,
+	0x4e, 0x5b, 0x9b, 0x51, 0x98, 0x9f, 0x0c, 0xd8, 0xd4, 0x15, 0x99, 0x69, 0x73, 0x72, 0x1d, 0x34,
+	0x67, 0xad, 0x83, 0x73, 0x06, 0xf4, 0x12, 0xe0, 0x28, 0xee, 0x3d, 0xef, 0xf7, 0x83, 0xd8, 0x8f,
+	0xf0, 0x93, 0x0b, 0x3d, 0xa3, 0xe2, 0x1b, 0x83, 0x31, 0xf9, 0xc9, 0x21, 0xf9, 0x06, 0xf6, 0x9c,
+	0x73, 0x16, 0x71, 0xf5, 0x12, 0x07, 0x60, 0xbe, 0x90, 0xdf, 0xbf, 0xec, 0x7f, 0x07, 0x00, 0xbe,
+	0x27, 0xf4, 0xe8, 0x8c, 0x13, 0x00, 0x00,
 }
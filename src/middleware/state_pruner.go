@@ -0,0 +1,162 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package middleware
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/middleware/db"
+	"com.tuntun.rangers/node/src/middleware/log"
+	"com.tuntun.rangers/node/src/storage/trie"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// StateGCModeArchive keeps every block state on disk
+	StateGCModeArchive = "archive"
+	// StateGCModeFull keeps the recent block states and the checkpoints only
+	StateGCModeFull = "full"
+
+	stateGCModeKey     = "gcmode"
+	stateRetentionKey  = "stateRetention"
+	stateCheckpointKey = "stateCheckpoint"
+	stateCacheKey      = "stateCache"
+	stateFlushKey      = "stateFlushInterval"
+
+	defaultStateRetention  = 128
+	defaultStateCheckpoint = 10000
+	// MB
+	defaultStateCache = 256
+	// seconds
+	defaultStateFlushInterval = 3600
+)
+
+var ErrStatePruned = errors.New("state is pruned, historical state is not kept in gcmode full")
+
+type prunedRoot struct {
+	height uint64
+	root   common.Hash
+}
+
+// statePruner keeps the states of the last retention blocks in memory and
+// writes a state to disk every checkpoint blocks.
+// States fall out of the window are dereferenced and garbage collected.
+// Besides, the state falling out of the window is written to disk when flushInterval
+// passed since the last write or the cache is over cacheLimit.
+//
+// After a crash the node rewinds to the latest state on disk, that is at most
+// min(checkpoint, retention + the blocks of flushInterval) blocks back,
+// unless the cache fills up faster which only shortens the rewind.
+type statePruner struct {
+	trieDB *trie.NodeDatabase
+
+	retention     uint64
+	checkpoint    uint64
+	cacheLimit    common.StorageSize
+	flushInterval time.Duration
+	lastFlush     time.Time
+
+	roots []prunedRoot
+	lock  sync.Mutex
+
+	logger log.Logger
+}
+
+// newStatePruner returns nil when gcmode is archive
+func newStatePruner(trieDB *trie.NodeDatabase, logger log.Logger) *statePruner {
+	mode := common.GlobalConf.GetString(common.ConfigSec, stateGCModeKey, StateGCModeArchive)
+	if mode != StateGCModeFull {
+		return nil
+	}
+
+	cache := common.GlobalConf.GetInt(common.ConfigSec, stateCacheKey, defaultStateCache)
+	pruner := &statePruner{
+		trieDB:        trieDB,
+		retention:     uint64(common.GlobalConf.GetInt(common.ConfigSec, stateRetentionKey, defaultStateRetention)),
+		checkpoint:    uint64(common.GlobalConf.GetInt(common.ConfigSec, stateCheckpointKey, defaultStateCheckpoint)),
+		cacheLimit:    common.StorageSize(cache * 1024 * 1024),
+		flushInterval: time.Duration(common.GlobalConf.GetInt(common.ConfigSec, stateFlushKey, defaultStateFlushInterval)) * time.Second,
+		lastFlush:     time.Now(),
+		roots:         make([]prunedRoot, 0),
+		logger:        logger,
+	}
+	if 0 == pruner.retention {
+		pruner.retention = 1
+	}
+	if 0 == pruner.checkpoint {
+		pruner.checkpoint = defaultStateCheckpoint
+	}
+	if 0 == pruner.flushInterval {
+		pruner.flushInterval = defaultStateFlushInterval * time.Second
+	}
+	logger.Infof("state pruning enabled. retention: %d, checkpoint: %d, cache: %dMB, flush interval: %s", pruner.retention, pruner.checkpoint, cache, pruner.flushInterval)
+	return pruner
+}
+
+func (pruner *statePruner) commit(root common.Hash, height uint64) error {
+	pruner.lock.Lock()
+	defer pruner.lock.Unlock()
+
+	pruner.trieDB.Reference(root, common.Hash{})
+	pruner.roots = append(pruner.roots, prunedRoot{height: height, root: root})
+
+	if 0 == height%pruner.checkpoint {
+		if err := pruner.trieDB.Commit(root, false); err != nil {
+			return err
+		}
+		pruner.lastFlush = time.Now()
+		pruner.logger.Infof("state checkpoint committed. height: %d, root: %s", height, root.String())
+	}
+
+	for len(pruner.roots) > 0 && pruner.roots[0].height+pruner.retention <= height {
+		oldest := pruner.roots[0]
+		nodes, preimages := pruner.trieDB.Size()
+		if time.Since(pruner.lastFlush) >= pruner.flushInterval || nodes+preimages > pruner.cacheLimit {
+			if err := pruner.trieDB.Commit(oldest.root, false); err != nil {
+				return err
+			}
+			pruner.lastFlush = time.Now()
+			pruner.logger.Infof("state flushed. height: %d, root: %s, cache: %s", oldest.height, oldest.root.String(), (nodes + preimages).String())
+		}
+		pruner.trieDB.Dereference(oldest.root)
+		pruner.roots = pruner.roots[1:]
+	}
+
+	nodes, preimages := pruner.trieDB.Size()
+	if nodes+preimages > pruner.cacheLimit {
+		return pruner.trieDB.Cap(pruner.cacheLimit - db.IdealBatchSize)
+	}
+	return nil
+}
+
+// flush writes the latest state to disk so that the node can restart from it
+func (pruner *statePruner) flush() {
+	pruner.lock.Lock()
+	defer pruner.lock.Unlock()
+
+	if 0 == len(pruner.roots) {
+		return
+	}
+	latest := pruner.roots[len(pruner.roots)-1]
+	if err := pruner.trieDB.Commit(latest.root, false); err != nil {
+		pruner.logger.Errorf("fail to flush state. height: %d, root: %s, error: %s", latest.height, latest.root.String(), err.Error())
+		return
+	}
+	pruner.lastFlush = time.Now()
+	pruner.logger.Infof("state flushed. height: %d, root: %s", latest.height, latest.root.String())
+}
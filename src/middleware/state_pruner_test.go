@@ -0,0 +1,138 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package middleware
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/middleware/db"
+	"com.tuntun.rangers/node/src/middleware/log"
+	"com.tuntun.rangers/node/src/storage/account"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestStatePruner(t *testing.T) {
+	memdb, _ := db.NewMemDatabase()
+	stateDB := account.NewDatabase(memdb)
+	pruner := &statePruner{
+		trieDB:        stateDB.TrieDB(),
+		retention:     2,
+		checkpoint:    4,
+		cacheLimit:    256 * 1024 * 1024,
+		flushInterval: time.Hour,
+		lastFlush:     time.Now(),
+		roots:         make([]prunedRoot, 0),
+		logger:        log.GetLoggerByIndex(log.AccountDBLogConfig, "0"),
+	}
+	manager := &AccountDBManager{stateDB: stateDB, pruner: pruner}
+
+	roots := make([]common.Hash, 0)
+	root := common.Hash{}
+	addr := common.BytesToAddress([]byte("pruner"))
+	for height := uint64(1); height <= 6; height++ {
+		state, err := manager.GetAccountDBByHash(root)
+		if err != nil {
+			t.Fatalf("height %d: %v", height, err)
+		}
+		state.SetBalance(addr, new(big.Int).SetUint64(height))
+		root, _ = state.Commit(true)
+		if err := manager.CommitState(root, height); err != nil {
+			t.Fatal(err)
+		}
+		roots = append(roots, root)
+	}
+
+	// 1,2,3 are pruned, 4 is checkpoint, 5 and 6 are in retention window
+	for i, root := range roots {
+		height := i + 1
+		state, err := manager.GetAccountDBByHash(root)
+		if height < 4 {
+			if err != ErrStatePruned {
+				t.Fatalf("height %d: expected pruned state, got %v", height, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("height %d: %v", height, err)
+		}
+		if balance := state.GetBalance(addr); balance.Uint64() != uint64(height) {
+			t.Fatalf("height %d: balance mismatch, got %d", height, balance.Uint64())
+		}
+	}
+
+	// checkpoint is on disk
+	if ok, _ := memdb.Has(roots[3].Bytes()); !ok {
+		t.Fatal("checkpoint state not committed")
+	}
+	if ok, _ := memdb.Has(roots[5].Bytes()); ok {
+		t.Fatal("recent state should be kept in memory")
+	}
+	pruner.flush()
+	if ok, _ := memdb.Has(roots[5].Bytes()); !ok {
+		t.Fatal("latest state not flushed")
+	}
+}
+
+func TestStatePrunerFlushInterval(t *testing.T) {
+	memdb, _ := db.NewMemDatabase()
+	stateDB := account.NewDatabase(memdb)
+	pruner := &statePruner{
+		trieDB:        stateDB.TrieDB(),
+		retention:     2,
+		checkpoint:    100,
+		cacheLimit:    256 * 1024 * 1024,
+		flushInterval: time.Hour,
+		lastFlush:     time.Now().Add(-2 * time.Hour),
+		roots:         make([]prunedRoot, 0),
+		logger:        log.GetLoggerByIndex(log.AccountDBLogConfig, "0"),
+	}
+	manager := &AccountDBManager{stateDB: stateDB, pruner: pruner}
+
+	roots := make([]common.Hash, 0)
+	root := common.Hash{}
+	addr := common.BytesToAddress([]byte("pruner"))
+	for height := uint64(1); height <= 4; height++ {
+		state, _ := manager.GetAccountDBByHash(root)
+		state.SetBalance(addr, new(big.Int).SetUint64(height))
+		root, _ = state.Commit(true)
+		if err := manager.CommitState(root, height); err != nil {
+			t.Fatal(err)
+		}
+		roots = append(roots, root)
+	}
+
+	// the interval passed when 1 fell out of the window, 2 is pruned within the next interval
+	if ok, _ := memdb.Has(roots[0].Bytes()); !ok {
+		t.Fatal("state falling out of the window not flushed")
+	}
+	if _, err := manager.GetAccountDBByHash(roots[1]); err != ErrStatePruned {
+		t.Fatalf("expected pruned state, got %v", err)
+	}
+
+	// over the cache limit
+	pruner.cacheLimit = 1
+	state, _ := manager.GetAccountDBByHash(root)
+	state.SetBalance(addr, big.NewInt(5))
+	root, _ = state.Commit(true)
+	if err := manager.CommitState(root, 5); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := memdb.Has(roots[2].Bytes()); !ok {
+		t.Fatal("state not flushed over the cache limit")
+	}
+}
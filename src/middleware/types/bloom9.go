@@ -131,6 +131,30 @@ func CreateBloom(receipts Receipts) Bloom {
 	return bin
 }
 
+// BytesBloom creates a bloom filter of the given address or topics
+func BytesBloom(data ...[]byte) Bloom {
+	buf := make([]byte, 6)
+	var bin Bloom
+	for _, d := range data {
+		bin.add(d, buf)
+	}
+	return bin
+}
+
+// Bits returns the positions of the set bits. Position i is bit i%8 of byte BloomByteLength-1-i/8
+func (b Bloom) Bits() []uint {
+	bits := make([]uint, 0)
+	for i := 0; i < BloomByteLength; i++ {
+		value := b[BloomByteLength-1-i]
+		for j := uint(0); j < 8 && 0 != value; j++ {
+			if 0 != value&(1<<j) {
+				bits = append(bits, uint(i)*8+j)
+			}
+		}
+	}
+	return bits
+}
+
 // add is internal version of Add, which takes a scratch buffer for reuse (needs to be at least 6 bytes)
 func (b *Bloom) add(d []byte, buf []byte) {
 	i1, v1, i2, v2, i3, v3 := bloomValues(d, buf)
@@ -100,6 +100,10 @@ type BlockHeader struct {
 	ExtraData    []byte
 	Random       []byte
 	EvictedTxs   []common.Hash
+
+	// set since Proposal029
+	GasLimit uint64
+	GasUsed  uint64
 }
 
 type header struct {
@@ -120,6 +124,10 @@ type header struct {
 	ExtraData    []byte
 	ProveRoot    common.Hash
 	EvictedTxs   []common.Hash
+
+	// zero before Proposal029, leaving the hash of the old blocks as it was
+	GasLimit uint64 `json:",omitempty"`
+	GasUsed  uint64 `json:",omitempty"`
 }
 
 func (bh *BlockHeader) GenHash() common.Hash {
@@ -140,6 +148,8 @@ func (bh *BlockHeader) GenHash() common.Hash {
 		ExtraData:    bh.ExtraData,
 		//ProveRoot:    bh.ProveRoot,
 		EvictedTxs: bh.EvictedTxs,
+		GasLimit:   bh.GasLimit,
+		GasUsed:    bh.GasUsed,
 	}
 	blockByte, _ := json.Marshal(header)
 	result := common.BytesToHash(common.Sha256(blockByte))
@@ -147,6 +157,12 @@ func (bh *BlockHeader) GenHash() common.Hash {
 	return result
 }
 
+// RandaoMix is the randomness of the group signature in 32 bytes,
+// the PREVRANDAO of the next block
+func (bh *BlockHeader) RandaoMix() common.Hash {
+	return common.BytesToHash(common.Sha256(bh.Random))
+}
+
 func (bh *BlockHeader) ToString() string {
 	header := &header{
 		Height:       bh.Height,
@@ -164,6 +180,8 @@ func (bh *BlockHeader) ToString() string {
 		StateTree:    bh.StateTree,
 		ExtraData:    bh.ExtraData,
 		EvictedTxs:   bh.EvictedTxs,
+		GasLimit:     bh.GasLimit,
+		GasUsed:      bh.GasUsed,
 	}
 	blockByte, _ := json.Marshal(header)
 	return string(blockByte)
@@ -352,6 +370,25 @@ type ContractData struct {
 	GasPrice string `json:"gasPrice,omitempty"`
 	GasLimit string `json:"gasLimit,omitempty"`
 
-	TransferValue string `json:"transferValue,omitempty"`
-	AbiData       string `json:"abiData,omitempty"`
+	TransferValue string     `json:"transferValue,omitempty"`
+	AbiData       string     `json:"abiData,omitempty"`
+	AccessList    AccessList `json:"accessList,omitempty"`
+}
+
+// AccessList is an EIP-2930 access list.
+type AccessList []AccessTuple
+
+// AccessTuple is the element type of an access list.
+type AccessTuple struct {
+	Address     common.Address `json:"address"`
+	StorageKeys []common.Hash  `json:"storageKeys"`
+}
+
+// StorageKeys returns the total number of storage keys in the access list.
+func (al AccessList) StorageKeys() int {
+	sum := 0
+	for _, tuple := range al {
+		sum += len(tuple.StorageKeys)
+	}
+	return sum
 }
@@ -0,0 +1,122 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"bytes"
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/utility"
+)
+
+const (
+	// EvidenceTypeDoubleSign proves a member voted for two blocks of the same proposal
+	EvidenceTypeDoubleSign byte = 1
+	// EvidenceTypeAbsence reports a member missing most pieces of the blocks its group signed in an epoch
+	EvidenceTypeAbsence byte = 2
+)
+
+// Vote is the signature of a group member with its miner key on a block it verified
+type Vote struct {
+	BlockHash common.Hash `json:"blockHash"`
+	Sign      HexBytes    `json:"sign"`
+}
+
+// ProposalId identifies a proposal of the castor. A castor proposes at a height again
+// only on another pre block or with another cast time after its proposal timed out
+func ProposalId(height uint64, castor []byte, preHash common.Hash, castTime int64) common.Hash {
+	buffer := bytes.Buffer{}
+	buffer.Write(utility.UInt64ToByte(height))
+	buffer.Write(castor)
+	buffer.Write(preHash.Bytes())
+	buffer.Write(utility.UInt64ToByte(uint64(castTime)))
+	return common.BytesToHash(common.Sha256(buffer.Bytes()))
+}
+
+// VoteHash is what a member signs with its miner key for the block of the proposal.
+// An honest member votes for one block of a proposal only.
+func VoteHash(proposalId common.Hash, blockHash common.Hash) common.Hash {
+	buffer := bytes.Buffer{}
+	buffer.Write(proposalId.Bytes())
+	buffer.Write(blockHash.Bytes())
+	return common.BytesToHash(common.Sha256(buffer.Bytes()))
+}
+
+// Evidence proves the misbehaviour of a group member, it is the data of the TransactionTypeEvidence tx
+type Evidence struct {
+	Type   byte     `json:"type"`
+	Member HexBytes `json:"member"`
+	// the miner sending the evidence tx from its account
+	Reporter HexBytes `json:"reporter,omitempty"`
+
+	// double sign, the cast time is in unix nano
+	Height   uint64      `json:"height,omitempty"`
+	Castor   HexBytes    `json:"castor,omitempty"`
+	PreHash  common.Hash `json:"preHash,omitempty"`
+	CastTime int64       `json:"castTime,omitempty"`
+	First    *Vote       `json:"first,omitempty"`
+	Second   *Vote       `json:"second,omitempty"`
+
+	// absence, signed by the reporter with its miner key
+	GroupId HexBytes `json:"groupId,omitempty"`
+	Epoch   uint64   `json:"epoch,omitempty"`
+	Blocks  uint64   `json:"blocks,omitempty"`
+	Missed  uint64   `json:"missed,omitempty"`
+	Sign    HexBytes `json:"sign,omitempty"`
+}
+
+// Hash identifies the offence, an offence is punished once however many times it is proved
+func (e *Evidence) Hash() common.Hash {
+	buffer := bytes.Buffer{}
+	buffer.WriteByte(e.Type)
+	buffer.Write(e.Member)
+	if EvidenceTypeDoubleSign == e.Type {
+		buffer.Write(e.ProposalId().Bytes())
+	} else {
+		buffer.Write(e.GroupId)
+		buffer.Write(utility.UInt64ToByte(e.Epoch))
+	}
+	return common.BytesToHash(common.Sha256(buffer.Bytes()))
+}
+
+// ProposalId is the proposal the member double signed
+func (e *Evidence) ProposalId() common.Hash {
+	return ProposalId(e.Height, e.Castor, e.PreHash, e.CastTime)
+}
+
+// ReportHash is what the reporter of an absence signs
+func (e *Evidence) ReportHash() common.Hash {
+	buffer := bytes.Buffer{}
+	buffer.Write(e.Hash().Bytes())
+	buffer.Write(utility.UInt64ToByte(e.Blocks))
+	buffer.Write(utility.UInt64ToByte(e.Missed))
+	buffer.Write(e.Reporter)
+	return common.BytesToHash(common.Sha256(buffer.Bytes()))
+}
+
+// GenHash lets the reporter sign the report as a consensus message
+func (e *Evidence) GenHash() common.Hash {
+	return e.ReportHash()
+}
+
+// EvidenceRecord is an offence punished on chain
+type EvidenceRecord struct {
+	Evidence
+
+	Hash        common.Hash `json:"hash"`
+	BlockHeight uint64      `json:"blockHeight"`
+	Slashed     uint64      `json:"slashed"`
+}
@@ -62,7 +62,7 @@ func (miner *Miner) GetMinerInfo() []byte {
 	result["publicKey"] = miner.PublicKey
 	result["vrfPublicKey"] = miner.VrfPublicKey
 	result["applyHeight"] = miner.ApplyHeight
-	if !common.IsProposal003() {
+	if !common.CurrentRules().IsProposal003 {
 		result["status"] = miner.Status
 	}
 	result["type"] = miner.Type
@@ -248,7 +248,8 @@ func PbToBlockHeader(h *middleware_pb.BlockHeader) *BlockHeader {
 	header := BlockHeader{Hash: common.BytesToHash(h.Hash), Height: *h.Height, PreHash: common.BytesToHash(h.PreHash), PreTime: preTime,
 		ProveValue: proveValue, CurTime: curTime, Castor: h.Castor, GroupId: h.GroupId, Signature: h.Signature,
 		Nonce: *h.Nonce, Transactions: hashes, TxTree: common.BytesToHash(h.TxTree), ReceiptTree: common.BytesToHash(h.ReceiptTree), StateTree: common.BytesToHash(h.StateTree),
-		ExtraData: h.ExtraData, TotalQN: *h.TotalQN, Random: h.Random, EvictedTxs: hashes2}
+		ExtraData: h.ExtraData, TotalQN: *h.TotalQN, Random: h.Random, EvictedTxs: hashes2,
+		GasLimit: h.GetGasLimit(), GasUsed: h.GetGasUsed()}
 
 	if nil != h.RequestIds {
 		json.Unmarshal(h.RequestIds, &header.RequestIds)
@@ -408,6 +409,11 @@ func BlockHeaderToPb(h *BlockHeader) *middleware_pb.BlockHeader {
 		ExtraData: h.ExtraData, TotalQN: &h.TotalQN, Random: h.Random, EvictedTxs: &evictedTxs}
 
 	header.RequestIds, _ = json.Marshal(h.RequestIds)
+	// the blocks before Proposal029 keep their encoding
+	if 0 != h.GasLimit {
+		header.GasLimit = &h.GasLimit
+		header.GasUsed = &h.GasUsed
+	}
 	return &header
 }
 
@@ -20,8 +20,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	middleware_pb "com.tuntun.rangers/node/src/middleware/pb"
+	"github.com/gogo/protobuf/proto"
 )
 
 func TestPbToBlockHeader(t *testing.T) {
@@ -41,6 +43,40 @@ func TestPbToBlockHeader(t *testing.T) {
 
 }
 
+func TestBlockHeaderGas(t *testing.T) {
+	header := &BlockHeader{Height: 100, CurTime: time.Unix(1700000000, 0), PreTime: time.Unix(1699999999, 0)}
+	hash := header.GenHash()
+
+	data, err := MarshalBlockHeader(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pb := new(middleware_pb.BlockHeader)
+	if err := proto.Unmarshal(data, pb); err != nil {
+		t.Fatal(err)
+	}
+	if pb.GasLimit != nil || pb.GasUsed != nil {
+		t.Fatalf("gas encoded before Proposal029")
+	}
+
+	header.GasLimit = 6000000000
+	header.GasUsed = 21000
+	if header.GenHash() == hash {
+		t.Fatalf("gas not in the hash")
+	}
+	data, err = MarshalBlockHeader(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := UnMarshalBlockHeader(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.GasLimit != header.GasLimit || decoded.GasUsed != header.GasUsed {
+		t.Fatalf("gas limit %d, gas used %d after decoding", decoded.GasLimit, decoded.GasUsed)
+	}
+}
+
 func TestMarshalTransactions(t *testing.T) {
 	txs := make([]*Transaction, 2)
 	//txs[0] = &Transaction{Source: "1234"}
@@ -19,6 +19,7 @@ package types
 import (
 	"bytes"
 	"com.tuntun.rangers/node/src/common"
+	"encoding/json"
 	"math/big"
 	"strconv"
 )
@@ -30,6 +31,7 @@ const (
 	TransactionTypeMinerAdd           = 5
 	TransactionTypeMinerChangeAccount = 6
 	TransactionTypeOperatorNode       = 7 // 成为矿主
+	TransactionTypeEvidence           = 8
 
 	TransactionTypeOperatorBalance = 99
 	TransactionTypeOperatorEvent   = 100
@@ -56,6 +58,13 @@ const (
 
 var DefaultGasPrice = big.NewInt(1000000000)
 
+const (
+	// DefaultTxGasLimit is the gas limit of a contract tx without one
+	DefaultTxGasLimit uint64 = 30000000
+	// MaxTxGasLimit is the gas a contract tx may use at most since Proposal026
+	MaxTxGasLimit uint64 = 900000000
+)
+
 type Transaction struct {
 	Source string
 	Target string
@@ -118,7 +127,7 @@ func (c Transactions) Swap(i, j int) {
 }
 func (c Transactions) Less(i, j int) bool {
 	if c[i].RequestId == 0 && c[j].RequestId == 0 {
-		if common.IsProposal023() {
+		if common.CurrentRules().IsProposal023 {
 			if c[i].Source == c[j].Source {
 				if c[i].Nonce != c[j].Nonce {
 					return c[i].Nonce < c[j].Nonce
@@ -139,7 +148,7 @@ func (c Transactions) Less(i, j int) bool {
 			return num1.Cmp(num2) > 0
 		}
 
-		if common.IsProposal021() {
+		if common.CurrentRules().IsProposal021 {
 			if c[i].Source == c[j].Source {
 				return c[i].Nonce < c[j].Nonce
 			}
@@ -149,7 +158,7 @@ func (c Transactions) Less(i, j int) bool {
 			return num1.Cmp(num2) > 0
 		}
 
-		if common.IsProposal016() && c[i].Source == c[j].Source {
+		if common.CurrentRules().IsProposal016 && c[i].Source == c[j].Source {
 			return c[i].Nonce < c[j].Nonce
 		}
 
@@ -164,3 +173,27 @@ func (c Transactions) Less(i, j int) bool {
 func IsContractTx(txType int32) bool {
 	return txType == TransactionTypeETHTX || txType == TransactionTypeContract
 }
+
+// GasLimit returns the gas the tx may use at most, capped as the contract executor does.
+// Only contract txs use gas
+func (tx *Transaction) GasLimit() uint64 {
+	if !IsContractTx(tx.Type) {
+		return 0
+	}
+
+	var data ContractData
+	if err := json.Unmarshal([]byte(tx.Data), &data); err != nil {
+		return 0
+	}
+	if data.GasLimit == "" || data.GasLimit == "0" {
+		return DefaultTxGasLimit
+	}
+	gasLimit, err := strconv.ParseUint(data.GasLimit, 10, 64)
+	if err != nil {
+		return 0
+	}
+	if gasLimit > MaxTxGasLimit {
+		return MaxTxGasLimit
+	}
+	return gasLimit
+}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"com.tuntun.rangers/node/src/common"
 	"com.tuntun.rangers/node/src/middleware/log"
+	"com.tuntun.rangers/node/src/middleware/metrics"
 	"com.tuntun.rangers/node/src/middleware/notify"
 	"com.tuntun.rangers/node/src/middleware/types"
 	"com.tuntun.rangers/node/src/service"
@@ -48,6 +49,14 @@ const (
 	netIdSize = 32
 )
 
+var (
+	connectedGauge = metrics.NewGaugeVec("gateway_connected", "Whether the gateway connection is up, 1 or 0", "path")
+
+	// connStates keeps whether the connections are up by path. It is out of baseConn,
+	// since the server is copied into the instance after its connections start
+	connStates sync.Map
+)
+
 type wsHeader struct {
 	method   []byte
 	sourceId uint64
@@ -112,11 +121,13 @@ func (base *baseConn) getWSConn() *websocket.Conn {
 	conn, _, err := d.Dial(base.url, nil)
 	if err != nil {
 		base.logger.Errorf("Dial to " + base.url + " err:" + err.Error())
+		setConnected(base.path, false)
 		time.Sleep(100 * time.Millisecond)
 		return nil
 	}
 
 	base.logger.Debugf("connected to %s", base.url)
+	setConnected(base.path, true)
 	return conn
 }
 
@@ -231,6 +242,32 @@ func (base *baseConn) closeConn() {
 		base.conn.Close()
 	}
 	base.conn = nil
+	setConnected(base.path, false)
+}
+
+func setConnected(path string, connected bool) {
+	connStates.Store(path, connected)
+	if connected {
+		connectedGauge.WithLabelValues(path).Set(1)
+	} else {
+		connectedGauge.WithLabelValues(path).Set(0)
+	}
+}
+
+// ConnStates returns whether the gateway connections are up by path
+func ConnStates() map[string]bool {
+	states := make(map[string]bool)
+	connStates.Range(func(key, value interface{}) bool {
+		states[key.(string)] = value.(bool)
+		return true
+	})
+	return states
+}
+
+// GatewayConnected tells whether the worker connection to the gateway is up
+func GatewayConnected() bool {
+	connected, ok := connStates.Load(workerPath)
+	return ok && connected.(bool)
 }
 
 func (base *baseConn) send(method []byte, target uint64, msg []byte, nonce uint64) {
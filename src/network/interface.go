@@ -97,6 +97,11 @@ func GetNetInstance() Network {
 	return &instance
 }
 
+// JoinedGroupNets returns the group nets joined on the gateway
+func JoinedGroupNets() []string {
+	return instance.worker.joinedGroupNets()
+}
+
 type MsgHandler interface {
 	Handle(sourceId string, msg Message) error
 }
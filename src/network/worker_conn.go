@@ -42,6 +42,8 @@ var (
 	methodCodeTxBroadcast, _ = hex.DecodeString("80000007")
 )
 
+const workerPath = "/srv/worker_worker"
+
 type WorkerConn struct {
 	baseConn
 	consensusHandler MsgHandler
@@ -82,7 +84,7 @@ func (workerConn *WorkerConn) Init(ipPort string, selfId []byte, consensusHandle
 		}
 	}
 
-	workerConn.init(ipPort, "/srv/worker_worker", logger)
+	workerConn.init(ipPort, workerPath, logger)
 	workerConn.setNetId(selfId)
 }
 
@@ -213,6 +215,17 @@ func (workerConn *WorkerConn) QuitGroupNet(groupId string) {
 	workerConn.logger.Debugf("Quit group: %v,targetId:%v,hex:%v", groupId, header.targetId, strconv.FormatUint(header.targetId, 16))
 }
 
+func (workerConn *WorkerConn) joinedGroupNets() []string {
+	workerConn.joinedGroupLock.Lock()
+	defer workerConn.joinedGroupLock.Unlock()
+
+	groups := make([]string, 0, len(workerConn.joinedGroup))
+	for groupId := range workerConn.joinedGroup {
+		groups = append(groups, groupId)
+	}
+	return groups
+}
+
 func (workerConn *WorkerConn) setNetId(netId []byte) {
 	header := wsHeader{method: methodSetNetId}
 	bytes := workerConn.headerToBytes(header)
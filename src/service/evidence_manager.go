@@ -0,0 +1,331 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"bytes"
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/consensus/groupsig"
+	"com.tuntun.rangers/node/src/middleware/log"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"com.tuntun.rangers/node/src/storage/account"
+	"com.tuntun.rangers/node/src/utility"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+)
+
+var (
+	EvidenceManagerImpl *EvidenceManager
+
+	evidenceCountPrefix    = utility.StrToBytes("count")
+	evidenceReporterPrefix = utility.StrToBytes("reporter")
+	evidenceMemberPrefix   = utility.StrToBytes("member")
+	evidenceBlocksPrefix   = utility.StrToBytes("blocks")
+	evidencePubkeyPrefix   = utility.StrToBytes("pubkey")
+)
+
+// evidenceWindow is the blocks an offence can be reported in.
+// It is the delay of the stake refunds, so the stake refunded after the offence is still slashable
+const evidenceWindow = refundHeight
+
+// EvidenceManager verifies the evidences of group members and slashes their stake.
+// The evidences punished are kept at common.EvidenceDBAddress
+type EvidenceManager struct {
+	logger           log.Logger
+	groupChainHelper types.GroupChainHelper
+	forkHelper       types.ForkHelper
+}
+
+func InitEvidenceManager(groupChainHelper types.GroupChainHelper, forkHelper types.ForkHelper) {
+	EvidenceManagerImpl = &EvidenceManager{}
+	EvidenceManagerImpl.logger = log.GetLoggerByIndex(log.EvidenceLogConfig, common.GlobalConf.GetString("instance", "index", ""))
+	EvidenceManagerImpl.groupChainHelper = groupChainHelper
+	EvidenceManagerImpl.forkHelper = forkHelper
+}
+
+// Verify checks the evidence sent by the source before it is executed at the height
+func (this *EvidenceManager) Verify(evidence *types.Evidence, source string, height uint64, situation string, accountdb *account.AccountDB) error {
+	if nil == evidence || 0 == len(evidence.Member) {
+		return errors.New("empty evidence")
+	}
+	if err := this.VerifyReporter(evidence, source, accountdb); nil != err {
+		return err
+	}
+	if nil != this.GetEvidence(evidence.Hash(), accountdb) {
+		return errors.New("evidence is punished already")
+	}
+
+	switch evidence.Type {
+	case types.EvidenceTypeDoubleSign:
+		if evidence.Height+evidenceWindow < height {
+			return fmt.Errorf("evidence expired, offence: %d, height: %d", evidence.Height, height)
+		}
+		return this.verifyDoubleSign(evidence, height, accountdb)
+	case types.EvidenceTypeAbsence:
+		if (evidence.Epoch+1)*common.GetGroupWorkDuration()+evidenceWindow < height {
+			return fmt.Errorf("evidence expired, epoch: %d, height: %d", evidence.Epoch, height)
+		}
+		return this.verifyAbsence(evidence, height, situation, accountdb)
+	default:
+		return fmt.Errorf("unknown evidence type: %d", evidence.Type)
+	}
+}
+
+// VerifyReporter checks the evidence is sent from the account of the registered miner reporting it
+func (this *EvidenceManager) VerifyReporter(evidence *types.Evidence, source string, accountdb *account.AccountDB) error {
+	if 0 == len(evidence.Reporter) || common.BytesToAddress(evidence.Reporter).GetHexString() != source {
+		return fmt.Errorf("source is not the reporter: %s", source)
+	}
+	if nil == MinerManagerImpl.GetMiner(evidence.Reporter, accountdb) {
+		return fmt.Errorf("reporter not existed: %s", common.ToHex(evidence.Reporter))
+	}
+	return nil
+}
+
+func (this *EvidenceManager) verifyDoubleSign(evidence *types.Evidence, height uint64, accountdb *account.AccountDB) error {
+	if nil == evidence.First || nil == evidence.Second || evidence.First.BlockHash == evidence.Second.BlockHash {
+		return errors.New("no conflicting votes")
+	}
+
+	pk, err := this.getMinerPubkey(evidence.Member, height, accountdb)
+	if nil != err {
+		return err
+	}
+	proposalId := evidence.ProposalId()
+	for _, vote := range []*types.Vote{evidence.First, evidence.Second} {
+		hash := types.VoteHash(proposalId, vote.BlockHash)
+		if !groupsig.VerifySig(pk, hash.Bytes(), *groupsig.DeserializeSign(vote.Sign)) {
+			return fmt.Errorf("wrong vote sign, block: %s", vote.BlockHash.String())
+		}
+	}
+	return nil
+}
+
+func (this *EvidenceManager) verifyAbsence(evidence *types.Evidence, height uint64, situation string, accountdb *account.AccountDB) error {
+	if evidence.Epoch >= common.GetGroupEpoch(height) {
+		return fmt.Errorf("epoch not finished, epoch: %d, height: %d", evidence.Epoch, height)
+	}
+	if evidence.Blocks < common.AbsenceMinBlocks || evidence.Missed*2 <= evidence.Blocks || evidence.Missed > evidence.Blocks {
+		return fmt.Errorf("not absent, blocks: %d, missed: %d", evidence.Blocks, evidence.Missed)
+	}
+	if bytes.Equal(evidence.Member, evidence.Reporter) {
+		return errors.New("reporter is the member")
+	}
+
+	var group *types.Group
+	if situation != "fork" {
+		group = this.groupChainHelper.GetGroupById(evidence.GroupId)
+	} else {
+		group = this.forkHelper.GetGroupById(evidence.GroupId)
+	}
+	if nil == group {
+		return fmt.Errorf("group not existed: %s", common.ToHex(evidence.GroupId))
+	}
+	if !isGroupMember(group, evidence.Member) || !isGroupMember(group, evidence.Reporter) {
+		return errors.New("member or reporter not in the group")
+	}
+	if !isGroupWorkingInEpoch(group, evidence.Epoch) {
+		return fmt.Errorf("group not working in the epoch, epoch: %d, work: %d, dismiss: %d", evidence.Epoch, group.Header.WorkHeight, group.Header.DismissHeight)
+	}
+	if signed := this.getSignedBlocks(evidence.GroupId, evidence.Epoch, accountdb); evidence.Blocks > signed {
+		return fmt.Errorf("more blocks than signed, blocks: %d, signed: %d", evidence.Blocks, signed)
+	}
+
+	if 0 != len(accountdb.GetData(common.EvidenceDBAddress, this.reporterKey(evidence))) {
+		return errors.New("reported already")
+	}
+
+	pk, err := this.getMinerPubkey(evidence.Reporter, height, accountdb)
+	if nil != err {
+		return err
+	}
+	if !groupsig.VerifySig(pk, evidence.ReportHash().Bytes(), *groupsig.DeserializeSign(evidence.Sign)) {
+		return errors.New("wrong reporter sign")
+	}
+	return nil
+}
+
+// Apply executes the verified evidence. A double sign is slashed at once,
+// while an absence is slashed when the most of the other members of the group report it
+func (this *EvidenceManager) Apply(evidence *types.Evidence, height uint64, situation string, accountdb *account.AccountDB) (bool, string) {
+	hash := evidence.Hash()
+	if types.EvidenceTypeDoubleSign == evidence.Type {
+		this.punish(evidence, common.DoubleSignSlashPercent, height, accountdb)
+		return true, ""
+	}
+
+	var group *types.Group
+	if situation != "fork" {
+		group = this.groupChainHelper.GetGroupById(evidence.GroupId)
+	} else {
+		group = this.forkHelper.GetGroupById(evidence.GroupId)
+	}
+	if nil == group {
+		msg := fmt.Sprintf("group not existed: %s", common.ToHex(evidence.GroupId))
+		this.logger.Errorf(msg)
+		return false, msg
+	}
+
+	accountdb.SetData(common.EvidenceDBAddress, this.reporterKey(evidence), []byte{1})
+	countKey := this.countKey(hash)
+	count := utility.ByteToUInt64(accountdb.GetData(common.EvidenceDBAddress, countKey)) + 1
+	accountdb.SetData(common.EvidenceDBAddress, countKey, utility.UInt64ToByte(count))
+	this.logger.Debugf("absence reported, hash: %s, reporter: %s, count: %d", hash.String(), common.ToHex(evidence.Reporter), count)
+
+	if count*2 > uint64(len(group.Members)-1) {
+		this.punish(evidence, common.AbsenceSlashPercent, height, accountdb)
+	}
+	return true, ""
+}
+
+// CountBlock counts the block signed by its group in the epoch of the height.
+// The blocks of an absence evidence can not exceed the count
+func (this *EvidenceManager) CountBlock(header *types.BlockHeader, accountdb *account.AccountDB) {
+	key := this.blocksKey(header.GroupId, common.GetGroupEpoch(header.Height))
+	count := utility.ByteToUInt64(accountdb.GetData(common.EvidenceDBAddress, key)) + 1
+	accountdb.SetData(common.EvidenceDBAddress, key, utility.UInt64ToByte(count))
+}
+
+func (this *EvidenceManager) getSignedBlocks(groupId []byte, epoch uint64, accountdb *account.AccountDB) uint64 {
+	return utility.ByteToUInt64(accountdb.GetData(common.EvidenceDBAddress, this.blocksKey(groupId, epoch)))
+}
+
+func (this *EvidenceManager) punish(evidence *types.Evidence, percent, height uint64, accountdb *account.AccountDB) {
+	record := types.EvidenceRecord{Evidence: *evidence, Hash: evidence.Hash(), BlockHeight: height}
+	record.Slashed = MinerManagerImpl.Slash(evidence.Member, percent, height, accountdb)
+
+	data, _ := json.Marshal(record)
+	accountdb.SetData(common.EvidenceDBAddress, record.Hash.Bytes(), data)
+
+	hashes := this.getMemberEvidenceHashes(evidence.Member, accountdb)
+	hashes = append(hashes, record.Hash)
+	data, _ = json.Marshal(hashes)
+	accountdb.SetData(common.EvidenceDBAddress, this.memberKey(evidence.Member), data)
+	this.logger.Warnf("punished, hash: %s, type: %d, member: %s, height: %d, slashed: %d", record.Hash.String(), evidence.Type, common.ToHex(evidence.Member), height, record.Slashed)
+}
+
+// GetEvidence returns the punished evidence of the offence hash
+func (this *EvidenceManager) GetEvidence(hash common.Hash, accountdb *account.AccountDB) *types.EvidenceRecord {
+	data := accountdb.GetData(common.EvidenceDBAddress, hash.Bytes())
+	if 0 == len(data) {
+		return nil
+	}
+
+	var record types.EvidenceRecord
+	if err := json.Unmarshal(data, &record); nil != err {
+		this.logger.Errorf("fail to unmarshal evidence: %s, %s", hash.String(), err.Error())
+		return nil
+	}
+	return &record
+}
+
+// GetMemberEvidences returns all the punished evidences of the member
+func (this *EvidenceManager) GetMemberEvidences(member []byte, accountdb *account.AccountDB) []*types.EvidenceRecord {
+	hashes := this.getMemberEvidenceHashes(member, accountdb)
+	result := make([]*types.EvidenceRecord, 0, len(hashes))
+	for _, hash := range hashes {
+		if record := this.GetEvidence(hash, accountdb); nil != record {
+			result = append(result, record)
+		}
+	}
+	return result
+}
+
+func (this *EvidenceManager) getMemberEvidenceHashes(member []byte, accountdb *account.AccountDB) []common.Hash {
+	var hashes []common.Hash
+	data := accountdb.GetData(common.EvidenceDBAddress, this.memberKey(member))
+	if 0 != len(data) {
+		json.Unmarshal(data, &hashes)
+	}
+	return hashes
+}
+
+// KeepPubkey keeps the pubkey of the miner removed at the height, its offences can be verified in the evidence window
+func (this *EvidenceManager) KeepPubkey(miner *types.Miner, height uint64, accountdb *account.AccountDB) {
+	data := append(utility.UInt64ToByte(height+evidenceWindow), miner.PublicKey...)
+	accountdb.SetData(common.EvidenceDBAddress, this.pubkeyKey(miner.Id), data)
+}
+
+func (this *EvidenceManager) getMinerPubkey(minerId []byte, height uint64, accountdb *account.AccountDB) (groupsig.Pubkey, error) {
+	miner := MinerManagerImpl.GetMiner(minerId, accountdb)
+	if nil != miner {
+		return groupsig.ByteToPublicKey(miner.PublicKey), nil
+	}
+
+	data := accountdb.GetData(common.EvidenceDBAddress, this.pubkeyKey(minerId))
+	if len(data) > 8 && utility.ByteToUInt64(data[:8]) >= height {
+		return groupsig.ByteToPublicKey(data[8:]), nil
+	}
+	return groupsig.Pubkey{}, fmt.Errorf("miner not existed: %s", common.ToHex(minerId))
+}
+
+func (this *EvidenceManager) reporterKey(evidence *types.Evidence) []byte {
+	buffer := bytes.Buffer{}
+	buffer.Write(evidenceReporterPrefix)
+	buffer.Write(evidence.Hash().Bytes())
+	buffer.Write(evidence.Reporter)
+	return common.Sha256(buffer.Bytes())
+}
+
+func (this *EvidenceManager) countKey(hash common.Hash) []byte {
+	buffer := bytes.Buffer{}
+	buffer.Write(evidenceCountPrefix)
+	buffer.Write(hash.Bytes())
+	return common.Sha256(buffer.Bytes())
+}
+
+func (this *EvidenceManager) memberKey(member []byte) []byte {
+	buffer := bytes.Buffer{}
+	buffer.Write(evidenceMemberPrefix)
+	buffer.Write(member)
+	return common.Sha256(buffer.Bytes())
+}
+
+func (this *EvidenceManager) pubkeyKey(minerId []byte) []byte {
+	buffer := bytes.Buffer{}
+	buffer.Write(evidencePubkeyPrefix)
+	buffer.Write(minerId)
+	return common.Sha256(buffer.Bytes())
+}
+
+func (this *EvidenceManager) blocksKey(groupId []byte, epoch uint64) []byte {
+	buffer := bytes.Buffer{}
+	buffer.Write(evidenceBlocksPrefix)
+	buffer.Write(groupId)
+	buffer.Write(utility.UInt64ToByte(epoch))
+	return common.Sha256(buffer.Bytes())
+}
+
+// isGroupWorkingInEpoch checks the epoch overlaps the working window of the group
+func isGroupWorkingInEpoch(group *types.Group, epoch uint64) bool {
+	if nil == group.Header {
+		return false
+	}
+	duration := common.GetGroupWorkDuration()
+	start := epoch * duration
+	return start < group.Header.DismissHeight && start+duration > group.Header.WorkHeight
+}
+
+func isGroupMember(group *types.Group, member []byte) bool {
+	for _, m := range group.Members {
+		if bytes.Equal(m, member) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,239 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/consensus/base"
+	"com.tuntun.rangers/node/src/consensus/groupsig"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"com.tuntun.rangers/node/src/storage/account"
+	"com.tuntun.rangers/node/src/utility"
+	"math"
+	"os"
+	"testing"
+)
+
+type evidenceGroupChain struct {
+	group *types.Group
+}
+
+func (chain *evidenceGroupChain) GetAvailableGroupsByMinerId(height uint64, minerId []byte) []*types.Group {
+	return []*types.Group{chain.group}
+}
+
+func (chain *evidenceGroupChain) GetGroupById(id []byte) *types.Group {
+	return chain.group
+}
+
+func setupEvidenceTest(count int) ([]groupsig.Seckey, *types.Group, *account.AccountDB) {
+	common.Init(0, "1.ini", "dev")
+	InitMinerManager()
+	InitRefundManager(nil, nil)
+
+	db := getTestAccountDB()
+	r := base.NewRand()
+	keys := make([]groupsig.Seckey, count)
+	group := &types.Group{Id: []byte{9, 9}, Members: make([][]byte, count),
+		Header: &types.GroupHeader{WorkHeight: 0, DismissHeight: common.GetGroupWorkDuration()}}
+	for i := 0; i < count; i++ {
+		keys[i] = *groupsig.NewSeckeyFromRand(r.Deri(i))
+		id := []byte{0, 0, byte(i + 1)}
+		group.Members[i] = id
+
+		miner := &types.Miner{Id: id, PublicKey: groupsig.GeneratePubkey(keys[i]).Serialize(), Type: common.MinerTypeValidator,
+			Stake: common.ValidatorStake * 2, Account: id, Status: common.MinerStatusNormal}
+		MinerManagerImpl.InsertMiner(miner, db)
+	}
+	InitEvidenceManager(&evidenceGroupChain{group: group}, nil)
+	return keys, group, db
+}
+
+func evidenceSource(minerId []byte) string {
+	return common.BytesToAddress(minerId).GetHexString()
+}
+
+func TestEvidenceManager_DoubleSign(t *testing.T) {
+	defer os.RemoveAll("logs")
+	defer os.Remove("1.ini")
+	defer os.RemoveAll("storage0")
+
+	keys, group, db := setupEvidenceTest(2)
+	evidence := &types.Evidence{Type: types.EvidenceTypeDoubleSign, Member: group.Members[0], Height: 100, Castor: []byte{1, 2, 3}}
+	source := evidenceSource(group.Members[1])
+	vote := func(hash common.Hash) *types.Vote {
+		sign := groupsig.Sign(keys[0], types.VoteHash(evidence.ProposalId(), hash).Bytes())
+		return &types.Vote{BlockHash: hash, Sign: sign.Serialize()}
+	}
+
+	evidence.First = vote(common.HexToHash("0x01"))
+	evidence.Second = vote(common.HexToHash("0x02"))
+	if err := EvidenceManagerImpl.Verify(evidence, source, 200, "", db); nil == err {
+		t.Fatalf("evidence without reporter accepted")
+	}
+	evidence.Reporter = []byte{1, 2, 3}
+	if err := EvidenceManagerImpl.Verify(evidence, evidenceSource(evidence.Reporter), 200, "", db); nil == err {
+		t.Fatalf("evidence reported by a non miner accepted")
+	}
+	evidence.Reporter = group.Members[1]
+	if err := EvidenceManagerImpl.Verify(evidence, evidenceSource(group.Members[0]), 200, "", db); nil == err {
+		t.Fatalf("evidence sent by another account accepted")
+	}
+
+	evidence.Second = vote(common.HexToHash("0x01"))
+	if err := EvidenceManagerImpl.Verify(evidence, source, 200, "", db); nil == err {
+		t.Fatalf("votes for the same block are not a double sign")
+	}
+
+	evidence.Second = vote(common.HexToHash("0x02"))
+	evidence.CastTime = 1
+	if err := EvidenceManagerImpl.Verify(evidence, source, 200, "", db); nil == err {
+		t.Fatalf("votes for another proposal are not a double sign")
+	}
+
+	evidence.CastTime = 0
+	if err := EvidenceManagerImpl.Verify(evidence, source, 200, "", db); nil != err {
+		t.Fatalf("fail to verify double sign: %s", err)
+	}
+	if ok, msg := EvidenceManagerImpl.Apply(evidence, 200, "", db); !ok {
+		t.Fatalf("fail to apply double sign: %s", msg)
+	}
+
+	miner := MinerManagerImpl.GetMiner(group.Members[0], db)
+	if miner.Stake != common.ValidatorStake*2*(100-common.DoubleSignSlashPercent)/100 {
+		t.Fatalf("wrong stake after slashed: %d", miner.Stake)
+	}
+	record := EvidenceManagerImpl.GetEvidence(evidence.Hash(), db)
+	if nil == record || record.Slashed != common.ValidatorStake*2*common.DoubleSignSlashPercent/100 || record.BlockHeight != 200 {
+		t.Fatalf("wrong evidence record: %v", record)
+	}
+	if 1 != len(EvidenceManagerImpl.GetMemberEvidences(group.Members[0], db)) {
+		t.Fatalf("wrong member evidences")
+	}
+	if err := EvidenceManagerImpl.Verify(evidence, source, 201, "", db); nil == err {
+		t.Fatalf("double sign punished twice")
+	}
+}
+
+func TestEvidenceManager_Absence(t *testing.T) {
+	defer os.RemoveAll("logs")
+	defer os.Remove("1.ini")
+	defer os.RemoveAll("storage0")
+
+	keys, group, db := setupEvidenceTest(4)
+	height := common.GetGroupWorkDuration()
+	report := func(reporter int) *types.Evidence {
+		evidence := &types.Evidence{Type: types.EvidenceTypeAbsence, Member: group.Members[0], GroupId: group.Id,
+			Epoch: 0, Blocks: common.AbsenceMinBlocks, Missed: common.AbsenceMinBlocks, Reporter: group.Members[reporter]}
+		evidence.Sign = groupsig.Sign(keys[reporter], evidence.ReportHash().Bytes()).Serialize()
+		return evidence
+	}
+
+	if err := EvidenceManagerImpl.Verify(report(1), evidenceSource(group.Members[1]), height-1, "", db); nil == err {
+		t.Fatalf("absence reported before the epoch is over")
+	}
+	if err := EvidenceManagerImpl.Verify(report(1), evidenceSource(group.Members[2]), height, "", db); nil == err {
+		t.Fatalf("absence sent by another member accepted")
+	}
+	forged := report(1)
+	forged.Missed = common.AbsenceMinBlocks - 1
+	if err := EvidenceManagerImpl.Verify(forged, evidenceSource(forged.Reporter), height, "", db); nil == err {
+		t.Fatalf("absence with a wrong sign accepted")
+	}
+
+	if err := EvidenceManagerImpl.Verify(report(1), evidenceSource(group.Members[1]), height, "", db); nil == err {
+		t.Fatalf("absence over the blocks signed accepted")
+	}
+	for i := uint64(0); i < common.AbsenceMinBlocks; i++ {
+		EvidenceManagerImpl.CountBlock(&types.BlockHeader{GroupId: group.Id, Height: i + 1}, db)
+	}
+	dismissed := report(1)
+	dismissed.Epoch = 1
+	dismissed.Sign = groupsig.Sign(keys[1], dismissed.ReportHash().Bytes()).Serialize()
+	if err := EvidenceManagerImpl.Verify(dismissed, evidenceSource(dismissed.Reporter), height*2, "", db); nil == err {
+		t.Fatalf("absence out of the group window accepted")
+	}
+
+	for i := 1; i <= 2; i++ {
+		evidence := report(i)
+		if err := EvidenceManagerImpl.Verify(evidence, evidenceSource(evidence.Reporter), height, "", db); nil != err {
+			t.Fatalf("fail to verify absence of reporter %d: %s", i, err)
+		}
+		EvidenceManagerImpl.Apply(evidence, height, "", db)
+		if err := EvidenceManagerImpl.Verify(evidence, evidenceSource(evidence.Reporter), height, "", db); nil == err {
+			t.Fatalf("reporter %d reported twice", i)
+		}
+
+		record := EvidenceManagerImpl.GetEvidence(evidence.Hash(), db)
+		if 1 == i && nil != record {
+			t.Fatalf("absence punished by one of three reporters")
+		}
+		if 2 == i && (nil == record || 0 == record.Slashed) {
+			t.Fatalf("absence not punished by two of three reporters")
+		}
+	}
+}
+
+func TestEvidenceManager_SlashRefund(t *testing.T) {
+	defer os.RemoveAll("logs")
+	defer os.Remove("1.ini")
+	defer os.RemoveAll("storage0")
+
+	keys, group, db := setupEvidenceTest(2)
+	member := group.Members[0]
+	refundHeight, money, account, err := RefundManagerImpl.GetRefundStake(100, member, member, math.MaxUint64, db, "")
+	if nil != err {
+		t.Fatalf("fail to refund: %s", err)
+	}
+	if nil != MinerManagerImpl.GetMiner(member, db) {
+		t.Fatalf("miner not removed after refunding all the stake")
+	}
+	refundInfo := types.RefundInfoList{}
+	refundInfo.AddRefundInfo(account, money)
+	RefundManagerImpl.Add(map[uint64]types.RefundInfoList{refundHeight: refundInfo}, db)
+
+	evidence := &types.Evidence{Type: types.EvidenceTypeDoubleSign, Member: member, Height: 100, Castor: []byte{1, 2, 3}, Reporter: group.Members[1]}
+	for i, hash := range []common.Hash{common.HexToHash("0x01"), common.HexToHash("0x02")} {
+		sign := groupsig.Sign(keys[0], types.VoteHash(evidence.ProposalId(), hash).Bytes())
+		vote := &types.Vote{BlockHash: hash, Sign: sign.Serialize()}
+		if 0 == i {
+			evidence.First = vote
+		} else {
+			evidence.Second = vote
+		}
+	}
+	source := evidenceSource(group.Members[1])
+	if err := EvidenceManagerImpl.Verify(evidence, source, 100+evidenceWindow+1, "", db); nil == err {
+		t.Fatalf("expired evidence accepted")
+	}
+	if err := EvidenceManagerImpl.Verify(evidence, source, 200, "", db); nil != err {
+		t.Fatalf("fail to verify double sign of the removed miner: %s", err)
+	}
+	EvidenceManagerImpl.Apply(evidence, 200, "", db)
+
+	slashed := common.ValidatorStake * 2 * common.DoubleSignSlashPercent / 100
+	record := EvidenceManagerImpl.GetEvidence(evidence.Hash(), db)
+	if nil == record || record.Slashed != slashed {
+		t.Fatalf("wrong evidence record: %v", record)
+	}
+
+	RefundManagerImpl.CheckAndMove(refundHeight, db)
+	expected := utility.Uint64ToBigInt(common.ValidatorStake*2 - slashed)
+	if balance := db.GetBalance(common.BytesToAddress(account)); 0 != balance.Cmp(expected) {
+		t.Fatalf("wrong refund after slashed: %s, expected: %s", balance, expected)
+	}
+}
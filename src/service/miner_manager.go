@@ -282,6 +282,34 @@ func (mm *MinerManager) AddStake(addr common.Address, minerId []byte, delta uint
 	return true, ""
 }
 
+// Slash burns the percent of the stake of the miner, and of its stake refunds not moved yet at the height.
+// The miner stops working if the stake left is not enough
+func (mm *MinerManager) Slash(minerId []byte, percent, height uint64, accountdb *account.AccountDB) uint64 {
+	refunds := RefundManagerImpl.Slash(minerId, percent, height, accountdb)
+	miner := mm.GetMiner(minerId, accountdb)
+	if nil == miner || 0 == miner.Stake {
+		return refunds
+	}
+
+	slashed := miner.Stake * percent / 100
+	if 0 == slashed {
+		slashed = 1
+	}
+	if slashed > miner.Stake {
+		slashed = miner.Stake
+	}
+
+	miner.Stake = miner.Stake - slashed
+	if miner.Type == common.MinerTypeProposer && miner.Stake < common.ProposerStake ||
+		miner.Type == common.MinerTypeValidator && miner.Stake < common.ValidatorStake {
+		miner.Status = common.MinerStatusAbort
+	}
+
+	mm.UpdateMiner(miner, accountdb, false)
+	mm.logger.Warnf("slash miner: %s, slashed: %d, stake left: %d, status: %d", common.ToHex(minerId), slashed, miner.Stake, miner.Status)
+	return slashed + refunds
+}
+
 func (mm *MinerManager) AddMiner(addr common.Address, miner *types.Miner, accountdb *account.AccountDB) (bool, string) {
 	if miner.Type != common.MinerTypeValidator && miner.Type != common.MinerTypeProposer {
 		msg := fmt.Sprintf("miner type error, minerId: %s, type: %d", common.ToHex(miner.Id), miner.Type)
@@ -352,7 +380,7 @@ func (mm *MinerManager) UpdateMiner(miner *types.Miner, accountdb *account.Accou
 	key = common.Sha256(key)
 	accountdb.SetData(db, key, miner.Account)
 
-	if common.IsProposal003() {
+	if common.CurrentRules().IsProposal003 {
 		key = common.Sha256(key)
 		accountdb.SetData(db, key, []byte{miner.Status})
 	}
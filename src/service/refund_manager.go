@@ -23,6 +23,7 @@ import (
 	"com.tuntun.rangers/node/src/middleware/types"
 	"com.tuntun.rangers/node/src/storage/account"
 	"com.tuntun.rangers/node/src/utility"
+	"encoding/json"
 	"fmt"
 	"github.com/pkg/errors"
 	"math"
@@ -42,8 +43,19 @@ type RefundManager struct {
 var (
 	RefundManagerImpl *RefundManager
 	prefix            = "refund"
+
+	// the stake refunds not moved yet, kept by miner id. They are slashable as the stake
+	pendingRefundAddress = common.BytesToAddress(common.Sha256(utility.StrToBytes("refundpending")))
+	slashedRefundPrefix  = "refundslashed"
 )
 
+// pendingRefund is the stake refunded to the account at the height
+type pendingRefund struct {
+	Height  uint64
+	Account []byte
+	Value   uint64
+}
+
 func InitRefundManager(groupChainHelper types.GroupChainHelper, forkHelper types.ForkHelper) {
 	RefundManagerImpl = &RefundManager{}
 	RefundManagerImpl.logger = log.GetLoggerByIndex(log.RefundLogConfig, common.GlobalConf.GetString("instance", "index", ""))
@@ -69,7 +81,17 @@ func (refund *RefundManager) CheckAndMove(height uint64, db *account.AccountDB)
 		return
 	}
 
+	slashedAddress := refund.generateSlashedAddress(height)
 	for addr, value := range refundList {
+		if slashedBytes := db.GetData(slashedAddress, addr.Bytes()); 0 != len(slashedBytes) {
+			slashed := new(big.Int).SetBytes(slashedBytes)
+			if slashed.Cmp(value) > 0 {
+				slashed = value
+			}
+			value = new(big.Int).Sub(value, slashed)
+			db.RemoveData(slashedAddress, addr.Bytes())
+			refund.logger.Warnf("refund slashed, height: %d, address: %s, slashed: %d", height, addr.String(), slashed)
+		}
 		db.AddBalance(addr, value)
 		db.RemoveData(address, addr.Bytes())
 		refund.logger.Warnf("refunded, height: %d, address: %s, delta: %d", height, addr.String(), value)
@@ -129,9 +151,11 @@ func (this *RefundManager) GetRefundStake(now uint64, minerId, account []byte, m
 
 	refund := money
 	left := miner.Stake - money
+	removed := false
 	if miner.Type == common.MinerTypeProposer && left < common.ProposerStake ||
 		miner.Type == common.MinerTypeValidator && left < common.ValidatorStake {
 		MinerManagerImpl.RemoveMiner(minerId, account, miner.Type, accountdb, left)
+		removed = true
 	} else {
 		// update miner
 		miner.Stake = left
@@ -139,6 +163,12 @@ func (this *RefundManager) GetRefundStake(now uint64, minerId, account []byte, m
 	}
 
 	height := this.getRefundHeight(now, left, miner.Type, minerId, situation)
+	if common.CurrentRules().IsProposal030 {
+		this.addPending(now, minerId, pendingRefund{Height: height, Account: miner.Account, Value: refund}, accountdb)
+		if removed {
+			EvidenceManagerImpl.KeepPubkey(miner, now, accountdb)
+		}
+	}
 
 	this.logger.Debugf("getRefund end, minerId: %s, height: %d, money: %d", common.ToHex(minerId), height, refund)
 	return height, utility.Uint64ToBigInt(refund), miner.Account, nil
@@ -146,7 +176,7 @@ func (this *RefundManager) GetRefundStake(now uint64, minerId, account []byte, m
 
 func (this *RefundManager) getRefundHeight(now, left uint64, minerType byte, minerId []byte, situation string) uint64 {
 	height := uint64(0)
-	if common.IsProposal012() {
+	if common.CurrentRules().IsProposal012 {
 		return now + refundHeight
 	}
 
@@ -178,16 +208,87 @@ func (this *RefundManager) getRefundHeight(now, left uint64, minerType byte, min
 		height = RewardCalculatorImpl.NextRewardHeight(now) + common.GetRefundBlocks()
 	}
 
-	if common.IsProposal004() && height <= 0 {
+	if common.CurrentRules().IsProposal004 && height <= 0 {
 		height = now + common.GetRefundBlocks()*100
 	}
 
-	if common.LocalChainConfig.Proposal011Block == now {
+	if common.LocalChainConfig.Forks[common.Proposal011] == now {
 		height = height - 50
 	}
 	return height
 }
 
+// Slash burns the percent of the stake refunds of the miner not moved yet at the height
+func (this *RefundManager) Slash(minerId []byte, percent, height uint64, accountdb *account.AccountDB) uint64 {
+	pendings := this.getPendings(height, minerId, accountdb)
+	if 0 == len(pendings) {
+		return 0
+	}
+
+	slashed := uint64(0)
+	for i := range pendings {
+		delta := pendings[i].Value * percent / 100
+		if 0 == delta {
+			continue
+		}
+
+		address := this.generateSlashedAddress(pendings[i].Height)
+		key := common.BytesToAddress(pendings[i].Account).Bytes()
+		existed := new(big.Int).SetBytes(accountdb.GetData(address, key))
+		existed.Add(existed, utility.Uint64ToBigInt(delta))
+		accountdb.SetData(address, key, existed.Bytes())
+
+		pendings[i].Value -= delta
+		slashed += delta
+	}
+	this.setPendings(minerId, pendings, accountdb)
+	this.logger.Warnf("slash refunds, minerId: %s, height: %d, slashed: %d", common.ToHex(minerId), height, slashed)
+	return slashed
+}
+
+func (this *RefundManager) addPending(now uint64, minerId []byte, pending pendingRefund, accountdb *account.AccountDB) {
+	pendings := this.getPendings(now, minerId, accountdb)
+	pendings = append(pendings, pending)
+	this.setPendings(minerId, pendings, accountdb)
+}
+
+// getPendings returns the refunds of the miner not moved yet at the height
+func (this *RefundManager) getPendings(height uint64, minerId []byte, accountdb *account.AccountDB) []pendingRefund {
+	data := accountdb.GetData(pendingRefundAddress, minerId)
+	if 0 == len(data) {
+		return nil
+	}
+
+	var pendings []pendingRefund
+	if err := json.Unmarshal(data, &pendings); nil != err {
+		this.logger.Errorf("fail to unmarshal pending refunds, minerId: %s, %s", common.ToHex(minerId), err.Error())
+		return nil
+	}
+
+	result := pendings[:0]
+	for _, pending := range pendings {
+		if pending.Height >= height {
+			result = append(result, pending)
+		}
+	}
+	return result
+}
+
+func (this *RefundManager) setPendings(minerId []byte, pendings []pendingRefund, accountdb *account.AccountDB) {
+	if 0 == len(pendings) {
+		accountdb.RemoveData(pendingRefundAddress, minerId)
+		return
+	}
+
+	data, _ := json.Marshal(pendings)
+	accountdb.SetData(pendingRefundAddress, minerId, data)
+}
+
+func (refund *RefundManager) generateSlashedAddress(height uint64) common.Address {
+	keyString := slashedRefundPrefix + strconv.FormatUint(height, 10)
+	return common.BytesToAddress(common.Sha256(utility.StrToBytes(keyString)))
+}
+
 type DismissHeightList []uint64
 
 func (c DismissHeightList) Len() int {
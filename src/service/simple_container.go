@@ -20,7 +20,11 @@ import (
 	"com.tuntun.rangers/node/src/common"
 	"com.tuntun.rangers/node/src/middleware/db"
 	"com.tuntun.rangers/node/src/middleware/types"
-	"github.com/gogf/gf/container/gmap"
+	"com.tuntun.rangers/node/src/storage/account"
+	"container/heap"
+	"encoding/json"
+	"math/big"
+	"sort"
 	"sync"
 	"time"
 )
@@ -28,6 +32,11 @@ import (
 const (
 	expiredRing     = 5
 	txCycleInterval = time.Minute * 1
+
+	// max nonce ordered txs kept in pool for one account
+	accountSlots = 64
+	// min price bump in percent to replace a pending tx with the same nonce
+	priceBump = 10
 )
 
 var (
@@ -35,15 +44,34 @@ var (
 	pendingTxListPrefix = "tx_list_"
 )
 
+// simpleContainer keeps the received txs.
+// Json rpc txs(RequestId is 0) are kept per account and ordered by nonce,
+// gateway txs are ordered by RequestId.
+// Json rpc txs whose nonce falls behind the state are kept as stale until packed,
+// so that they get a failed receipt on chain.
 type simpleContainer struct {
 	limit int
-	data  *gmap.ListMap
 	db    db.Database
 
+	lock     sync.RWMutex
+	seq      uint64
+	all      map[common.Hash]*pooledTx
+	accounts map[string]*accountTxs
+	gateway  map[common.Hash]*pooledTx
+	stale    map[common.Hash]*pooledTx
+
 	txAnnualRingMap sync.Map
 	txCycleTicker   *time.Ticker
 }
 
+type pooledTx struct {
+	tx    *types.Transaction
+	price *big.Int
+	gas   uint64
+	// arrival order, break price tie
+	seq uint64
+}
+
 func newSimpleContainer(l int) *simpleContainer {
 	db, err := db.NewDatabase(pendingTxListPrefix)
 	if err != nil {
@@ -51,9 +79,12 @@ func newSimpleContainer(l int) *simpleContainer {
 	}
 
 	c := &simpleContainer{
-		data:  gmap.NewListMap(true),
-		limit: l,
-		db:    db,
+		limit:    l,
+		db:       db,
+		all:      make(map[common.Hash]*pooledTx),
+		accounts: make(map[string]*accountTxs),
+		gateway:  make(map[common.Hash]*pooledTx),
+		stale:    make(map[common.Hash]*pooledTx),
 
 		txAnnualRingMap: sync.Map{},
 		txCycleTicker:   time.NewTicker(txCycleInterval),
@@ -70,44 +101,283 @@ func (c *simpleContainer) Close() {
 }
 
 func (c *simpleContainer) Len() int {
-	return c.data.Size()
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return len(c.all)
 }
 
 func (c *simpleContainer) isFull() bool {
-	return c.data.Size() >= c.limit
+	return c.Len() >= c.limit
 }
 
 func (c *simpleContainer) contains(key common.Hash) bool {
-	return c.data.Contains(key)
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	_, ok := c.all[key]
+	return ok
 }
 
 func (c *simpleContainer) get(key common.Hash) *types.Transaction {
-	item := c.data.Get(key)
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	item := c.all[key]
 	if nil == item {
 		return nil
 	}
 
-	return item.(*types.Transaction)
+	return item.tx
 }
 
+// asSlice returns all txs in arrival order
 func (c *simpleContainer) asSlice() []interface{} {
-	return c.data.Values()
+	c.lock.RLock()
+	items := make([]*pooledTx, 0, len(c.all))
+	for _, item := range c.all {
+		items = append(items, item)
+	}
+	c.lock.RUnlock()
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].seq < items[j].seq
+	})
+	result := make([]interface{}, len(items))
+	for i, item := range items {
+		result[i] = item.tx
+	}
+	return result
 }
 
-func (c *simpleContainer) push(tx *types.Transaction) {
-	if c.data.Size() < c.limit {
-		c.data.Set(tx.Hash, tx)
-		c.txAnnualRingMap.Store(tx.Hash, uint64(0))
-		//c.flush()
+// push adds tx into container.
+// nonce is the next nonce of tx source in the latest state.
+// A tx with the same source and nonce as a pending one replaces it only if it pays priceBump percent more.
+// When container is full, the lowest priced tx is evicted if the new one pays more.
+func (c *simpleContainer) push(tx *types.Transaction, nonce uint64) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.all[tx.Hash]; ok {
+		return ErrExist
+	}
+	item := &pooledTx{tx: tx, price: txPrice(tx), gas: tx.GasLimit(), seq: c.seq}
+	c.seq++
+
+	if 0 != tx.RequestId {
+		if len(c.all) >= c.limit {
+			victim := c.cheapest()
+			if nil == victim {
+				return ErrPoolFull
+			}
+			c.drop(victim, "evicted")
+		}
+		c.gateway[tx.Hash] = item
+		c.store(item)
+		return nil
+	}
+
+	list := c.accounts[tx.Source]
+	if nil == list {
+		list = newAccountTxs(nonce)
+		c.accounts[tx.Source] = list
+	}
+	c.markStale(list.reset(nonce))
+	if tx.Nonce < list.nonce {
+		c.removeAccountIfEmpty(tx.Source)
+		return ErrNonceTooLow
+	}
+
+	if old := list.get(tx.Nonce); nil != old {
+		threshold := new(big.Int).Mul(old.price, big.NewInt(100+priceBump))
+		threshold.Div(threshold, big.NewInt(100))
+		if item.price.Cmp(threshold) < 0 {
+			return ErrReplaceUnderpriced
+		}
+		list.put(item)
+		c.discard(old, "replaced")
+		c.store(item)
+		return nil
+	}
+
+	if list.len() >= accountSlots {
+		c.removeAccountIfEmpty(tx.Source)
+		return ErrAccountSlotsExceeded
+	}
+	if len(c.all) >= c.limit {
+		victim := c.cheapest()
+		if nil == victim || victim.price.Cmp(item.price) >= 0 {
+			c.removeAccountIfEmpty(tx.Source)
+			return ErrUnderpriced
+		}
+		c.drop(victim, "evicted")
+	}
+
+	// victim may be the last tx of this account
+	if nil == c.accounts[tx.Source] {
+		c.accounts[tx.Source] = list
 	}
+	list.put(item)
+	c.store(item)
+	return nil
 }
 
 func (c *simpleContainer) remove(txHashList []interface{}) {
-	c.data.Removes(txHashList)
-	for _, item := range txHashList {
-		c.txAnnualRingMap.Delete(item)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, hash := range txHashList {
+		item, ok := c.all[hash.(common.Hash)]
+		if !ok {
+			continue
+		}
+		c.drop(item, "removed")
+	}
+}
+
+// pack selects at most limit txs for a new block, whose gas limits sum up to gasLimit at most.
+// Json rpc txs executable against stateDB are picked by price across accounts and by nonce within an account,
+// then gateway txs fill the rest by RequestId.
+func (c *simpleContainer) pack(stateDB *account.AccountDB, limit int, gasLimit uint64) []*types.Transaction {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	result := make([]*types.Transaction, 0)
+	staleTxs := make([]*pooledTx, 0)
+	cursors := make(txPriceHeap, 0, len(c.accounts))
+	for source, list := range c.accounts {
+		if nil != stateDB {
+			c.markStale(list.reset(stateDB.GetNonce(common.HexToAddress(source))))
+			if c.removeAccountIfEmpty(source) {
+				continue
+			}
+		}
+		if 0 != len(list.pending) {
+			cursors = append(cursors, list.pending)
+		}
+	}
+	// stale txs go first, they only get a failed receipt
+	for _, item := range c.stale {
+		staleTxs = append(staleTxs, item)
+	}
+	sort.Slice(staleTxs, func(i, j int) bool {
+		return staleTxs[i].seq < staleTxs[j].seq
+	})
+	for _, item := range staleTxs {
+		if len(result) >= limit {
+			return result
+		}
+		if item.gas > gasLimit {
+			continue
+		}
+		gasLimit -= item.gas
+		result = append(result, item.tx)
+	}
+
+	heap.Init(&cursors)
+	for cursors.Len() > 0 && len(result) < limit {
+		txs := cursors[0]
+		if txs[0].gas > gasLimit {
+			// the later txs of the account can not go before this one
+			heap.Pop(&cursors)
+			continue
+		}
+		gasLimit -= txs[0].gas
+		result = append(result, txs[0].tx)
+		if 1 == len(txs) {
+			heap.Pop(&cursors)
+		} else {
+			cursors[0] = txs[1:]
+			heap.Fix(&cursors, 0)
+		}
+	}
+
+	if len(result) >= limit {
+		return result
+	}
+	gatewayTxs := make([]*pooledTx, 0, len(c.gateway))
+	for _, item := range c.gateway {
+		gatewayTxs = append(gatewayTxs, item)
+	}
+	sort.Slice(gatewayTxs, func(i, j int) bool {
+		return gatewayTxs[i].tx.RequestId < gatewayTxs[j].tx.RequestId
+	})
+	for _, item := range gatewayTxs {
+		if len(result) >= limit {
+			break
+		}
+		if item.gas > gasLimit {
+			// the gateway txs are executed in the order of the request id
+			break
+		}
+		gasLimit -= item.gas
+		result = append(result, item.tx)
+	}
+	return result
+}
+
+func (c *simpleContainer) store(item *pooledTx) {
+	c.all[item.tx.Hash] = item
+	c.txAnnualRingMap.Store(item.tx.Hash, uint64(0))
+}
+
+// markStale keeps the txs detached from their account list until they are packed
+func (c *simpleContainer) markStale(items []*pooledTx) {
+	for _, item := range items {
+		c.stale[item.tx.Hash] = item
+		txPoolLogger.Debugf("stale tx:%s, source:%s, nonce:%d", item.tx.Hash.String(), item.tx.Source, item.tx.Nonce)
+	}
+}
+
+// discard forgets a tx which is already detached from its account list
+func (c *simpleContainer) discard(item *pooledTx, reason string) {
+	delete(c.all, item.tx.Hash)
+	c.txAnnualRingMap.Delete(item.tx.Hash)
+	txPoolLogger.Debugf("%s tx:%s, source:%s, nonce:%d, price:%s", reason, item.tx.Hash.String(), item.tx.Source, item.tx.Nonce, item.price.String())
+}
+
+func (c *simpleContainer) drop(item *pooledTx, reason string) {
+	tx := item.tx
+	if 0 != tx.RequestId {
+		delete(c.gateway, tx.Hash)
+	} else if _, ok := c.stale[tx.Hash]; ok {
+		delete(c.stale, tx.Hash)
+	} else if list := c.accounts[tx.Source]; nil != list {
+		if current := list.get(tx.Nonce); current == item {
+			list.remove(tx.Nonce)
+		}
+		c.removeAccountIfEmpty(tx.Source)
 	}
-	//c.flush()
+	c.discard(item, reason)
+}
+
+func (c *simpleContainer) removeAccountIfEmpty(source string) bool {
+	list := c.accounts[source]
+	if nil != list && 0 == list.len() {
+		delete(c.accounts, source)
+		return true
+	}
+	return false
+}
+
+// cheapest returns the lowest priced json rpc tx, the latest one wins a tie.
+// Gateway txs are never evicted.
+func (c *simpleContainer) cheapest() *pooledTx {
+	var result *pooledTx
+	for hash, item := range c.all {
+		if _, ok := c.gateway[hash]; ok {
+			continue
+		}
+		if nil == result {
+			result = item
+			continue
+		}
+		cmp := item.price.Cmp(result.price)
+		if cmp < 0 || (0 == cmp && item.seq > result.seq) {
+			result = item
+		}
+	}
+	return result
 }
 
 //
@@ -124,25 +394,6 @@ func (c *simpleContainer) remove(txHashList []interface{}) {
 //	}
 //}
 
-//func (c *simpleContainer) loadPendingTxList() {
-//	data, _ := c.db.Get(pendingTxListKey)
-//	if data == nil {
-//		return
-//	}
-//
-//	var pendingTxList []*types.Transaction
-//	err := json.Unmarshal(data, &pendingTxList)
-//	if err != nil {
-//		txPoolLogger.Error("json unmarshal pending tx list error:%s", err.Error())
-//		return
-//	}
-//	for _, item := range pendingTxList {
-//		c.data.Set(item.Hash, item)
-//		c.txAnnualRingMap.Store(item.Hash, uint64(0))
-//		txPoolLogger.Debugf("load pending tx:%s", item.Hash.String())
-//	}
-//}
-
 func (c *simpleContainer) loop() {
 	for {
 		select {
@@ -171,3 +422,137 @@ func (c *simpleContainer) growRing() {
 		c.remove(expiredTxHashList)
 	}
 }
+
+// accountTxs keeps json rpc txs of one account.
+// pending txs are executable one by one from nonce, queued txs wait for the nonce gap to be filled.
+type accountTxs struct {
+	nonce   uint64
+	pending []*pooledTx
+	queued  map[uint64]*pooledTx
+}
+
+func newAccountTxs(nonce uint64) *accountTxs {
+	return &accountTxs{nonce: nonce, pending: make([]*pooledTx, 0), queued: make(map[uint64]*pooledTx)}
+}
+
+func (a *accountTxs) len() int {
+	return len(a.pending) + len(a.queued)
+}
+
+func (a *accountTxs) get(nonce uint64) *pooledTx {
+	if nonce >= a.nonce && nonce < a.nonce+uint64(len(a.pending)) {
+		return a.pending[nonce-a.nonce]
+	}
+	return a.queued[nonce]
+}
+
+// put adds or replaces the tx with the same nonce
+func (a *accountTxs) put(item *pooledTx) {
+	nonce := item.tx.Nonce
+	if nonce >= a.nonce && nonce < a.nonce+uint64(len(a.pending)) {
+		a.pending[nonce-a.nonce] = item
+		return
+	}
+	a.queued[nonce] = item
+	a.promote()
+}
+
+// promote moves queued txs which become executable into pending
+func (a *accountTxs) promote() {
+	for {
+		next := a.nonce + uint64(len(a.pending))
+		item, ok := a.queued[next]
+		if !ok {
+			return
+		}
+		delete(a.queued, next)
+		a.pending = append(a.pending, item)
+	}
+}
+
+// reset rebuilds the lists from the given state nonce and returns txs whose nonce is too low
+func (a *accountTxs) reset(nonce uint64) []*pooledTx {
+	if nonce == a.nonce {
+		return nil
+	}
+
+	stale := make([]*pooledTx, 0)
+	items := a.pending
+	for _, item := range a.queued {
+		items = append(items, item)
+	}
+	a.nonce = nonce
+	a.pending = make([]*pooledTx, 0)
+	a.queued = make(map[uint64]*pooledTx)
+	for _, item := range items {
+		if item.tx.Nonce < nonce {
+			stale = append(stale, item)
+		} else {
+			a.queued[item.tx.Nonce] = item
+		}
+	}
+	a.promote()
+	return stale
+}
+
+// remove deletes the tx with the given nonce.
+// Removing the first pending tx means it is executed, otherwise the following pending txs are demoted.
+func (a *accountTxs) remove(nonce uint64) {
+	if nonce < a.nonce || nonce >= a.nonce+uint64(len(a.pending)) {
+		delete(a.queued, nonce)
+		return
+	}
+
+	index := nonce - a.nonce
+	if 0 == index {
+		a.pending = a.pending[1:]
+		a.nonce++
+		return
+	}
+	for _, item := range a.pending[index+1:] {
+		a.queued[item.tx.Nonce] = item
+	}
+	a.pending = a.pending[:index]
+}
+
+// txPriceHeap orders the pending lists by the price of their first tx
+type txPriceHeap [][]*pooledTx
+
+func (h txPriceHeap) Len() int { return len(h) }
+func (h txPriceHeap) Less(i, j int) bool {
+	cmp := h[i][0].price.Cmp(h[j][0].price)
+	if 0 != cmp {
+		return cmp > 0
+	}
+	return h[i][0].seq < h[j][0].seq
+}
+func (h txPriceHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *txPriceHeap) Push(x interface{}) {
+	*h = append(*h, x.([]*pooledTx))
+}
+
+func (h *txPriceHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[0 : n-1]
+	return x
+}
+
+// txPrice returns the gas price a tx pays, contract txs declare it in tx data
+func txPrice(tx *types.Transaction) *big.Int {
+	if !types.IsContractTx(tx.Type) {
+		return types.DefaultGasPrice
+	}
+
+	var data types.ContractData
+	if err := json.Unmarshal([]byte(tx.Data), &data); err != nil || data.GasPrice == "" {
+		return types.DefaultGasPrice
+	}
+	price, ok := new(big.Int).SetString(data.GasPrice, 10)
+	if !ok || price.Sign() <= 0 {
+		return types.DefaultGasPrice
+	}
+	return price
+}
@@ -2,9 +2,14 @@ package service
 
 import (
 	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/middleware/db"
 	"com.tuntun.rangers/node/src/middleware/log"
 	"com.tuntun.rangers/node/src/middleware/types"
+	"com.tuntun.rangers/node/src/storage/account"
 	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/big"
 	"strconv"
 	"testing"
 	"time"
@@ -24,8 +29,8 @@ func TestSimpleContainer(t *testing.T) {
 	source2 := "22222"
 	tx2 := &types.Transaction{Hash: hash2, Source: source2}
 
-	container.push(tx1)
-	container.push(tx2)
+	container.push(tx1, 0)
+	container.push(tx2, 0)
 	//assert.Equal(t, container.Len(), 2)
 
 	//assert.Equal(t, true, container.contains(hash1))
@@ -39,7 +44,7 @@ func TestSimpleContainer(t *testing.T) {
 	hash3 := common.HexToHash("0xfa6be88da0fd27716900859a633c9085d664b4e07053f2c1361d6a5d6f933333")
 	source3 := "33333"
 	tx3 := &types.Transaction{Hash: hash3, Source: source3}
-	container.push(tx3)
+	container.push(tx3, 0)
 	txPoolLogger.Debugf("after push tx3")
 	container.dump()
 
@@ -53,7 +58,7 @@ func TestSimpleContainer(t *testing.T) {
 	hash4 := common.HexToHash("0xfa6be88da0fd27716900859a633c9085d664b4e07053f2c1361d6a5d6f944444")
 	source4 := "44444"
 	tx4 := &types.Transaction{Hash: hash4, Source: source4}
-	container.push(tx4)
+	container.push(tx4, 0)
 	txPoolLogger.Debugf("after push tx4")
 	container.dump()
 
@@ -61,7 +66,7 @@ func TestSimpleContainer(t *testing.T) {
 }
 
 func (c *simpleContainer) dump() {
-	txPoolLogger.Debugf("data len:%d", c.data.Size())
+	txPoolLogger.Debugf("data len:%d", c.Len())
 	data := c.asSlice()
 	for _, item := range data {
 		txPoolLogger.Debugf("hash:%s", item.(*types.Transaction).Hash.String())
@@ -84,3 +89,170 @@ func (c *simpleContainer) dump() {
 	}
 	txPoolLogger.Debugf("\n")
 }
+
+func newTestContainer(limit int) *simpleContainer {
+	txPoolLogger = log.GetLoggerByIndex(log.TxPoolLogConfig, strconv.Itoa(common.InstanceIndex))
+	return &simpleContainer{
+		limit:    limit,
+		all:      make(map[common.Hash]*pooledTx),
+		accounts: make(map[string]*accountTxs),
+		gateway:  make(map[common.Hash]*pooledTx),
+		stale:    make(map[common.Hash]*pooledTx),
+	}
+}
+
+func newTestStateDB() *account.AccountDB {
+	memdb, _ := db.NewMemDatabase()
+	state, _ := account.NewAccountDB(common.Hash{}, account.NewDatabase(memdb))
+	return state
+}
+
+func newPricedTx(source string, nonce uint64, price int64, hash string) *types.Transaction {
+	data, _ := json.Marshal(types.ContractData{GasPrice: strconv.FormatInt(price, 10)})
+	return &types.Transaction{Type: types.TransactionTypeETHTX, Source: source, Nonce: nonce, Data: string(data), Hash: common.HexToHash(hash)}
+}
+
+func TestSimpleContainer_PackByPrice(t *testing.T) {
+	container := newTestContainer(100)
+	state := newTestStateDB()
+
+	assert.Nil(t, container.push(newPricedTx("0x0001", 0, 1, "0xa0"), 0))
+	assert.Nil(t, container.push(newPricedTx("0x0001", 1, 1, "0xa1"), 0))
+	assert.Nil(t, container.push(newPricedTx("0x0002", 0, 5, "0xb0"), 0))
+	assert.Nil(t, container.push(newPricedTx("0x0003", 0, 3, "0xc0"), 0))
+	assert.Nil(t, container.push(&types.Transaction{Source: "0x0004", RequestId: 2, Hash: common.HexToHash("0xd2")}, 0))
+	assert.Nil(t, container.push(&types.Transaction{Source: "0x0004", RequestId: 1, Hash: common.HexToHash("0xd1")}, 0))
+
+	txs := container.pack(state, 100, math.MaxUint64)
+	expected := []string{"0xb0", "0xc0", "0xa0", "0xa1", "0xd1", "0xd2"}
+	assert.Equal(t, len(expected), len(txs))
+	for i, hash := range expected {
+		assert.Equal(t, common.HexToHash(hash), txs[i].Hash)
+	}
+
+	txs = container.pack(state, 2, math.MaxUint64)
+	assert.Equal(t, 2, len(txs))
+	assert.Equal(t, common.HexToHash("0xb0"), txs[0].Hash)
+	assert.Equal(t, common.HexToHash("0xc0"), txs[1].Hash)
+}
+
+func newGasTx(source string, nonce uint64, price int64, gasLimit uint64, hash string) *types.Transaction {
+	data, _ := json.Marshal(types.ContractData{GasPrice: strconv.FormatInt(price, 10), GasLimit: strconv.FormatUint(gasLimit, 10)})
+	return &types.Transaction{Type: types.TransactionTypeETHTX, Source: source, Nonce: nonce, Data: string(data), Hash: common.HexToHash(hash)}
+}
+
+func TestSimpleContainer_PackByGasLimit(t *testing.T) {
+	container := newTestContainer(100)
+	state := newTestStateDB()
+
+	assert.Nil(t, container.push(newGasTx("0x0001", 0, 5, 600, "0xa0"), 0))
+	assert.Nil(t, container.push(newGasTx("0x0001", 1, 5, 100, "0xa1"), 0))
+	assert.Nil(t, container.push(newGasTx("0x0002", 0, 3, 350, "0xb0"), 0))
+	assert.Nil(t, container.push(newGasTx("0x0003", 0, 1, 200, "0xc0"), 0))
+	assert.Nil(t, container.push(&types.Transaction{Source: "0x0004", RequestId: 1, Hash: common.HexToHash("0xd1")}, 0))
+
+	// 0xb0 does not fit after 0xa0 and 0xa1, the cheaper 0xc0 still does
+	txs := container.pack(state, 100, 1000)
+	expected := []string{"0xa0", "0xa1", "0xc0", "0xd1"}
+	assert.Equal(t, len(expected), len(txs))
+	for i, hash := range expected {
+		assert.Equal(t, common.HexToHash(hash), txs[i].Hash)
+	}
+
+	// the later txs of an account wait for the one over the gas limit
+	txs = container.pack(state, 100, 500)
+	expected = []string{"0xb0", "0xd1"}
+	assert.Equal(t, len(expected), len(txs))
+	for i, hash := range expected {
+		assert.Equal(t, common.HexToHash(hash), txs[i].Hash)
+	}
+}
+
+func TestSimpleContainer_NonceGap(t *testing.T) {
+	container := newTestContainer(100)
+	state := newTestStateDB()
+
+	assert.Nil(t, container.push(newPricedTx("0x0001", 0, 1, "0xa0"), 0))
+	assert.Nil(t, container.push(newPricedTx("0x0001", 2, 1, "0xa2"), 0))
+	assert.Equal(t, 1, len(container.pack(state, 100, math.MaxUint64)))
+
+	// fill the gap, queued tx is promoted
+	assert.Nil(t, container.push(newPricedTx("0x0001", 1, 1, "0xa1"), 0))
+	assert.Equal(t, 3, len(container.pack(state, 100, math.MaxUint64)))
+
+	// nonce 0 executed by another tx, 0xa0 is packed first to get a failed receipt
+	state.SetNonce(common.HexToAddress("0x0001"), 1)
+	txs := container.pack(state, 100, math.MaxUint64)
+	assert.Equal(t, 3, len(txs))
+	assert.Equal(t, common.HexToHash("0xa0"), txs[0].Hash)
+	assert.Equal(t, common.HexToHash("0xa1"), txs[1].Hash)
+
+	container.remove([]interface{}{common.HexToHash("0xa0")})
+	assert.False(t, container.contains(common.HexToHash("0xa0")))
+	txs = container.pack(state, 100, math.MaxUint64)
+	assert.Equal(t, 2, len(txs))
+	assert.Equal(t, common.HexToHash("0xa1"), txs[0].Hash)
+
+	assert.Equal(t, ErrNonceTooLow, container.push(newPricedTx("0x0001", 0, 1, "0xa3"), 1))
+}
+
+func TestSimpleContainer_Replace(t *testing.T) {
+	container := newTestContainer(100)
+	state := newTestStateDB()
+
+	assert.Nil(t, container.push(newPricedTx("0x0001", 0, 100, "0xa0"), 0))
+	assert.Equal(t, ErrReplaceUnderpriced, container.push(newPricedTx("0x0001", 0, 109, "0xa1"), 0))
+	assert.Nil(t, container.push(newPricedTx("0x0001", 0, 110, "0xa2"), 0))
+
+	assert.Equal(t, 1, container.Len())
+	assert.False(t, container.contains(common.HexToHash("0xa0")))
+	txs := container.pack(state, 100, math.MaxUint64)
+	assert.Equal(t, 1, len(txs))
+	assert.Equal(t, common.HexToHash("0xa2"), txs[0].Hash)
+}
+
+func TestSimpleContainer_AccountSlots(t *testing.T) {
+	container := newTestContainer(1000)
+
+	for i := 0; i < accountSlots; i++ {
+		hash := "0x" + new(big.Int).SetInt64(int64(i+1)).Text(16)
+		assert.Nil(t, container.push(newPricedTx("0x0001", uint64(i), 1, hash), 0))
+	}
+	assert.Equal(t, ErrAccountSlotsExceeded, container.push(newPricedTx("0x0001", accountSlots, 1, "0xffff"), 0))
+	assert.Nil(t, container.push(newPricedTx("0x0002", 0, 1, "0xeeee"), 0))
+}
+
+func TestSimpleContainer_Evict(t *testing.T) {
+	container := newTestContainer(2)
+	state := newTestStateDB()
+
+	assert.Nil(t, container.push(newPricedTx("0x0001", 0, 2, "0xa0"), 0))
+	assert.Nil(t, container.push(newPricedTx("0x0002", 0, 3, "0xb0"), 0))
+	assert.True(t, container.isFull())
+
+	assert.Equal(t, ErrUnderpriced, container.push(newPricedTx("0x0003", 0, 2, "0xc0"), 0))
+	assert.Nil(t, container.push(newPricedTx("0x0003", 0, 4, "0xc1"), 0))
+	assert.Equal(t, 2, container.Len())
+	assert.False(t, container.contains(common.HexToHash("0xa0")))
+
+	txs := container.pack(state, 100, math.MaxUint64)
+	assert.Equal(t, 2, len(txs))
+	assert.Equal(t, common.HexToHash("0xc1"), txs[0].Hash)
+	assert.Equal(t, common.HexToHash("0xb0"), txs[1].Hash)
+}
+
+func TestSimpleContainer_EvictGateway(t *testing.T) {
+	container := newTestContainer(2)
+
+	assert.Nil(t, container.push(&types.Transaction{Source: "0x0004", RequestId: 1, Hash: common.HexToHash("0xd1")}, 0))
+	assert.Nil(t, container.push(newPricedTx("0x0001", 0, 2, "0xa0"), 0))
+
+	// the json rpc tx is evicted even if it pays more
+	assert.Nil(t, container.push(&types.Transaction{Source: "0x0004", RequestId: 2, Hash: common.HexToHash("0xd2")}, 0))
+	assert.False(t, container.contains(common.HexToHash("0xa0")))
+	assert.True(t, container.contains(common.HexToHash("0xd1")))
+
+	assert.Equal(t, ErrPoolFull, container.push(&types.Transaction{Source: "0x0004", RequestId: 3, Hash: common.HexToHash("0xd3")}, 0))
+	assert.Equal(t, ErrUnderpriced, container.push(newPricedTx("0x0002", 0, 100, "0xb0"), 0))
+	assert.Equal(t, 2, container.Len())
+}
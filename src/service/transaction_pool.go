@@ -21,15 +21,17 @@ import (
 	"com.tuntun.rangers/node/src/eth_tx"
 	"com.tuntun.rangers/node/src/middleware"
 	"com.tuntun.rangers/node/src/middleware/db"
-	"com.tuntun.rangers/node/src/middleware/mysql"
+	"com.tuntun.rangers/node/src/middleware/logindex"
+	"com.tuntun.rangers/node/src/middleware/metrics"
+	"com.tuntun.rangers/node/src/middleware/notify"
 	"com.tuntun.rangers/node/src/middleware/types"
 	"com.tuntun.rangers/node/src/storage/account"
-	"com.tuntun.rangers/node/src/storage/rlp"
 	"com.tuntun.rangers/node/src/utility"
 	"encoding/json"
 	"errors"
 	"fmt"
 	lru "github.com/hashicorp/golang-lru"
+	"math"
 	"sort"
 )
 
@@ -55,6 +57,16 @@ var (
 	ErrEvicted = errors.New("error transaction already exist in pool")
 
 	ErrIllegal = errors.New("illegal transaction")
+
+	ErrNonceTooLow = errors.New("nonce too low")
+
+	ErrPoolFull = errors.New("transaction pool is full")
+
+	ErrUnderpriced = errors.New("transaction underpriced")
+
+	ErrReplaceUnderpriced = errors.New("replacement transaction underpriced")
+
+	ErrAccountSlotsExceeded = errors.New("too many pending transactions of the account")
 )
 
 type ExecutedReceipt struct {
@@ -120,6 +132,9 @@ func initTransactionPool() {
 	if nil == txpoolInstance {
 		txpoolInstance = newTransactionPool()
 	}
+	metrics.NewGaugeFunc("txpool_size", "Number of the transactions received in the pool", func() float64 {
+		return float64(txpoolInstance.TxNum())
+	})
 }
 
 func GetTransactionPool() TransactionPool {
@@ -179,6 +194,9 @@ func (pool *TxPool) AddTransaction(tx *types.Transaction) (bool, error) {
 	b, err := pool.add(tx)
 	if nil == err {
 		pool.refreshGateNonce(tx)
+		if nil != notify.BUS {
+			notify.BUS.Publish(notify.NewPendingTxNotify, &notify.NewPendingTxNotifyMessage{Transactions: []*types.Transaction{tx}})
+		}
 	}
 	return b, err
 }
@@ -187,7 +205,7 @@ func (pool *TxPool) MarkExecuted(header *types.BlockHeader, receipts types.Recei
 	txHashList := make([]interface{}, 0)
 
 	if receipts != nil && len(receipts) != 0 {
-		go mysql.InsertLogs(header.Height, receipts, header.Hash)
+		go logindex.GetLogIndex().InsertLogs(header.Height, receipts, header.Hash)
 
 		for i, receipt := range receipts {
 			hash := receipt.TxHash
@@ -246,7 +264,7 @@ func (pool *TxPool) UnMarkExecuted(block *types.Block) {
 		return
 	}
 
-	mysql.DeleteLogs(block.Header.Height, block.Header.Hash)
+	logindex.GetLogIndex().DeleteLogs(block.Header.Height, block.Header.Hash)
 
 	if evictedTxs != nil {
 		for _, hash := range evictedTxs {
@@ -254,9 +272,19 @@ func (pool *TxPool) UnMarkExecuted(block *types.Block) {
 		}
 	}
 
+	// latest state is not rolled back yet, use the nonces before the block instead
+	nonces := make(map[string]uint64)
+	for _, tx := range txs {
+		if 0 != tx.RequestId {
+			continue
+		}
+		if nonce, ok := nonces[tx.Source]; !ok || tx.Nonce < nonce {
+			nonces[tx.Source] = tx.Nonce
+		}
+	}
 	for _, tx := range txs {
 		pool.executed.Delete(tx.Hash.Bytes())
-		pool.add(tx)
+		pool.push(tx, nonces[tx.Source])
 	}
 }
 
@@ -337,28 +365,18 @@ func (p *TxPool) IsFull() bool {
 }
 
 func (pool *TxPool) PackForCast(height uint64, stateDB *account.AccountDB) []*types.Transaction {
-	packedTxs := make([]*types.Transaction, 0)
-
-	txs := pool.received.asSlice()
-	if 0 == len(txs) {
+	gasLimit := uint64(math.MaxUint64)
+	if common.CurrentRules().IsProposal029 {
+		gasLimit = common.BlockGasLimit
+	}
+	packedTxs := pool.received.pack(stateDB, txCountPerBlock, gasLimit)
+	if 0 == len(packedTxs) {
 		txPoolLogger.Debugf("packed no tx. height: %d", height)
 		return packedTxs
 	}
 
-	for _, tx := range txs {
-		packedTxs = append(packedTxs, tx.(*types.Transaction))
-	}
-	if common.IsProposal018() {
-		packedTxs = pool.checkNonce(packedTxs, stateDB)
-	}
-	if len(packedTxs) > txCountPerBlock {
-		packedTxs = packedTxs[:txCountPerBlock]
-	}
-	if 0 == len(packedTxs) {
-		txPoolLogger.Debugf("after check nonce ,packed no tx. height: %d", height)
-	} else {
-		txPoolLogger.Debugf("packed tx. height: %d. nonce from %d to %d. size: %d", height, packedTxs[0].RequestId, packedTxs[len(packedTxs)-1].RequestId, len(packedTxs))
-	}
+	sort.Sort(types.Transactions(packedTxs))
+	txPoolLogger.Debugf("packed tx. height: %d. nonce from %d to %d. size: %d", height, packedTxs[0].RequestId, packedTxs[len(packedTxs)-1].RequestId, len(packedTxs))
 	return packedTxs
 }
 
@@ -378,6 +396,12 @@ func (pool *TxPool) VerifyTransaction(tx *types.Transaction, height uint64) erro
 	if nil != err {
 		return err
 	}
+	if tx.Type == types.TransactionTypeEvidence {
+		err = verifyEvidenceReporter(tx)
+		if nil != err {
+			return err
+		}
+	}
 
 	txPoolLogger.Debugf("Verify tx success. hash: %s", tx.Hash.String())
 	return nil
@@ -388,7 +412,7 @@ func (pool *TxPool) ProcessFee(tx types.Transaction, accountDB *account.AccountD
 	balance := accountDB.GetBalance(addr)
 
 	fee := delta
-	if common.IsProposal026() {
+	if common.CurrentRules().IsProposal026 {
 		fee = delta026
 	}
 
@@ -406,15 +430,35 @@ func (pool *TxPool) add(tx *types.Transaction) (bool, error) {
 		return false, ErrNil
 	}
 
+	return pool.push(tx, pool.nextNonce(tx))
+}
+
+// push adds tx into received container, nonce is the next nonce of tx source
+func (pool *TxPool) push(tx *types.Transaction, nonce uint64) (bool, error) {
 	hash := tx.Hash
 	if pool.isTransactionExisted(hash) {
 		return false, ErrExist
 	}
-	pool.received.push(tx)
+	if err := pool.received.push(tx, nonce); err != nil {
+		txPoolLogger.Debugf("[pool]Refuse tx:%s. source:%s,nonce:%d,error:%s", tx.Hash.String(), tx.Source, tx.Nonce, err.Error())
+		return false, err
+	}
 	txPoolLogger.Debugf("[pool]Add tx:%s. global nonce: %d,source:%s,nonce:%d, After add,received size:%d", tx.Hash.String(), tx.RequestId, tx.Source, tx.Nonce, pool.received.Len())
 	return true, nil
 }
 
+// nextNonce returns the nonce of tx source in the latest state
+func (pool *TxPool) nextNonce(tx *types.Transaction) uint64 {
+	if 0 != tx.RequestId {
+		return 0
+	}
+	stateDB := middleware.AccountDBManagerInstance.GetLatestStateDB()
+	if nil == stateDB {
+		return 0
+	}
+	return stateDB.GetNonce(common.HexToAddress(tx.Source))
+}
+
 func (pool *TxPool) remove(txHashList []interface{}) {
 	pool.received.remove(txHashList)
 	for _, txHash := range txHashList {
@@ -493,6 +537,25 @@ func verifyTransactionSign(tx *types.Transaction) error {
 	return nil
 }
 
+// verifyEvidenceReporter refuses the evidences not sent by the registered miners reporting them,
+// since no fee is charged for evidences
+func verifyEvidenceReporter(tx *types.Transaction) error {
+	var evidence types.Evidence
+	if err := json.Unmarshal([]byte(tx.Data), &evidence); nil != err {
+		txPoolLogger.Errorf("Verify evidence tx error!Hash:%s,error:%s", tx.Hash.String(), err.Error())
+		return ErrIllegal
+	}
+	stateDB := middleware.AccountDBManagerInstance.GetLatestStateDB()
+	if nil == stateDB {
+		return nil
+	}
+	if err := EvidenceManagerImpl.VerifyReporter(&evidence, tx.Source, stateDB); nil != err {
+		txPoolLogger.Errorf("Verify evidence tx error!Hash:%s,error:%s", tx.Hash.String(), err.Error())
+		return ErrIllegal
+	}
+	return nil
+}
+
 func verifyETHTx(tx *types.Transaction, height uint64) error {
 	if tx == nil {
 		return ErrNil
@@ -500,12 +563,16 @@ func verifyETHTx(tx *types.Transaction, height uint64) error {
 	ethTx := new(eth_tx.Transaction)
 	var encodedTx utility.Bytes
 	encodedTx = common.FromHex(tx.ExtraData)
-	if err := rlp.DecodeBytes(encodedTx, ethTx); err != nil {
+	if err := ethTx.UnmarshalBinary(encodedTx); err != nil {
 		txPoolLogger.Errorf("Verify eth tx rlp error!error:%v", err)
 		return ErrIllegal
 	}
+	if ethTx.Type() != eth_tx.LegacyTxType && !common.LocalChainConfig.Rules(height).IsProposal031 {
+		txPoolLogger.Errorf("Verify eth tx error!tx:%s,error:%v", ethTx.Hash().String(), eth_tx.ErrTxTypeNotSupported)
+		return ErrIllegal
+	}
 
-	signer := eth_tx.NewEIP155Signer(common.GetChainId(height))
+	signer := eth_tx.NewLondonSigner(common.GetChainId(height))
 	sender, err := eth_tx.Sender(signer, ethTx)
 	if err != nil {
 		txPoolLogger.Errorf("Verify eth tx error!tx:%s,error:%v", ethTx.Hash().String(), err)
@@ -533,35 +600,3 @@ func compareTx(tx *types.Transaction, expectedTx *types.Transaction) bool {
 	}
 	return true
 }
-
-func (pool *TxPool) checkNonce(txList []*types.Transaction, stateDB *account.AccountDB) []*types.Transaction {
-	txs := types.Transactions(txList)
-	sort.Sort(txs)
-
-	packedTxs := make([]*types.Transaction, 0)
-	nonceMap := make(map[string]uint64, 0)
-	for _, tx := range txs {
-		if tx.RequestId == 0 { //only json rpc tx pre check nonce
-			expectedNonce, exist := nonceMap[tx.Source]
-			if !exist {
-				expectedNonce = stateDB.GetNonce(common.HexToAddress(tx.Source))
-				nonceMap[tx.Source] = expectedNonce
-			}
-
-			//nonce too low tx and repeat nonce tx will be packed into block and execute failed
-			if expectedNonce < tx.Nonce {
-				txPoolLogger.Debugf("nonce too high tx,skip pack.tx:%s,expected:%d,but:%d", tx.Hash.String(), expectedNonce, tx.Nonce)
-				continue
-			}
-			if expectedNonce == tx.Nonce {
-				nonceMap[tx.Source] = expectedNonce + 1
-			}
-		}
-
-		packedTxs = append(packedTxs, tx)
-		if len(packedTxs) >= txCountPerBlock {
-			break
-		}
-	}
-	return packedTxs
-}
@@ -173,7 +173,7 @@ func TestTxPool_PackForCast1(t *testing.T) {
 }
 
 // same address for 2 txs with same nonce
-// still pack 2 txs
+// the second one does not pay more, only pack 1 tx
 func TestTxPool_PackForCast2(t *testing.T) {
 	defer func() {
 		Close()
@@ -209,16 +209,17 @@ func TestTxPool_PackForCast2(t *testing.T) {
 		Hash:   common.HexToHash("0xbb"),
 		Nonce:  0,
 	}
-	txpoolInstance.AddTransaction(tx1)
+	_, err := txpoolInstance.AddTransaction(tx1)
+	assert.Equal(t, ErrReplaceUnderpriced, err)
 	txs1 := txpoolInstance.PackForCast(10000, state)
-	if 2 != len(txs1) {
+	if 1 != len(txs1) {
 		t.Fatal("no txs error")
 	}
 }
 
 // same address for 2 txs with same nonce
 // but different type
-// still pack 2 txs
+// the second one does not pay more, only pack 1 tx
 func TestTxPool_PackForCast3(t *testing.T) {
 	defer func() {
 		Close()
@@ -255,17 +256,18 @@ func TestTxPool_PackForCast3(t *testing.T) {
 		Nonce:  0,
 		Type:   1,
 	}
-	txpoolInstance.AddTransaction(tx1)
+	_, err := txpoolInstance.AddTransaction(tx1)
+	assert.Equal(t, ErrReplaceUnderpriced, err)
 	txs1 := txpoolInstance.PackForCast(10000, state)
-	if 2 != len(txs1) {
+	if 1 != len(txs1) {
 		t.Fatal("no txs error")
 	}
 }
 
 // 2 addresses
-// A has 2 tx
+// A has 2 tx with same nonce
 // B has 1 tx
-// pack 3 txs
+// pack 2 txs
 func TestTxPool_PackForCast4(t *testing.T) {
 	defer func() {
 		Close()
@@ -314,7 +316,7 @@ func TestTxPool_PackForCast4(t *testing.T) {
 	}
 	txpoolInstance.AddTransaction(tx2)
 	txs2 := txpoolInstance.PackForCast(10000, state)
-	if 3 != len(txs2) {
+	if 2 != len(txs2) {
 		t.Fatal("no txs error")
 	}
 }
@@ -379,7 +381,7 @@ func TestTxPool_PackForCast5(t *testing.T) {
 // 2 addresses
 // A has 3 tx nonce 0,1,2
 // B has 6 tx nonce 0,1,1,3,4,5
-// pack 6 txs
+// pack 5 txs
 func TestTxPool_PackForCast6(t *testing.T) {
 	defer func() {
 		Close()
@@ -462,15 +464,14 @@ func TestTxPool_PackForCast6(t *testing.T) {
 	txpoolInstance.AddTransaction(tx15)
 
 	txList := txpoolInstance.PackForCast(10000, state)
-	if 6 != len(txList) {
+	if 5 != len(txList) {
 		t.Fatal("packed tx count error")
 	}
 	assert.Equal(t, txList[0].Hash.String(), tx10.Hash.String())
 	assert.Equal(t, txList[1].Hash.String(), tx11.Hash.String())
-	assert.Equal(t, txList[2].Hash.String(), tx12.Hash.String())
-	assert.Equal(t, txList[3].Hash.String(), tx0.Hash.String())
-	assert.Equal(t, txList[4].Hash.String(), tx1.Hash.String())
-	assert.Equal(t, txList[5].Hash.String(), tx2.Hash.String())
+	assert.Equal(t, txList[2].Hash.String(), tx0.Hash.String())
+	assert.Equal(t, txList[3].Hash.String(), tx1.Hash.String())
+	assert.Equal(t, txList[4].Hash.String(), tx2.Hash.String())
 }
 
 func TestMockCastWithMixTxType(t *testing.T) {
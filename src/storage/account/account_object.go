@@ -276,9 +276,11 @@ func (ao *accountObject) deepCopy(db *AccountDB, onDirty func(addr common.Addres
 	}
 
 	accountObject.nftSet = ao.nftSet
+	accountObject.dirtyNFTSet = ao.dirtyNFTSet
 	accountObject.dirtyStorage = ao.dirtyStorage.Copy()
 	accountObject.cachedStorage = ao.dirtyStorage.Copy()
 	accountObject.suicided = ao.suicided
+	accountObject.touched = ao.touched
 	accountObject.deleted = ao.deleted
 	return accountObject
 }
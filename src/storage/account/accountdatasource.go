@@ -74,6 +74,11 @@ type Trie interface {
 	// NodeIterator returns an iterator that returns nodes of the trie. Iteration
 	// starts at the key after the given start key.
 	NodeIterator(startKey []byte) trie.NodeIterator
+
+	// Prove constructs a Merkle proof for key. The result contains all encoded nodes
+	// on the path to the value at key. The value itself is also included in the last
+	// node and can be retrieved by verifying the proof.
+	Prove(key []byte, fromLevel uint, proofDb xdb.Putter) error
 }
 
 // NewDatabase creates a backing store for state. The returned database
@@ -130,8 +135,7 @@ func (db *storageDB) OpenStorageTrie(addrHash, root common.Hash) (Trie, error) {
 func (db *storageDB) CopyTrie(t Trie) Trie {
 	switch t := t.(type) {
 	case *trie.Trie:
-		newTrie, _ := trie.NewTrie(t.Hash(), db.db)
-		return newTrie
+		return t.Copy()
 	default:
 		// this must not happen
 		panic(fmt.Errorf("unknown trie type %T", t))
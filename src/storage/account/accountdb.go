@@ -20,6 +20,7 @@ import (
 	crypto "com.tuntun.rangers/node/src/eth_crypto"
 	"com.tuntun.rangers/node/src/middleware/types"
 	"com.tuntun.rangers/node/src/utility"
+	"errors"
 	"fmt"
 	"math/big"
 	"sort"
@@ -141,6 +142,51 @@ func (adb *AccountDB) Reset(root common.Hash) error {
 	return nil
 }
 
+// Copy creates a deep, independent copy of the state.
+// Snapshots of the copied state cannot be applied to the copy.
+func (adb *AccountDB) Copy() *AccountDB {
+	adb.accountObjectsLock.Lock()
+	defer adb.accountObjectsLock.Unlock()
+
+	state := &AccountDB{
+		db:                  adb.db,
+		trie:                adb.db.CopyTrie(adb.trie),
+		accessList:          adb.accessList.Copy(),
+		accountObjects:      new(sync.Map),
+		accountObjectsDirty: make(map[common.Address]struct{}, len(adb.accountObjectsDirty)),
+		accountObjectsLock:  new(sync.Mutex),
+		refund:              adb.refund,
+		transientStorage:    adb.transientStorage.Copy(),
+		thash:               adb.thash,
+		bhash:               adb.bhash,
+		txIndex:             adb.txIndex,
+		logs:                make(map[common.Hash][]*types.Log, len(adb.logs)),
+		logSize:             adb.logSize,
+	}
+	adb.accountObjects.Range(func(key, value interface{}) bool {
+		object := value.(*accountObject)
+		// an object marked dirty does not notify again
+		onDirty := state.MarkAccountObjectDirty
+		if nil == object.onDirty {
+			onDirty = nil
+		}
+		state.accountObjects.Store(key, object.deepCopy(state, onDirty))
+		return true
+	})
+	for addr := range adb.accountObjectsDirty {
+		state.accountObjectsDirty[addr] = struct{}{}
+	}
+	for hash, logs := range adb.logs {
+		cpy := make([]*types.Log, len(logs))
+		for i, log := range logs {
+			cpy[i] = new(types.Log)
+			*cpy[i] = *log
+		}
+		state.logs[hash] = cpy
+	}
+	return state
+}
+
 func (adb *AccountDB) Clean() {
 	adb.accountObjects = new(sync.Map)
 	adb.accountObjectsLock = new(sync.Mutex)
@@ -216,6 +262,32 @@ func (adb *AccountDB) StorageTrie(a common.Address) Trie {
 	return cpy.updateTrie(adb.db)
 }
 
+// GetProof returns the Merkle proof for a given account.
+func (adb *AccountDB) GetProof(addr common.Address) ([][]byte, error) {
+	var proof proofList
+	err := adb.trie.Prove(addr[:], 0, &proof)
+	return proof, err
+}
+
+// GetStorageProof returns the Merkle proof for given storage slot.
+func (adb *AccountDB) GetStorageProof(a common.Address, key common.Hash) ([][]byte, error) {
+	var proof proofList
+	trie := adb.StorageTrie(a)
+	if trie == nil {
+		return proof, errors.New("storage trie for requested address does not exist")
+	}
+	err := trie.Prove(key.Bytes(), 0, &proof)
+	return proof, err
+}
+
+// proofList collects the nodes of a Merkle proof in path order.
+type proofList [][]byte
+
+func (n *proofList) Put(key []byte, value []byte) error {
+	*n = append(*n, value)
+	return nil
+}
+
 // HasSuicided returns this account is suicided
 func (adb *AccountDB) HasSuicided(addr common.Address) bool {
 	stateObject := adb.getAccountObject(addr, false)
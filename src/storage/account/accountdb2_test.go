@@ -18,7 +18,10 @@ package account
 
 import (
 	"com.tuntun.rangers/node/src/common"
+	crypto "com.tuntun.rangers/node/src/eth_crypto"
 	"com.tuntun.rangers/node/src/middleware/db"
+	"com.tuntun.rangers/node/src/storage/rlp"
+	"com.tuntun.rangers/node/src/storage/trie"
 	"fmt"
 	"math/big"
 	"testing"
@@ -126,3 +129,91 @@ func TestAccountDB_Revert(t *testing.T) {
 	balance = state.GetBalance(common.BytesToAddress([]byte("1")))
 	fmt.Printf("after commit balance:%v\n", balance)
 }
+
+func TestAccountDB_GetProof(t *testing.T) {
+	memdb, _ := db.NewMemDatabase()
+	triedb := NewDatabase(memdb)
+	state, _ := NewAccountDB(common.Hash{}, triedb)
+
+	addr := common.BytesToAddress([]byte("proof"))
+	key := common.BytesToHash([]byte("slot"))
+	state.SetNonce(addr, 3)
+	state.SetState(addr, key, common.BytesToHash([]byte("value")))
+	root, _ := state.Commit(true)
+
+	accountProof, err := state.GetProof(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := trie.VerifyProof(root, addr[:], proofDatabase(accountProof))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data Account
+	if err := rlp.DecodeBytes(enc, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Nonce != 3 {
+		t.Fatalf("nonce mismatch: got %d, want 3", data.Nonce)
+	}
+
+	storageProof, err := state.GetStorageProof(addr, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := trie.VerifyProof(data.Root, key.Bytes(), proofDatabase(storageProof))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if common.BytesToHash(value) != common.BytesToHash([]byte("value")) {
+		t.Fatalf("storage value mismatch: got %x", value)
+	}
+}
+
+func proofDatabase(proof [][]byte) *db.MemDatabase {
+	memdb, _ := db.NewMemDatabase()
+	for _, node := range proof {
+		memdb.Put(crypto.Keccak256(node), node)
+	}
+	return memdb
+}
+
+func TestAccountDB_Copy(t *testing.T) {
+	memdb, _ := db.NewMemDatabase()
+	state, _ := NewAccountDB(common.Hash{}, NewDatabase(memdb))
+
+	addr := common.BytesToAddress([]byte("copy"))
+	key := []byte("slot")
+	state.SetBalance(addr, big.NewInt(1))
+	state.SetNonce(addr, 1)
+	state.SetData(addr, key, []byte("v1"))
+	root := state.IntermediateRoot(true)
+
+	cpy := state.Copy()
+	cpy.SetBalance(addr, big.NewInt(2))
+	cpy.SetNonce(addr, 2)
+	cpy.SetData(addr, key, []byte("v2"))
+
+	if balance := state.GetBalance(addr); balance.Int64() != 1 {
+		t.Fatalf("balance of origin changed, got %d", balance.Int64())
+	}
+	if nonce := state.GetNonce(addr); nonce != 1 {
+		t.Fatalf("nonce of origin changed, got %d", nonce)
+	}
+	if value := state.GetData(addr, key); string(value) != "v1" {
+		t.Fatalf("storage of origin changed, got %s", value)
+	}
+	if hash := state.IntermediateRoot(true); hash != root {
+		t.Fatalf("root of origin changed, got %s", hash.Hex())
+	}
+
+	if balance := cpy.GetBalance(addr); balance.Int64() != 2 {
+		t.Fatalf("balance of copy mismatch, got %d", balance.Int64())
+	}
+	if value := cpy.GetData(addr, key); string(value) != "v2" {
+		t.Fatalf("storage of copy mismatch, got %s", value)
+	}
+	if hash := cpy.IntermediateRoot(true); hash == root {
+		t.Fatal("root of copy unchanged")
+	}
+}
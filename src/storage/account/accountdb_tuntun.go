@@ -73,7 +73,7 @@ func (self *AccountDB) AddFT(addr common.Address, ftName string, balance *big.In
 		key := self.GetERC20Key(addr, position)
 		remain := new(big.Int).SetBytes(account.GetData(self.db, key))
 		remain.Add(remain, utility.FormatDecimalForERC20(balance, int64(decimal)))
-		if common.IsProposal002() {
+		if common.CurrentRules().IsProposal002 {
 			account.SetData(self.db, key, remain.Bytes())
 		} else {
 			account.setData(key, remain.Bytes())
@@ -104,7 +104,7 @@ func (self *AccountDB) SubFT(addr common.Address, ftName string, balance *big.In
 		}
 
 		remain.Sub(remain, value)
-		if common.IsProposal002() {
+		if common.CurrentRules().IsProposal002 {
 			account.SetData(self.db, key, remain.Bytes())
 		} else {
 			account.setData(key, remain.Bytes())
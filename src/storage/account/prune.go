@@ -0,0 +1,175 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package account
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	xdb "com.tuntun.rangers/node/src/middleware/db"
+	"com.tuntun.rangers/node/src/storage/rlp"
+	"com.tuntun.rangers/node/src/storage/trie"
+	"encoding/binary"
+)
+
+const (
+	// pruneBloomSize is the size in bytes of the bloom filter of the reachable nodes
+	pruneBloomSize = 256 * 1024 * 1024
+	// pruneBloomHashes is the number of bit positions set for one node
+	pruneBloomHashes = 4
+)
+
+// stateBloom is a bloom filter of node hashes.
+// The hashes are keccak outputs already, so the bit positions are read from the hash itself.
+type stateBloom struct {
+	bits []uint64
+	size uint64
+}
+
+func newStateBloom(bytes uint64) *stateBloom {
+	words := bytes / 8
+	if 0 == words {
+		words = 1
+	}
+	return &stateBloom{bits: make([]uint64, words), size: words * 64}
+}
+
+func (b *stateBloom) add(hash common.Hash) {
+	for i := 0; i < pruneBloomHashes; i++ {
+		pos := binary.BigEndian.Uint64(hash[i*8:]) % b.size
+		b.bits[pos/64] |= 1 << (pos % 64)
+	}
+}
+
+func (b *stateBloom) mayContain(hash common.Hash) bool {
+	for i := 0; i < pruneBloomHashes; i++ {
+		pos := binary.BigEndian.Uint64(hash[i*8:]) % b.size
+		if 0 == b.bits[pos/64]&(1<<(pos%64)) {
+			return false
+		}
+	}
+	return true
+}
+
+// reachableSet records the reachable node hashes.
+// The bloom filter answers most of the lookups in memory, the hashes it may contain
+// are confirmed against a disk backed set, so memory use does not grow with the state.
+type reachableSet struct {
+	bloom *stateBloom
+	db    xdb.Database
+}
+
+func (s *reachableSet) add(hash common.Hash) error {
+	s.bloom.add(hash)
+	return s.db.Put(hash.Bytes(), []byte{})
+}
+
+func (s *reachableSet) contains(hash common.Hash) (bool, error) {
+	if !s.bloom.mayContain(hash) {
+		return false, nil
+	}
+	return s.db.Has(hash.Bytes())
+}
+
+// PruneState deletes the trie nodes and nft set definitions in diskdb which
+// can not be reached from any of the given state roots.
+// setdb is an empty scratch database keeping the reachable hashes, it can be removed afterwards.
+// Preimages and other non hash keys are kept. It must only be used on a stopped node.
+func PruneState(diskdb xdb.Database, setdb xdb.Database, roots ...common.Hash) (int, common.StorageSize, error) {
+	return pruneState(diskdb, &reachableSet{bloom: newStateBloom(pruneBloomSize), db: setdb}, roots...)
+}
+
+func pruneState(diskdb xdb.Database, reachable *reachableSet, roots ...common.Hash) (int, common.StorageSize, error) {
+	triedb := trie.NewDatabase(diskdb)
+	for _, root := range roots {
+		if err := markReachable(triedb, root, reachable, true); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	var (
+		count int
+		size  common.StorageSize
+	)
+	it := diskdb.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		key := it.Key()
+		if common.HashLength != len(key) {
+			continue
+		}
+		ok, err := reachable.contains(common.BytesToHash(key))
+		if err != nil {
+			return count, size, err
+		}
+		if ok {
+			continue
+		}
+		if err := diskdb.Delete(key); err != nil {
+			return count, size, err
+		}
+		count++
+		size += common.StorageSize(len(key) + len(it.Value()))
+	}
+	return count, size, it.Error()
+}
+
+// markReachable walks the trie of root and records the hashes of all nodes.
+// Subtries already recorded are skipped. For the account trie, storage tries and
+// nft set definitions of the accounts are recorded as well.
+func markReachable(triedb *trie.NodeDatabase, root common.Hash, reachable *reachableSet, isAccountTrie bool) error {
+	tr, err := trie.NewTrie(root, triedb)
+	if err != nil {
+		return err
+	}
+
+	it := tr.NodeIterator(nil)
+	descend := true
+	for it.Next(descend) {
+		descend = true
+		if hash := it.Hash(); hash != (common.Hash{}) {
+			ok, err := reachable.contains(hash)
+			if err != nil {
+				return err
+			}
+			if ok {
+				descend = false
+				continue
+			}
+			if err := reachable.add(hash); err != nil {
+				return err
+			}
+		}
+		if !isAccountTrie || !it.Leaf() {
+			continue
+		}
+
+		var account Account
+		if err := rlp.DecodeBytes(it.LeafBlob(), &account); err != nil {
+			return err
+		}
+		if account.Root != emptyData && account.Root != (common.Hash{}) {
+			if err := markReachable(triedb, account.Root, reachable, false); err != nil {
+				return err
+			}
+		}
+		if 0 != len(account.NFTSetDefinitionHash) {
+			if err := reachable.add(common.BytesToHash(account.NFTSetDefinitionHash)); err != nil {
+				return err
+			}
+		}
+	}
+	return it.Error()
+}
@@ -0,0 +1,116 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package account
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/middleware/db"
+	"math/big"
+	"os"
+	"testing"
+)
+
+func TestPruneState(t *testing.T) {
+	defer os.RemoveAll("storage0")
+	diskdb, err := db.NewLDBDatabase("prune", 16, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer diskdb.Close()
+
+	commit := func(state *AccountDB) common.Hash {
+		root, _ := state.Commit(true)
+		if err := state.db.TrieDB().Commit(root, false); err != nil {
+			t.Fatal(err)
+		}
+		return root
+	}
+
+	addr1 := common.BytesToAddress([]byte("prune1"))
+	addr2 := common.BytesToAddress([]byte("prune2"))
+	key := []byte("slot")
+
+	state, _ := NewAccountDB(common.Hash{}, NewDatabase(diskdb))
+	state.SetBalance(addr1, big.NewInt(1))
+	state.SetData(addr1, key, []byte("v1"))
+	state.SetBalance(addr2, big.NewInt(2))
+	root1 := commit(state)
+
+	state, _ = NewAccountDB(root1, NewDatabase(diskdb))
+	state.SetBalance(addr1, big.NewInt(3))
+	state.SetData(addr1, key, []byte("v2"))
+	root2 := commit(state)
+
+	setdb, _ := db.NewMemDatabase()
+	count, _, err := PruneState(diskdb, setdb, root2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if 0 == count {
+		t.Fatal("nothing pruned")
+	}
+
+	if _, err := NewAccountDB(root1, NewDatabase(diskdb)); err == nil {
+		t.Fatal("pruned state still readable")
+	}
+	state, err = NewAccountDB(root2, NewDatabase(diskdb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance := state.GetBalance(addr1); balance.Int64() != 3 {
+		t.Fatalf("balance mismatch, got %d", balance.Int64())
+	}
+	if balance := state.GetBalance(addr2); balance.Int64() != 2 {
+		t.Fatalf("balance mismatch, got %d", balance.Int64())
+	}
+	if value := state.GetData(addr1, key); string(value) != "v2" {
+		t.Fatalf("storage mismatch, got %s", value)
+	}
+}
+
+func TestReachableSet(t *testing.T) {
+	setdb, _ := db.NewMemDatabase()
+	// a tiny bloom filter says yes to almost every hash
+	reachable := &reachableSet{bloom: newStateBloom(1), db: setdb}
+
+	hashes := make([]common.Hash, 100)
+	for i := range hashes {
+		hashes[i] = common.BytesToHash(common.Sha256([]byte{byte(i)}))
+	}
+	for _, hash := range hashes[:50] {
+		if err := reachable.add(hash); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	falsePositive := 0
+	for i, hash := range hashes {
+		if reachable.bloom.mayContain(hash) && i >= 50 {
+			falsePositive++
+		}
+		ok, err := reachable.contains(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != (i < 50) {
+			t.Fatalf("hash %d reachable: %v", i, ok)
+		}
+	}
+	if 0 == falsePositive {
+		t.Fatal("no false positive of the bloom filter")
+	}
+}
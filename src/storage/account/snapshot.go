@@ -0,0 +1,217 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package account
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	xdb "com.tuntun.rangers/node/src/middleware/db"
+	"com.tuntun.rangers/node/src/storage/rlp"
+	"com.tuntun.rangers/node/src/storage/trie"
+	"errors"
+	"fmt"
+)
+
+// accounts in the account trie between two flushes of the rebuilt account trie
+const stateRebuildFlushInterval = 10000
+
+var ErrStateRootMismatch = errors.New("state root mismatch")
+
+// StateDumper receives the flat content of a state
+type StateDumper interface {
+	// OnAccount is called with the account trie leaf before the storage of the account
+	OnAccount(address []byte, account []byte) error
+
+	OnStorage(key []byte, value []byte) error
+
+	// OnNFTSet is called with the nft set definition or the contract code of the account
+	OnNFTSet(hash common.Hash, definition []byte) error
+}
+
+// DumpState iterates the account trie of root together with the storage tries and nft set definitions.
+func DumpState(triedb *trie.NodeDatabase, root common.Hash, dumper StateDumper) (int, error) {
+	tr, err := trie.NewTrie(root, triedb)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	it := trie.NewIterator(tr.NodeIterator(nil))
+	for it.Next() {
+		var account Account
+		if err := rlp.DecodeBytes(it.Value, &account); err != nil {
+			return count, err
+		}
+		if err := dumper.OnAccount(it.Key, it.Value); err != nil {
+			return count, err
+		}
+		count++
+
+		if account.Root != emptyData && account.Root != (common.Hash{}) {
+			storage, err := trie.NewTrie(account.Root, triedb)
+			if err != nil {
+				return count, err
+			}
+			storageIt := trie.NewIterator(storage.NodeIterator(nil))
+			for storageIt.Next() {
+				if err := dumper.OnStorage(storageIt.Key, storageIt.Value); err != nil {
+					return count, err
+				}
+			}
+			if storageIt.Err != nil {
+				return count, storageIt.Err
+			}
+		}
+
+		if hash := common.BytesToHash(account.NFTSetDefinitionHash); hash != emptyCode && 0 != len(account.NFTSetDefinitionHash) {
+			definition, err := triedb.Node(hash)
+			if err != nil {
+				return count, err
+			}
+			if err := dumper.OnNFTSet(hash, definition); err != nil {
+				return count, err
+			}
+		}
+	}
+	return count, it.Err
+}
+
+// StateRebuilder writes a state dumped by DumpState into a staging database.
+// The storage root of every account is checked while rebuilding,
+// the staged state is written to the state database only after its root is verified.
+type StateRebuilder struct {
+	staging xdb.Database
+	triedb  *trie.NodeDatabase
+	trie    *trie.Trie
+
+	address []byte
+	account []byte
+	root    common.Hash
+	storage *trie.Trie
+
+	count int
+}
+
+func NewStateRebuilder(staging xdb.Database) (*StateRebuilder, error) {
+	triedb := trie.NewDatabase(staging)
+	tr, err := trie.NewTrie(common.Hash{}, triedb)
+	if err != nil {
+		return nil, err
+	}
+	return &StateRebuilder{staging: staging, triedb: triedb, trie: tr}, nil
+}
+
+func (rebuilder *StateRebuilder) OnAccount(address []byte, account []byte) error {
+	if err := rebuilder.finishAccount(); err != nil {
+		return err
+	}
+
+	var data Account
+	if err := rlp.DecodeBytes(account, &data); err != nil {
+		return err
+	}
+	storage, err := trie.NewTrie(common.Hash{}, rebuilder.triedb)
+	if err != nil {
+		return err
+	}
+	rebuilder.address = common.CopyBytes(address)
+	rebuilder.account = common.CopyBytes(account)
+	rebuilder.root = data.Root
+	rebuilder.storage = storage
+	return nil
+}
+
+func (rebuilder *StateRebuilder) OnStorage(key []byte, value []byte) error {
+	if nil == rebuilder.storage {
+		return errors.New("storage without account")
+	}
+	return rebuilder.storage.TryUpdate(common.CopyBytes(key), common.CopyBytes(value))
+}
+
+func (rebuilder *StateRebuilder) OnNFTSet(hash common.Hash, definition []byte) error {
+	rebuilder.triedb.InsertBlob(hash, common.CopyBytes(definition))
+	return rebuilder.triedb.Commit(hash, false)
+}
+
+// Commit writes the rebuilt state to the staging database and returns its root
+func (rebuilder *StateRebuilder) Commit() (common.Hash, error) {
+	if err := rebuilder.finishAccount(); err != nil {
+		return common.Hash{}, err
+	}
+	return rebuilder.flush()
+}
+
+// Write copies the staged state into diskdb. It is called once the root returned by Commit is verified
+func (rebuilder *StateRebuilder) Write(diskdb xdb.Database) error {
+	it := rebuilder.staging.NewIterator()
+	defer it.Release()
+
+	batch := diskdb.NewBatch()
+	for it.Next() {
+		if err := batch.Put(common.CopyBytes(it.Key()), common.CopyBytes(it.Value())); err != nil {
+			return err
+		}
+		if batch.ValueSize() > xdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+func (rebuilder *StateRebuilder) finishAccount() error {
+	if nil == rebuilder.address {
+		return nil
+	}
+
+	root, err := rebuilder.storage.Commit(nil)
+	if err != nil {
+		return err
+	}
+	if root != rebuilder.root && !(root == emptyData && rebuilder.root == common.Hash{}) {
+		return fmt.Errorf("%w, account: %s, expect: %s, got: %s", ErrStateRootMismatch, common.ToHex(rebuilder.address), rebuilder.root.String(), root.String())
+	}
+	if err := rebuilder.triedb.Commit(root, false); err != nil {
+		return err
+	}
+	if err := rebuilder.trie.TryUpdate(rebuilder.address, rebuilder.account); err != nil {
+		return err
+	}
+
+	rebuilder.address, rebuilder.account, rebuilder.storage = nil, nil, nil
+	rebuilder.count++
+	if 0 == rebuilder.count%stateRebuildFlushInterval {
+		_, err = rebuilder.flush()
+	}
+	return err
+}
+
+func (rebuilder *StateRebuilder) flush() (common.Hash, error) {
+	root, err := rebuilder.trie.Commit(nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := rebuilder.triedb.Commit(root, false); err != nil {
+		return common.Hash{}, err
+	}
+	rebuilder.trie, err = trie.NewTrie(root, rebuilder.triedb)
+	return root, err
+}
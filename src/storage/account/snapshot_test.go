@@ -0,0 +1,159 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package account
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/middleware/db"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+)
+
+// stateRecorder keeps a dumped state in memory
+type stateRecorder struct {
+	records []func(dumper StateDumper) error
+}
+
+func (recorder *stateRecorder) OnAccount(address []byte, account []byte) error {
+	address, account = common.CopyBytes(address), common.CopyBytes(account)
+	recorder.records = append(recorder.records, func(dumper StateDumper) error { return dumper.OnAccount(address, account) })
+	return nil
+}
+
+func (recorder *stateRecorder) OnStorage(key []byte, value []byte) error {
+	key, value = common.CopyBytes(key), common.CopyBytes(value)
+	recorder.records = append(recorder.records, func(dumper StateDumper) error { return dumper.OnStorage(key, value) })
+	return nil
+}
+
+func (recorder *stateRecorder) OnNFTSet(hash common.Hash, definition []byte) error {
+	definition = common.CopyBytes(definition)
+	recorder.records = append(recorder.records, func(dumper StateDumper) error { return dumper.OnNFTSet(hash, definition) })
+	return nil
+}
+
+func (recorder *stateRecorder) replay(dumper StateDumper) error {
+	for _, record := range recorder.records {
+		if err := record(dumper); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestDumpAndRebuildState(t *testing.T) {
+	diskdb, _ := db.NewMemDatabase()
+	state, _ := NewAccountDB(common.Hash{}, NewDatabase(diskdb))
+
+	addr1 := common.BytesToAddress([]byte("snapshot1"))
+	addr2 := common.BytesToAddress([]byte("snapshot2"))
+	contract := common.BytesToAddress([]byte("contract"))
+	state.SetBalance(addr1, big.NewInt(100))
+	state.SetNonce(addr1, 3)
+	state.SetData(addr1, []byte("k1"), []byte("v1"))
+	state.SetData(addr1, []byte("k2"), []byte("v2"))
+	state.SetBalance(addr2, big.NewInt(200))
+	state.SetCode(contract, []byte{0x60, 0x00, 0x60, 0x00})
+	state.SetState(contract, common.HexToHash("0x01"), common.HexToHash("0x02"))
+	root, err := state.Commit(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Database().TrieDB().Commit(root, false); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := &stateRecorder{}
+	count, err := DumpState(state.Database().TrieDB(), root, recorder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if 3 != count {
+		t.Fatalf("expect 3 accounts, got %d", count)
+	}
+
+	defer os.RemoveAll("storage0")
+	stagingdb, err := db.NewLDBDatabase("staging", 16, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stagingdb.Close()
+	rebuilder, err := NewStateRebuilder(stagingdb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.replay(rebuilder); err != nil {
+		t.Fatal(err)
+	}
+	rebuilt, err := rebuilder.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rebuilt != root {
+		t.Fatalf("root mismatch, expect %s, got %s", root.String(), rebuilt.String())
+	}
+	targetdb, _ := db.NewMemDatabase()
+	if err := rebuilder.Write(targetdb); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err = NewAccountDB(rebuilt, NewDatabase(targetdb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance := state.GetBalance(addr1); balance.Int64() != 100 {
+		t.Fatalf("balance mismatch, got %d", balance.Int64())
+	}
+	if nonce := state.GetNonce(addr1); nonce != 3 {
+		t.Fatalf("nonce mismatch, got %d", nonce)
+	}
+	if value := state.GetData(addr1, []byte("k2")); string(value) != "v2" {
+		t.Fatalf("storage mismatch, got %s", value)
+	}
+	if code := state.GetCode(contract); 4 != len(code) {
+		t.Fatalf("code mismatch, got %x", code)
+	}
+	if value := state.GetState(contract, common.HexToHash("0x01")); value != common.HexToHash("0x02") {
+		t.Fatalf("state mismatch, got %s", value.String())
+	}
+}
+
+func TestRebuildStateMismatch(t *testing.T) {
+	diskdb, _ := db.NewMemDatabase()
+	state, _ := NewAccountDB(common.Hash{}, NewDatabase(diskdb))
+	addr := common.BytesToAddress([]byte("snapshot"))
+	state.SetData(addr, []byte("k1"), []byte("v1"))
+	root, _ := state.Commit(true)
+	state.Database().TrieDB().Commit(root, false)
+
+	recorder := &stateRecorder{}
+	if _, err := DumpState(state.Database().TrieDB(), root, recorder); err != nil {
+		t.Fatal(err)
+	}
+	recorder.records = recorder.records[:1]
+
+	stagingdb, _ := db.NewMemDatabase()
+	rebuilder, _ := NewStateRebuilder(stagingdb)
+	if err := recorder.replay(rebuilder); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rebuilder.Commit(); !errors.Is(err, ErrStateRootMismatch) {
+		t.Fatalf("expect ErrStateRootMismatch, got %v", err)
+	}
+}
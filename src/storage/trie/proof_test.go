@@ -0,0 +1,96 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/middleware/db"
+)
+
+func TestProof(t *testing.T) {
+	trie := newEmpty()
+	values := make(map[string][]byte)
+	for i := 0; i < 200; i++ {
+		key := common.BigToHash(common.Big1).Bytes()
+		key[0] = byte(i)
+		value := []byte(fmt.Sprintf("value-%d", i))
+		trie.Update(key, value)
+		values[string(key)] = value
+	}
+	root := trie.Hash()
+
+	for key, want := range values {
+		proof, _ := db.NewMemDatabase()
+		if err := trie.Prove([]byte(key), 0, proof); err != nil {
+			t.Fatalf("prove %x: %v", key, err)
+		}
+		got, err := VerifyProof(root, []byte(key), proof)
+		if err != nil {
+			t.Fatalf("verify %x: %v", key, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("verify %x: got %x, want %x", key, got, want)
+		}
+	}
+}
+
+func TestAbsentProof(t *testing.T) {
+	trie := newEmpty()
+	trie.Update([]byte("abcdef"), []byte("A"))
+	trie.Update([]byte("abcxyz"), []byte("B"))
+	root := trie.Hash()
+
+	proof, _ := db.NewMemDatabase()
+	if err := trie.Prove([]byte("abcghi"), 0, proof); err != nil {
+		t.Fatal(err)
+	}
+	got, err := VerifyProof(root, []byte("abcghi"), proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Fatalf("expected absent key, got %x", got)
+	}
+}
+
+func TestBadProof(t *testing.T) {
+	trie := newEmpty()
+	trie.Update([]byte("abcdef"), []byte("A"))
+	trie.Update([]byte("abcxyz"), []byte("B"))
+	root := trie.Hash()
+
+	proof, _ := db.NewMemDatabase()
+	if err := trie.Prove([]byte("abcdef"), 0, proof); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range proof.Keys() {
+		value, _ := proof.Get(key)
+		proof.Delete(key)
+		if _, err := VerifyProof(root, []byte("abcdef"), proof); err == nil {
+			t.Fatalf("expected error for proof without node %x", key)
+		}
+		proof.Put(key, value)
+	}
+
+	if _, err := VerifyProof(common.HexToHash("0x01"), []byte("abcdef"), proof); err == nil {
+		t.Fatal("expected error for wrong root")
+	}
+}
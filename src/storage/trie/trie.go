@@ -88,6 +88,13 @@ func NewTrie(root common.Hash, db *NodeDatabase) (*Trie, error) {
 	return trie, nil
 }
 
+// Copy returns a copy of the trie. Nodes are never modified in place,
+// so the copy and the original can be updated independently.
+func (t *Trie) Copy() *Trie {
+	cpy := *t
+	return &cpy
+}
+
 // NodeIterator returns an iterator that returns nodes of the trie. Iteration starts at
 // the key after the given start key.
 func (t *Trie) NodeIterator(start []byte) NodeIterator {
@@ -0,0 +1,119 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RangersProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"com.tuntun.rangers/node/src/common"
+	"com.tuntun.rangers/node/src/utility"
+	"math/big"
+	"time"
+)
+
+// CallFrame is a single call of the call tree produced by CallTracer.
+// It serializes to the same layout as geth's callTracer output.
+type CallFrame struct {
+	Type    string          `json:"type"`
+	From    common.Address  `json:"from"`
+	To      *common.Address `json:"to,omitempty"`
+	Value   *utility.Big    `json:"value,omitempty"`
+	Gas     utility.Uint64  `json:"gas"`
+	GasUsed utility.Uint64  `json:"gasUsed"`
+	Input   utility.Bytes   `json:"input"`
+	Output  utility.Bytes   `json:"output,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Calls   []CallFrame     `json:"calls,omitempty"`
+}
+
+// CallTracer records the call tree of an execution, ignoring opcode level steps.
+type CallTracer struct {
+	callstack []CallFrame
+}
+
+func NewCallTracer() *CallTracer {
+	// the first frame is the root call, it is filled by CaptureStart
+	return &CallTracer{callstack: make([]CallFrame, 1)}
+}
+
+func (t *CallTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	typ := CALL
+	if create {
+		typ = CREATE
+	}
+	t.callstack[0] = newCallFrame(typ, from, to, input, gas, value)
+	return nil
+}
+
+func (t *CallTracer) CaptureState(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, rStack *ReturnStack, rData []byte, contract *Contract, depth int, err error) error {
+	return nil
+}
+
+func (t *CallTracer) CaptureFault(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, rStack *ReturnStack, contract *Contract, depth int, err error) error {
+	return nil
+}
+
+func (t *CallTracer) CaptureEnd(output []byte, gasUsed uint64, tm time.Duration, err error) error {
+	t.callstack[0].fill(output, gasUsed, err)
+	return nil
+}
+
+func (t *CallTracer) CaptureEnter(typ OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	t.callstack = append(t.callstack, newCallFrame(typ, from, to, input, gas, value))
+}
+
+func (t *CallTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	size := len(t.callstack)
+	if size <= 1 {
+		return
+	}
+	// pop call
+	call := t.callstack[size-1]
+	t.callstack = t.callstack[:size-1]
+	size -= 1
+
+	call.fill(output, gasUsed, err)
+	t.callstack[size-1].Calls = append(t.callstack[size-1].Calls, call)
+}
+
+// GetResult returns the root frame of the recorded call tree.
+func (t *CallTracer) GetResult() *CallFrame {
+	return &t.callstack[0]
+}
+
+func newCallFrame(typ OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) CallFrame {
+	frame := CallFrame{
+		Type:  typ.String(),
+		From:  from,
+		To:    &to,
+		Gas:   utility.Uint64(gas),
+		Input: common.CopyBytes(input),
+	}
+	if value != nil {
+		frame.Value = (*utility.Big)(new(big.Int).Set(value))
+	}
+	return frame
+}
+
+func (f *CallFrame) fill(output []byte, gasUsed uint64, err error) {
+	f.GasUsed = utility.Uint64(gasUsed)
+	f.Output = common.CopyBytes(output)
+	if err != nil {
+		f.Error = err.Error()
+		if err != ErrExecutionReverted {
+			f.Output = nil
+		}
+	}
+}
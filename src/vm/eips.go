@@ -196,6 +196,16 @@ func opPush0(pc *uint64, interpreter *EVMInterpreter, scope *callCtx) ([]byte, e
 	return nil, nil
 }
 
+// doProposal028 turns DIFFICULTY into PREVRANDAO, the randomness of the group signature
+func doProposal028(jt *JumpTable) {
+	jt[DIFFICULTY] = &operation{
+		execute:     opRandom,
+		constantGas: GasQuickStep,
+		minStack:    minStack(0, 1),
+		maxStack:    maxStack(0, 1),
+	}
+}
+
 func doProposal026(jt *JumpTable) {
 	for _, operator := range jt {
 		if operator != nil {
@@ -26,6 +26,7 @@ import (
 	"golang.org/x/crypto/sha3"
 	"math/big"
 	"sync/atomic"
+	"time"
 )
 
 // emptyCodeHash is used by create to ensure deployment is disallowed to already
@@ -43,6 +44,9 @@ type (
 	// GetHashFunc returns the n'th block hash in the blockchain
 	// and is used by the BLOCKHASH EVM op code.
 	GetHashFunc func(uint64) common.Hash
+	// GetRandomFunc returns the randomness of the parent's group signature
+	// and is used by the PREVRANDAO EVM op code.
+	GetRandomFunc func() common.Hash
 )
 
 // ActivePrecompiles returns the addresses of the precompiles enabled with the current
@@ -84,6 +88,8 @@ type Context struct {
 	Transfer TransferFunc
 	// GetHash returns the hash corresponding to n
 	GetHash GetHashFunc
+	// GetRandom returns the PREVRANDAO of the block
+	GetRandom GetRandomFunc
 
 	// Message information
 	Origin   common.Address // Provides information for ORIGIN
@@ -116,6 +122,8 @@ type EVM struct {
 
 	chainID *big.Int
 
+	// debug enables the tracer hooks, tracer receives the execution steps
+	debug  bool
 	tracer Tracer
 	// global (to this context) ethereum virtual machine
 	// used throughout the execution of the tx.
@@ -143,9 +151,15 @@ func NewEVM(ctx Context, statedb StateDB) *EVM {
 	if ctx.BlockNumber != nil {
 		evm.chainID = common.GetChainId(ctx.BlockNumber.Uint64())
 	}
-
-	config := LogConfig{false, false, false, false}
-	evm.tracer = NewStructLogger(&config, logger)
+	// PREVRANDAO falls back to DIFFICULTY for the contexts without the randomness
+	if evm.GetRandom == nil {
+		evm.GetRandom = func() common.Hash {
+			if evm.Difficulty == nil {
+				return common.Hash{}
+			}
+			return common.BigToHash(evm.Difficulty)
+		}
+	}
 
 	// vmConfig.EVMInterpreter will be used by EVM-C, it won't be checked here
 	// as we always want to have the built-in EVM as the failover option.
@@ -164,6 +178,13 @@ func NewEVMWithNFT(ctx Context, statedb StateDB, accountDB *account.AccountDB) *
 	return evm
 }
 
+// SetTracer attaches a tracer to the EVM. All following calls and creations
+// will be reported to it until the EVM is discarded.
+func (evm *EVM) SetTracer(tracer Tracer) {
+	evm.tracer = tracer
+	evm.debug = tracer != nil
+}
+
 // Cancel cancels any running EVM operation. This may be called concurrently and
 // it's safe to be called multiple times.
 func (evm *EVM) Cancel() {
@@ -199,17 +220,23 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 	if !evm.StateDB.Exist(addr) {
 		if !isPrecompile && value.Sign() == 0 {
 			// Calling a non existing account, don't do anything, but ping the tracer
-			//if evm.vmConfig.Debug && evm.depth == 0
-			//if evm.depth == 0 {
-			//	evm.tracer.CaptureStart(caller.Address(), addr, false, input, gas, value)
-			//	evm.tracer.CaptureEnd(ret, 0, 0, nil)
-			//}
+			if evm.debug {
+				evm.captureBegin(CALL, caller.Address(), addr, input, gas, value)
+				evm.captureEnd(ret, 0, time.Now(), nil)
+			}
 			return nil, gas, nil, nil
 		}
 		evm.StateDB.CreateAccount(addr)
 	}
 	evm.Transfer(evm.StateDB, caller.Address(), addr, value)
 
+	if evm.debug {
+		evm.captureBegin(CALL, caller.Address(), addr, input, gas, value)
+		defer func(startGas uint64, startTime time.Time) {
+			evm.captureEnd(ret, startGas-gas, startTime, err)
+		}(gas, time.Now())
+	}
+
 	if isPrecompile {
 		ret, gas, err = RunPrecompiledContract(p, input, gas)
 	} else {
@@ -265,6 +292,13 @@ func (evm *EVM) CallCode(caller ContractRef, addr common.Address, input []byte,
 	}
 	var snapshot = evm.StateDB.Snapshot()
 
+	if evm.debug {
+		evm.captureBegin(CALLCODE, caller.Address(), addr, input, gas, value)
+		defer func(startGas uint64, startTime time.Time) {
+			evm.captureEnd(ret, startGas-gas, startTime, err)
+		}(gas, time.Now())
+	}
+
 	// It is allowed to call precompiles, even via delegatecall
 	if p, isPrecompile := evm.precompile(addr); isPrecompile {
 		ret, gas, err = RunPrecompiledContract(p, input, gas)
@@ -299,6 +333,13 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []by
 	}
 	var snapshot = evm.StateDB.Snapshot()
 
+	if evm.debug {
+		evm.captureBegin(DELEGATECALL, caller.Address(), addr, input, gas, nil)
+		defer func(startGas uint64, startTime time.Time) {
+			evm.captureEnd(ret, startGas-gas, startTime, err)
+		}(gas, time.Now())
+	}
+
 	// It is allowed to call precompiles, even via delegatecall
 	if p, isPrecompile := evm.precompile(addr); isPrecompile {
 		ret, gas, err = RunPrecompiledContract(p, input, gas)
@@ -341,6 +382,13 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 	// future scenarios
 	evm.StateDB.AddBalance(addr, big0)
 
+	if evm.debug {
+		evm.captureBegin(STATICCALL, caller.Address(), addr, input, gas, nil)
+		defer func(startGas uint64, startTime time.Time) {
+			evm.captureEnd(ret, startGas-gas, startTime, err)
+		}(gas, time.Now())
+	}
+
 	if p, isPrecompile := evm.precompile(addr); isPrecompile {
 		ret, gas, err = RunPrecompiledContract(p, input, gas)
 	} else {
@@ -380,7 +428,7 @@ func (c *codeAndHash) Hash() common.Hash {
 }
 
 // create creates a new contract using code as deployment code.
-func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64, value *big.Int, address common.Address) ([]byte, common.Address, uint64, []*types.Log, error) {
+func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64, value *big.Int, address common.Address, typ OpCode) ([]byte, common.Address, uint64, []*types.Log, error) {
 	// check owner when subnet
 	if common.IsSub() && 0 != evm.BlockNumber.Uint64() {
 		data := [64]byte{}
@@ -405,8 +453,9 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	if !evm.CanTransfer(evm.StateDB, caller.Address(), value) {
 		return nil, common.Address{}, gas, nil, ErrInsufficientBalance
 	}
+	rules := common.CurrentRules()
 	nonce := evm.StateDB.GetNonce(caller.Address())
-	if !common.IsProposal006() || common.IsProposal007() {
+	if !rules.IsProposal006 || rules.IsProposal007 {
 		evm.StateDB.SetNonce(caller.Address(), nonce+1)
 	}
 	// We add this to the access list _before_ taking a snapshot. Even if the creation fails,
@@ -430,6 +479,11 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	contract := NewContract(caller, AccountRef(address), value, gas)
 	contract.SetCodeOptionalHash(&address, codeAndHash)
 
+	if evm.debug {
+		evm.captureBegin(typ, caller.Address(), address, codeAndHash.code, gas, value)
+	}
+	start := time.Now()
+
 	ret, logs, err := run(evm, contract, nil, false)
 
 	// check whether the max code size has been exceeded
@@ -440,7 +494,7 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	// by the error checking condition below.
 	if err == nil && !maxCodeSizeExceeded {
 		createDataGas := uint64(len(ret)) * CreateDataGas
-		if common.IsProposal026() {
+		if rules.IsProposal026 {
 			createDataGas = createDataGas * common.GasMagnification
 		}
 		if contract.UseGas(createDataGas) {
@@ -464,6 +518,9 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	if maxCodeSizeExceeded && err == nil {
 		err = ErrMaxCodeSizeExceeded
 	}
+	if evm.debug {
+		evm.captureEnd(ret, gas-contract.Gas, start, err)
+	}
 	return ret, address, contract.Gas, logs, err
 
 }
@@ -471,7 +528,7 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 // Create creates a new contract using code as deployment code.
 func (evm *EVM) Create(caller ContractRef, code []byte, gas uint64, value *big.Int) (ret []byte, contractAddr common.Address, leftOverGas uint64, logs []*types.Log, err error) {
 	contractAddr = crypto.CreateAddress(caller.Address(), evm.StateDB.GetNonce(caller.Address()))
-	return evm.create(caller, &codeAndHash{code: code}, gas, value, contractAddr)
+	return evm.create(caller, &codeAndHash{code: code}, gas, value, contractAddr, CREATE)
 }
 
 // Create2 creates a new contract using code as deployment code.
@@ -481,7 +538,7 @@ func (evm *EVM) Create(caller ContractRef, code []byte, gas uint64, value *big.I
 func (evm *EVM) Create2(caller ContractRef, code []byte, gas uint64, endowment *big.Int, salt *uint256.Int) (ret []byte, contractAddr common.Address, leftOverGas uint64, logs []*types.Log, err error) {
 	codeAndHash := &codeAndHash{code: code}
 	contractAddr = crypto.CreateAddress2(caller.Address(), common.Hash(salt.Bytes32()), codeAndHash.Hash().Bytes())
-	return evm.create(caller, codeAndHash, gas, endowment, contractAddr)
+	return evm.create(caller, codeAndHash, gas, endowment, contractAddr, CREATE2)
 }
 
 // AuthCall executes the contract associated with the addr with the given input
@@ -514,6 +571,13 @@ func (evm *EVM) AuthCall(sponsor common.Address, caller ContractRef, addr common
 	}
 	evm.Transfer(evm.StateDB, sponsor, addr, value)
 
+	if evm.debug {
+		evm.captureBegin(AUTHCALL, caller.Address(), addr, input, gas, value)
+		defer func(startGas uint64, startTime time.Time) {
+			evm.captureEnd(ret, startGas-gas, startTime, err)
+		}(gas, time.Now())
+	}
+
 	if isPrecompile {
 		ret, gas, err = RunPrecompiledContract(p, input, gas)
 	} else {
@@ -543,3 +607,22 @@ func (evm *EVM) AuthCall(sponsor common.Address, caller ContractRef, addr common
 	}
 	return ret, gas, logs, err
 }
+
+// captureBegin reports a new call frame to the tracer. The outermost frame
+// starts the trace, nested ones are reported as entered frames.
+func (evm *EVM) captureBegin(typ OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if evm.depth == 0 {
+		evm.tracer.CaptureStart(from, to, typ == CREATE || typ == CREATE2, input, gas, value)
+	} else {
+		evm.tracer.CaptureEnter(typ, from, to, input, gas, value)
+	}
+}
+
+// captureEnd closes the call frame opened by captureBegin.
+func (evm *EVM) captureEnd(output []byte, gasUsed uint64, startTime time.Time, err error) {
+	if evm.depth == 0 {
+		evm.tracer.CaptureEnd(output, gasUsed, time.Since(startTime), err)
+	} else {
+		evm.tracer.CaptureExit(output, gasUsed, err)
+	}
+}
@@ -46,7 +46,7 @@ func memoryGasCost(mem *Memory, newMemSize uint64) (uint64, error) {
 
 		fee := newTotalFee - mem.lastGasCost
 		mem.lastGasCost = newTotalFee
-		if common.IsProposal026() {
+		if common.CurrentRules().IsProposal026 {
 			return fee * common.GasMagnification, nil
 		}
 		return fee, nil
@@ -81,7 +81,7 @@ func memoryCopierGas(stackpos int) gasFunc {
 		if gas, overflow = utility.SafeAdd(gas, words); overflow {
 			return 0, ErrGasUintOverflow
 		}
-		if common.IsProposal026() {
+		if common.CurrentRules().IsProposal026 {
 			return gas * common.GasMagnification, nil
 		}
 		return gas, nil
@@ -97,20 +97,20 @@ var (
 )
 
 func gasSStore(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
-	if common.IsProposal026() {
+	if common.CurrentRules().IsProposal026 {
 		return SstoreSetGas * common.GasMagnification, nil
 	}
-	if common.IsProposal015() {
+	if common.CurrentRules().IsProposal015 {
 		return SstoreSetGas, nil
 	}
 	return 0, nil
 }
 
 func gasSStoreEIP2200(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
-	if common.IsProposal026() {
+	if common.CurrentRules().IsProposal026 {
 		return SstoreSetGasEIP2200 * common.GasMagnification, nil
 	}
-	if common.IsProposal015() {
+	if common.CurrentRules().IsProposal015 {
 		return SstoreSetGasEIP2200, nil // dirty update (2.2)
 	}
 	return 0, nil
@@ -142,7 +142,7 @@ func makeGasLog(n uint64) gasFunc {
 		if gas, overflow = utility.SafeAdd(gas, memorySizeGas); overflow {
 			return 0, ErrGasUintOverflow
 		}
-		if common.IsProposal026() {
+		if common.CurrentRules().IsProposal026 {
 			return gas * common.GasMagnification, nil
 		}
 		return gas, nil
@@ -164,7 +164,7 @@ func gasSha3(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize
 	if gas, overflow = utility.SafeAdd(gas, wordGas); overflow {
 		return 0, ErrGasUintOverflow
 	}
-	if common.IsProposal026() {
+	if common.CurrentRules().IsProposal026 {
 		return gas * common.GasMagnification, nil
 	}
 	return gas, nil
@@ -202,7 +202,7 @@ func gasCreate2(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memoryS
 	if gas, overflow = utility.SafeAdd(gas, wordGas); overflow {
 		return 0, ErrGasUintOverflow
 	}
-	if common.IsProposal026() {
+	if common.CurrentRules().IsProposal026 {
 		return gas * common.GasMagnification, nil
 	}
 	return gas, nil
@@ -218,7 +218,7 @@ func gasExpFrontier(evm *EVM, contract *Contract, stack *Stack, mem *Memory, mem
 	if gas, overflow = utility.SafeAdd(gas, ExpGas); overflow {
 		return 0, ErrGasUintOverflow
 	}
-	if common.IsProposal026() {
+	if common.CurrentRules().IsProposal026 {
 		return gas * common.GasMagnification, nil
 	}
 	return gas, nil
@@ -234,7 +234,7 @@ func gasExpEIP158(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memor
 	if gas, overflow = utility.SafeAdd(gas, ExpGas); overflow {
 		return 0, ErrGasUintOverflow
 	}
-	if common.IsProposal026() {
+	if common.CurrentRules().IsProposal026 {
 		return gas * common.GasMagnification, nil
 	}
 	return gas, nil
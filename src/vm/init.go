@@ -29,6 +29,7 @@ type LogConfig struct {
 	DisableStack      bool // disable stack capture
 	DisableStorage    bool // disable storage capture
 	DisableReturnData bool // disable return data capture
+	Limit             int  // maximum length of output, but zero means unlimited
 }
 
 var (
@@ -23,6 +23,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"testing"
 
 	"github.com/holiman/uint256"
@@ -302,6 +303,48 @@ func TestOpMstore(t *testing.T) {
 	}
 }
 
+func TestOpRandom(t *testing.T) {
+	defer func(height uint64) { common.LocalChainConfig.Forks[common.Proposal028] = height }(common.LocalChainConfig.Forks[common.Proposal028])
+	common.LocalChainConfig.Forks[common.Proposal028] = 100
+
+	random := common.HexToHash("0x4f4a0c0e2b5d8a6f1d7e9b3c5a2f8e6d4c1b9a7f5e3d2c1b0a9f8e7d6c5b4a39")
+	tests := []struct {
+		height   uint64
+		expected *uint256.Int
+	}{
+		{99, uint256.NewInt().SetUint64(123)},
+		{100, new(uint256.Int).SetBytes(random.Bytes())},
+	}
+	for _, test := range tests {
+		var (
+			env = NewEVM(Context{
+				BlockNumber: new(big.Int).SetUint64(test.height),
+				Difficulty:  big.NewInt(123),
+				GetRandom:   func() common.Hash { return random },
+			}, nil)
+			stack, rstack  = newstack(), newReturnStack()
+			pc             = uint64(0)
+			evmInterpreter = env.interpreter.(*EVMInterpreter)
+		)
+		evmInterpreter.jumpTable[DIFFICULTY].execute(&pc, evmInterpreter, &callCtx{nil, stack, rstack, nil, nil, make([]*types.Log, 0)})
+		if actual := stack.pop(); actual.Cmp(test.expected) != 0 {
+			t.Errorf("DIFFICULTY at height %d: expected %x, got %x", test.height, test.expected, actual)
+		}
+	}
+
+	// the contexts without the randomness fall back to the difficulty
+	var (
+		env            = NewEVM(Context{BlockNumber: big.NewInt(100), Difficulty: big.NewInt(123)}, nil)
+		stack, rstack  = newstack(), newReturnStack()
+		pc             = uint64(0)
+		evmInterpreter = env.interpreter.(*EVMInterpreter)
+	)
+	evmInterpreter.jumpTable[DIFFICULTY].execute(&pc, evmInterpreter, &callCtx{nil, stack, rstack, nil, nil, make([]*types.Log, 0)})
+	if actual := stack.pop(); actual.Cmp(uint256.NewInt().SetUint64(123)) != 0 {
+		t.Errorf("PREVRANDAO without randomness: expected 123, got %x", actual)
+	}
+}
+
 func opBenchmark(bench *testing.B, op executionFunc, args ...string) {
 	var (
 		env            = NewEVM(Context{}, nil)
@@ -68,7 +68,6 @@ type keccakState interface {
 // EVMInterpreter represents an EVM interpreter
 type EVMInterpreter struct {
 	evm       *EVM
-	tracer    Tracer
 	jumpTable JumpTable
 
 	hasher    keccakState // Keccak256 hasher instance shared across opcodes
@@ -85,19 +84,21 @@ func NewEVMInterpreter(evm *EVM) *EVMInterpreter {
 		jumpTable: newInstructionSet(),
 	}
 
-	height := evm.BlockNumber.Uint64()
+	rules := common.LocalChainConfig.Rules(evm.BlockNumber.Uint64())
 	jt := &interpreter.jumpTable
-	if height >= common.LocalChainConfig.Proposal014Block {
+	if rules.IsProposal014 {
 		doProposal014(jt)
 	}
-	if height >= common.LocalChainConfig.Proposal022Block {
+	if rules.IsProposal022 {
 		doProposal022(jt)
 	}
-	if height >= common.LocalChainConfig.Proposal026Block {
+	if rules.IsProposal028 {
+		doProposal028(jt)
+	}
+	// the gas magnification applies to the whole jump table, so it goes last
+	if rules.IsProposal026 {
 		doProposal026(jt)
 	}
-	config := LogConfig{false, false, false, false}
-	interpreter.tracer = NewStructLogger(&config, logger)
 	return interpreter
 }
 
@@ -148,10 +149,10 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 		pc   = uint64(0) // program counter
 		cost uint64
 		// copies used by tracer
-		//pcCopy  uint64 // needed for the deferred Tracer
-		//gasCopy uint64 // for Tracer to log gas remaining before execution
-		//logged  bool   // deferred Tracer should ignore already logged steps
-		res []byte // result of the opcode execution function
+		pcCopy  uint64 // needed for the deferred Tracer
+		gasCopy uint64 // for Tracer to log gas remaining before execution
+		logged  bool   // deferred Tracer should ignore already logged steps
+		res     []byte // result of the opcode execution function
 	)
 	// Don't move this deferrred function, it's placed before the capturestate-deferred method,
 	// so that it get's executed _after_: the capturestate needs the stacks before
@@ -162,15 +163,17 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 	}()
 	contract.Input = input
 
-	defer func() {
-		if err != nil {
-			//if !logged {
-			//	in.tracer.CaptureState(in.evm, pcCopy, op, gasCopy, cost, mem, stack, returns, in.returnData, contract, in.evm.depth, err)
-			//} else {
-			//	in.tracer.CaptureFault(in.evm, pcCopy, op, gasCopy, cost, mem, stack, returns, contract, in.evm.depth, err)
-			//}
-		}
-	}()
+	if in.evm.debug {
+		defer func() {
+			if err != nil {
+				if !logged {
+					in.evm.tracer.CaptureState(in.evm, pcCopy, op, gasCopy, cost, mem, stack, returns, in.returnData, contract, in.evm.depth, err)
+				} else {
+					in.evm.tracer.CaptureFault(in.evm, pcCopy, op, gasCopy, cost, mem, stack, returns, contract, in.evm.depth, err)
+				}
+			}
+		}()
+	}
 
 	// The Interpreter main run loop (contextual). This loop runs until either an
 	// explicit STOP, RETURN or SELFDESTRUCT is executed, an error occurred during
@@ -182,8 +185,10 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 		if steps%1000 == 0 && atomic.LoadInt32(&in.evm.abort) != 0 {
 			break
 		}
-		// Capture pre-execution values for tracing.
-		//logged, pcCopy, gasCopy = false, pc, contract.Gas
+		if in.evm.debug {
+			// Capture pre-execution values for tracing.
+			logged, pcCopy, gasCopy = false, pc, contract.Gas
+		}
 
 		// Get the operation from the jump table and validate the stack to ensure there are
 		// enough stack items available to perform the operation.
@@ -249,8 +254,10 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 			mem.Resize(memorySize)
 		}
 
-		//in.tracer.CaptureState(in.evm, pc, op, gasCopy, cost, mem, stack, returns, in.returnData, contract, in.evm.depth, err)
-		//logged = true
+		if in.evm.debug {
+			in.evm.tracer.CaptureState(in.evm, pc, op, gasCopy, cost, mem, stack, returns, in.returnData, contract, in.evm.depth, err)
+			logged = true
+		}
 
 		// execute the operation
 		res, err = operation.execute(&pc, in, callContext)
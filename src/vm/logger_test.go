@@ -70,3 +70,51 @@ func TestStoreCapture(t *testing.T) {
 		t.Errorf("expected %x, got %x", exp, logger.storage[contract.Address()][index])
 	}
 }
+
+func TestCallTracer(t *testing.T) {
+	mockInit()
+	config := new(testConfig)
+	setDefaults(config)
+	defer log.Close()
+
+	var (
+		caller = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		outer  = common.HexToAddress("0x2000000000000000000000000000000000000002")
+		inner  = common.HexToAddress("0x3000000000000000000000000000000000000003")
+	)
+	// outer: CALL(gas=0xffff, inner, value=0, in=0:0, out=0:0), STOP
+	outerCode := append(common.FromHex("0x60006000600060006000"), append(append([]byte{byte(PUSH20)}, inner.Bytes()...), common.FromHex("0x61fffff100")...)...)
+	config.State.CreateAccount(outer)
+	config.State.SetCode(outer, outerCode)
+	config.State.CreateAccount(inner)
+	config.State.SetCode(inner, []byte{byte(STOP)})
+
+	tracer := NewCallTracer()
+	structLogger := NewStructLogger(nil, nil)
+	evm := mockEVM(config)
+	evm.SetTracer(tracer)
+	if _, _, _, err := evm.Call(AccountRef(caller), outer, nil, 100000, big.NewInt(0)); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+
+	result := tracer.GetResult()
+	if result.Type != "CALL" || *result.To != outer {
+		t.Fatalf("unexpected root frame: %s %s", result.Type, result.To.GetHexString())
+	}
+	if len(result.Calls) != 1 || *result.Calls[0].To != inner {
+		t.Fatalf("expected exactly 1 inner call, got %d", len(result.Calls))
+	}
+	if result.GasUsed == 0 || result.Calls[0].GasUsed > result.GasUsed {
+		t.Errorf("unexpected gas used, outer: %d, inner: %d", result.GasUsed, result.Calls[0].GasUsed)
+	}
+
+	evm = mockEVM(config)
+	evm.SetTracer(structLogger)
+	if _, _, _, err := evm.Call(AccountRef(caller), outer, nil, 100000, big.NewInt(0)); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	// 9 opcodes in outer and 1 in inner
+	if len(structLogger.StructLogs()) != 10 {
+		t.Errorf("expected 10 struct logs, got %d", len(structLogger.StructLogs()))
+	}
+}
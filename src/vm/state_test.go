@@ -0,0 +1,91 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"com.tuntun.rangers/node/src/middleware/log"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestGeneralStateTests runs the GeneralStateTests fixtures of ethereum/tests found in
+// GENERAL_STATE_TESTS (testdata/GeneralStateTests by default) and reports the result per fork.
+// The rules of the vm are selected by the fork, or by STATE_TEST_PROPOSAL, such as Proposal026, if set.
+func TestGeneralStateTests(t *testing.T) {
+	dir := os.Getenv("GENERAL_STATE_TESTS")
+	if dir == "" {
+		dir = filepath.Join("testdata", "GeneralStateTests")
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Skipf("no state tests found in %s", dir)
+	}
+	proposal := os.Getenv("STATE_TEST_PROPOSAL")
+	mockInit()
+	defer log.Close()
+
+	// passed, failed and skipped subtests per fork
+	report := make(map[string]*[3]int)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".json") {
+			return err
+		}
+		tests, err := loadStateTests(path)
+		if err != nil {
+			t.Error(err)
+			return nil
+		}
+		name, _ := filepath.Rel(dir, path)
+		for key, test := range tests {
+			for _, subtest := range test.subtests() {
+				if report[subtest.Fork] == nil {
+					report[subtest.Fork] = new([3]int)
+				}
+				counts := report[subtest.Fork]
+				t.Run(fmt.Sprintf("%s/%s/%s/%d", name, key, subtest.Fork, subtest.Index), func(t *testing.T) {
+					switch err := test.run(subtest, proposal); err {
+					case nil:
+						counts[0]++
+					case errStateTestUnsupportedFork:
+						counts[2]++
+						t.Skip(err)
+					default:
+						counts[1]++
+						t.Error(err)
+					}
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forks := make([]string, 0, len(report))
+	for fork := range report {
+		forks = append(forks, fork)
+	}
+	sort.Strings(forks)
+	for _, fork := range forks {
+		counts := report[fork]
+		t.Logf("%-10s passed: %d, failed: %d, skipped: %d", fork, counts[0], counts[1], counts[2])
+	}
+}
@@ -0,0 +1,565 @@
+// Copyright 2020 The RangersProtocol Authors
+// This file is part of the RocketProtocol library.
+//
+// The RangersProtocol library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The RangersProtocol library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the RangersProtocol library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"bytes"
+	"com.tuntun.rangers/node/src/common"
+	crypto "com.tuntun.rangers/node/src/eth_crypto"
+	"com.tuntun.rangers/node/src/middleware/db"
+	"com.tuntun.rangers/node/src/middleware/types"
+	"com.tuntun.rangers/node/src/storage/account"
+	"com.tuntun.rangers/node/src/storage/rlp"
+	"com.tuntun.rangers/node/src/storage/trie"
+	"com.tuntun.rangers/node/src/utility"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"sort"
+)
+
+//GeneralStateTests of ethereum/tests
+
+// stateTestFork selects the rules of the vm closest to an ethereum fork.
+// The transaction itself is processed by the ethereum rules of the fork, only the vm is under test.
+type stateTestFork struct {
+	proposal string // the proposal whose jump table is in force
+	london   bool   // eip-1559 fees and eip-3529 refunds
+	merge    bool   // DIFFICULTY returns the random of the block
+}
+
+// forks before Berlin are not supported, the vm always charges the eip-2929 access costs
+var stateTestForks = map[string]stateTestFork{
+	"Berlin":   {proposal: "Proposal014"},
+	"London":   {proposal: "Proposal022", london: true},
+	"Merge":    {proposal: "Proposal022", london: true, merge: true},
+	"Paris":    {proposal: "Proposal022", london: true, merge: true},
+	"Shanghai": {proposal: "Proposal022", london: true, merge: true},
+	"Cancun":   {proposal: "Proposal022", london: true, merge: true},
+}
+
+// stateTestChainId is the chain id the fixtures are generated with
+var stateTestChainId = big.NewInt(1)
+
+var errStateTestUnsupportedFork = errors.New("unsupported fork")
+
+// stateTestProposalHeight returns the height the vm runs at under the jump table of the proposal.
+// It is the last height before the next proposal changing the jump table, so the proposals in between apply too.
+func stateTestProposalHeight(proposal string) (uint64, bool) {
+	switch proposal {
+	case "Proposal014":
+		return common.LocalChainConfig.Forks[common.Proposal022] - 1, true
+	case "Proposal022":
+		return common.LocalChainConfig.Forks[common.Proposal026] - 1, true
+	case "Proposal026":
+		return common.LocalChainConfig.Forks[common.Proposal026], true
+	}
+	return 0, false
+}
+
+type stateTest struct {
+	Env  stateTestEnv                        `json:"env"`
+	Pre  map[common.Address]stateTestAccount `json:"pre"`
+	Tx   stateTestTransaction                `json:"transaction"`
+	Post map[string][]stateTestPost          `json:"post"`
+}
+
+type stateTestEnv struct {
+	Coinbase   common.Address           `json:"currentCoinbase"`
+	Difficulty *utility.HexOrDecimal256 `json:"currentDifficulty"`
+	Random     *utility.HexOrDecimal256 `json:"currentRandom"`
+	GasLimit   utility.HexOrDecimal64   `json:"currentGasLimit"`
+	Number     utility.HexOrDecimal64   `json:"currentNumber"`
+	Timestamp  utility.HexOrDecimal64   `json:"currentTimestamp"`
+	BaseFee    *utility.HexOrDecimal256 `json:"currentBaseFee"`
+}
+
+type stateTestAccount struct {
+	Balance *utility.HexOrDecimal256 `json:"balance"`
+	Code    utility.Bytes            `json:"code"`
+	Nonce   utility.HexOrDecimal64   `json:"nonce"`
+	Storage map[string]string        `json:"storage"`
+}
+
+type stateTestTransaction struct {
+	Data                 []utility.Bytes          `json:"data"`
+	GasLimit             []utility.HexOrDecimal64 `json:"gasLimit"`
+	Value                []string                 `json:"value"`
+	AccessLists          []*types.AccessList      `json:"accessLists"`
+	GasPrice             *utility.HexOrDecimal256 `json:"gasPrice"`
+	MaxFeePerGas         *utility.HexOrDecimal256 `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *utility.HexOrDecimal256 `json:"maxPriorityFeePerGas"`
+	Nonce                utility.HexOrDecimal64   `json:"nonce"`
+	To                   string                   `json:"to"`
+	Sender               string                   `json:"sender"`
+	SecretKey            utility.Bytes            `json:"secretKey"`
+}
+
+type stateTestPost struct {
+	Root            common.Hash `json:"hash"`
+	Logs            common.Hash `json:"logs"`
+	ExpectException string      `json:"expectException"`
+	Indexes         struct {
+		Data  int `json:"data"`
+		Gas   int `json:"gas"`
+		Value int `json:"value"`
+	} `json:"indexes"`
+}
+
+// stateSubtest is a post state of a state test, one per fork and transaction variant
+type stateSubtest struct {
+	Fork  string
+	Index int
+}
+
+// stateTestMessage is the transaction variant selected by the indexes of a post state
+type stateTestMessage struct {
+	from          common.Address
+	to            *common.Address
+	nonce         uint64
+	gasLimit      uint64
+	value         *big.Int
+	data          []byte
+	accessList    types.AccessList
+	gasPrice      *big.Int // price paid for the gas
+	gasFeeCap     *big.Int // price the balance must cover
+	coinbasePrice *big.Int // price received by the coinbase
+}
+
+// stateTestDB records the addresses written by the vm.
+// The balances are kept by the balance ledger, so an account may hold balance without an account object.
+type stateTestDB struct {
+	*account.AccountDB
+	addresses map[common.Address]struct{}
+}
+
+func (s *stateTestDB) CreateAccount(addr common.Address) {
+	s.addresses[addr] = struct{}{}
+	s.AccountDB.CreateAccount(addr)
+}
+
+func (s *stateTestDB) AddBalance(addr common.Address, amount *big.Int) {
+	s.addresses[addr] = struct{}{}
+	s.AccountDB.AddBalance(addr, amount)
+}
+
+func (s *stateTestDB) SetNonce(addr common.Address, nonce uint64) {
+	s.addresses[addr] = struct{}{}
+	s.AccountDB.SetNonce(addr, nonce)
+}
+
+func (s *stateTestDB) SetCode(addr common.Address, code []byte) {
+	s.addresses[addr] = struct{}{}
+	s.AccountDB.SetCode(addr, code)
+}
+
+func (s *stateTestDB) SetState(addr common.Address, key, value common.Hash) {
+	s.addresses[addr] = struct{}{}
+	s.AccountDB.SetState(addr, key, value)
+}
+
+// stateTestEthAccount is the ethereum consensus encoding of an account
+type stateTestEthAccount struct {
+	Nonce    uint64
+	Balance  *big.Int
+	Root     common.Hash
+	CodeHash []byte
+}
+
+// stateTestLog is the ethereum consensus encoding of a log
+type stateTestLog struct {
+	Address common.Address
+	Topics  []common.Hash
+	Data    []byte
+}
+
+// loadStateTests reads a fixture file of GeneralStateTests
+func loadStateTests(file string) (map[string]*stateTest, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	tests := make(map[string]*stateTest)
+	if err := json.Unmarshal(data, &tests); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return tests, nil
+}
+
+// subtests returns the post states of the test ordered by fork
+func (t *stateTest) subtests() []stateSubtest {
+	forks := make([]string, 0, len(t.Post))
+	for fork := range t.Post {
+		forks = append(forks, fork)
+	}
+	sort.Strings(forks)
+
+	subtests := make([]stateSubtest, 0)
+	for _, fork := range forks {
+		for i := range t.Post[fork] {
+			subtests = append(subtests, stateSubtest{fork, i})
+		}
+	}
+	return subtests
+}
+
+// run executes the transaction variant of the subtest and checks the post state root and the logs hash.
+// The rules of the vm are selected by the proposal if it is not empty, otherwise by the fork.
+func (t *stateTest) run(subtest stateSubtest, proposal string) error {
+	fork, ok := stateTestForks[subtest.Fork]
+	if !ok {
+		return errStateTestUnsupportedFork
+	}
+	if proposal == "" {
+		proposal = fork.proposal
+	}
+	height, ok := stateTestProposalHeight(proposal)
+	if !ok {
+		return fmt.Errorf("unknown proposal %s", proposal)
+	}
+	post := t.Post[subtest.Fork][subtest.Index]
+	common.SetBlockHeight(height)
+
+	statedb, err := t.makePreState()
+	if err != nil {
+		return err
+	}
+	logs, err := t.apply(statedb, post, fork, height)
+	if err != nil {
+		if post.ExpectException != "" {
+			return nil
+		}
+		return err
+	}
+	if post.ExpectException != "" {
+		return fmt.Errorf("expected exception %s", post.ExpectException)
+	}
+
+	if _, err := statedb.Commit(true); err != nil {
+		return err
+	}
+	root, err := stateTestRoot(statedb.AccountDB, statedb.addresses)
+	if err != nil {
+		return err
+	}
+	if root != post.Root {
+		return fmt.Errorf("post state root mismatch: got %x, want %x", root, post.Root)
+	}
+	if hash := stateTestLogsHash(logs); hash != post.Logs {
+		return fmt.Errorf("post state logs hash mismatch: got %x, want %x", hash, post.Logs)
+	}
+	return nil
+}
+
+// makePreState builds the committed pre state in memory
+func (t *stateTest) makePreState() (*stateTestDB, error) {
+	database, _ := db.NewMemDatabase()
+	accountDatabase := account.NewDatabase(database)
+	statedb, err := account.NewAccountDB(common.Hash{}, accountDatabase)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make(map[common.Address]struct{})
+	for addr, a := range t.Pre {
+		addresses[addr] = struct{}{}
+		statedb.SetNonce(addr, uint64(a.Nonce))
+		if a.Balance != nil {
+			statedb.SetBalance(addr, (*big.Int)(a.Balance))
+		}
+		if 0 != len(a.Code) {
+			statedb.SetCode(addr, a.Code)
+		}
+		for key, value := range a.Storage {
+			if value := common.HexToHash(value); value != (common.Hash{}) {
+				statedb.SetState(addr, common.HexToHash(key), value)
+			}
+		}
+	}
+	root, err := statedb.Commit(false)
+	if err != nil {
+		return nil, err
+	}
+	statedb, err = account.NewAccountDB(root, accountDatabase)
+	if err != nil {
+		return nil, err
+	}
+	return &stateTestDB{statedb, addresses}, nil
+}
+
+// message returns the transaction variant selected by the indexes of the post state
+func (t *stateTest) message(post stateTestPost, fork stateTestFork) (*stateTestMessage, error) {
+	tx := t.Tx
+	if post.Indexes.Data >= len(tx.Data) || post.Indexes.Gas >= len(tx.GasLimit) || post.Indexes.Value >= len(tx.Value) {
+		return nil, errors.New("tx index out of range")
+	}
+	msg := &stateTestMessage{
+		nonce:    uint64(tx.Nonce),
+		gasLimit: uint64(tx.GasLimit[post.Indexes.Gas]),
+		data:     tx.Data[post.Indexes.Data],
+	}
+
+	if 0 != len(tx.SecretKey) {
+		key, err := crypto.ToECDSA(tx.SecretKey)
+		if err != nil {
+			return nil, err
+		}
+		msg.from = crypto.PubkeyToAddress(key.PublicKey)
+	} else {
+		msg.from = common.HexToAddress(tx.Sender)
+	}
+	if tx.To != "" {
+		to := common.HexToAddress(tx.To)
+		msg.to = &to
+	}
+	value, ok := utility.ParseBig256(tx.Value[post.Indexes.Value])
+	if !ok {
+		return nil, fmt.Errorf("invalid tx value %q", tx.Value[post.Indexes.Value])
+	}
+	msg.value = value
+	if post.Indexes.Data < len(tx.AccessLists) && tx.AccessLists[post.Indexes.Data] != nil {
+		msg.accessList = *tx.AccessLists[post.Indexes.Data]
+	}
+
+	baseFee := new(big.Int)
+	if fork.london && t.Env.BaseFee != nil {
+		baseFee = (*big.Int)(t.Env.BaseFee)
+	}
+	switch {
+	case tx.GasPrice != nil:
+		msg.gasPrice = (*big.Int)(tx.GasPrice)
+		msg.gasFeeCap = msg.gasPrice
+	case tx.MaxFeePerGas != nil && fork.london:
+		msg.gasFeeCap = (*big.Int)(tx.MaxFeePerGas)
+		tipCap := new(big.Int)
+		if tx.MaxPriorityFeePerGas != nil {
+			tipCap = (*big.Int)(tx.MaxPriorityFeePerGas)
+		}
+		if tipCap.Cmp(msg.gasFeeCap) > 0 {
+			return nil, errors.New("max priority fee per gas higher than max fee per gas")
+		}
+		msg.gasPrice = new(big.Int).Add(tipCap, baseFee)
+		if msg.gasPrice.Cmp(msg.gasFeeCap) > 0 {
+			msg.gasPrice = msg.gasFeeCap
+		}
+	default:
+		return nil, errors.New("tx without gas price")
+	}
+	if msg.gasFeeCap.Cmp(baseFee) < 0 {
+		return nil, errors.New("max fee per gas less than block base fee")
+	}
+	msg.coinbasePrice = new(big.Int).Sub(msg.gasPrice, baseFee)
+	return msg, nil
+}
+
+// apply processes the transaction by the ethereum rules and returns the logs.
+// The returned error is not nil only if the transaction is invalid.
+func (t *stateTest) apply(statedb *stateTestDB, post stateTestPost, fork stateTestFork, height uint64) ([]*types.Log, error) {
+	msg, err := t.message(post, fork)
+	if err != nil {
+		return nil, err
+	}
+
+	intrinsicGas, err := stateTestIntrinsicGas(msg.data, msg.accessList, msg.to == nil)
+	if err != nil {
+		return nil, err
+	}
+	if nonce := statedb.GetNonce(msg.from); nonce != msg.nonce {
+		return nil, fmt.Errorf("nonce mismatch: state %d, tx %d", nonce, msg.nonce)
+	}
+	if 0 != statedb.GetCodeSize(msg.from) {
+		return nil, errors.New("sender not an eoa")
+	}
+	if msg.gasLimit > uint64(t.Env.GasLimit) {
+		return nil, errors.New("gas limit reached")
+	}
+	if msg.gasLimit < intrinsicGas {
+		return nil, errors.New("intrinsic gas too low")
+	}
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(msg.gasLimit), msg.gasFeeCap)
+	cost.Add(cost, msg.value)
+	if statedb.GetBalance(msg.from).Cmp(cost) < 0 {
+		return nil, errors.New("insufficient funds for gas * price + value")
+	}
+
+	statedb.SubBalance(msg.from, new(big.Int).Mul(new(big.Int).SetUint64(msg.gasLimit), msg.gasPrice))
+	vmCtx := Context{
+		CanTransfer: CanTransfer,
+		Transfer:    Transfer,
+		GetHash:     stateTestBlockHash,
+		Origin:      msg.from,
+		GasPrice:    msg.gasPrice,
+		Coinbase:    t.Env.Coinbase,
+		GasLimit:    uint64(t.Env.GasLimit),
+		BlockNumber: new(big.Int).SetUint64(height),
+		Time:        new(big.Int).SetUint64(uint64(t.Env.Timestamp)),
+		Difficulty:  new(big.Int),
+	}
+	if fork.merge && t.Env.Random != nil {
+		vmCtx.Difficulty = (*big.Int)(t.Env.Random)
+	} else if t.Env.Difficulty != nil {
+		vmCtx.Difficulty = (*big.Int)(t.Env.Difficulty)
+	}
+	// DIFFICULTY is PREVRANDAO since Proposal028, whichever the height of the rules
+	random := common.BigToHash(vmCtx.Difficulty)
+	vmCtx.GetRandom = func() common.Hash { return random }
+	evm := NewEVMWithNFT(vmCtx, statedb, statedb.AccountDB)
+	// the rules are selected by the height, while NUMBER and CHAINID return the values of the fixture
+	evm.BlockNumber = new(big.Int).SetUint64(uint64(t.Env.Number))
+	evm.chainID = stateTestChainId
+
+	statedb.AddAddressToAccessList(msg.from)
+	if msg.to != nil {
+		statedb.AddAddressToAccessList(*msg.to)
+	}
+	for _, addr := range evm.ActivePrecompiles() {
+		statedb.AddAddressToAccessList(addr)
+	}
+	for _, el := range msg.accessList {
+		statedb.AddAddressToAccessList(el.Address)
+		for _, key := range el.StorageKeys {
+			statedb.AddSlotToAccessList(el.Address, key)
+		}
+	}
+
+	var (
+		caller      = AccountRef(msg.from)
+		gas         = msg.gasLimit - intrinsicGas
+		leftOverGas uint64
+		logs        []*types.Log
+		vmerr       error
+	)
+	if msg.to == nil {
+		_, _, leftOverGas, logs, vmerr = evm.Create(caller, msg.data, gas, msg.value)
+	} else {
+		statedb.SetNonce(msg.from, msg.nonce+1)
+		_, leftOverGas, logs, vmerr = evm.Call(caller, *msg.to, msg.data, gas, msg.value)
+	}
+	if vmerr != nil {
+		logs = nil
+	}
+
+	refundQuotient := uint64(2)
+	if fork.london {
+		refundQuotient = 5
+	}
+	gasUsed := msg.gasLimit - leftOverGas
+	refund := statedb.GetRefund()
+	if refund > gasUsed/refundQuotient {
+		refund = gasUsed / refundQuotient
+	}
+	gasUsed -= refund
+	statedb.AddBalance(msg.from, new(big.Int).Mul(new(big.Int).SetUint64(msg.gasLimit-gasUsed), msg.gasPrice))
+	statedb.AddBalance(t.Env.Coinbase, new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), msg.coinbasePrice))
+	return logs, nil
+}
+
+// stateTestIntrinsicGas computes the ethereum intrinsic gas of a transaction
+func stateTestIntrinsicGas(data []byte, accessList types.AccessList, contractCreation bool) (uint64, error) {
+	gas := TxGas
+	if contractCreation {
+		gas = TxGasContractCreation
+	}
+	var nz uint64
+	for _, b := range data {
+		if b != 0 {
+			nz++
+		}
+	}
+	z := uint64(len(data)) - nz
+	if (utility.MaxUint64-gas)/TxDataNonZeroGasEIP2028 < nz {
+		return 0, ErrGasUintOverflow
+	}
+	gas += nz * TxDataNonZeroGasEIP2028
+	if (utility.MaxUint64-gas)/TxDataZeroGas < z {
+		return 0, ErrGasUintOverflow
+	}
+	gas += z * TxDataZeroGas
+	gas += uint64(len(accessList)) * TxAccessListAddressGas
+	gas += uint64(accessList.StorageKeys()) * TxAccessListStorageKeyGas
+	return gas, nil
+}
+
+// stateTestBlockHash is the block hash of the fixtures
+func stateTestBlockHash(n uint64) common.Hash {
+	return common.BytesToHash(crypto.Keccak256([]byte(new(big.Int).SetUint64(n).String())))
+}
+
+// stateTestRoot computes the ethereum state root of the addresses.
+// The storage of the balance ledger is left out, it holds the balances of all accounts.
+func stateTestRoot(statedb *account.AccountDB, addresses map[common.Address]struct{}) (common.Hash, error) {
+	database, _ := db.NewMemDatabase()
+	triedb := trie.NewDatabase(database)
+	accounts, err := trie.NewTrie(common.Hash{}, triedb)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	_, ledger, _, _ := statedb.GetERC20Binding(common.BLANCE_NAME)
+
+	for addr := range addresses {
+		balance := statedb.GetBalance(addr)
+		if !statedb.Exist(addr) && 0 == balance.Sign() {
+			continue
+		}
+
+		storage, err := trie.NewTrie(common.Hash{}, triedb)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		if it := statedb.DataIterator(addr, nil); it != nil && addr != ledger {
+			for it.Next() {
+				value := bytes.TrimLeft(it.Value, "\x00")
+				if len(it.Key) != common.HashLength || 0 == len(value) {
+					continue
+				}
+				enc, _ := rlp.EncodeToBytes(value)
+				if err := storage.TryUpdate(crypto.Keccak256(it.Key), enc); err != nil {
+					return common.Hash{}, err
+				}
+			}
+			if it.Err != nil {
+				return common.Hash{}, it.Err
+			}
+		}
+
+		data, err := rlp.EncodeToBytes(stateTestEthAccount{
+			Nonce:    statedb.GetNonce(addr),
+			Balance:  balance,
+			Root:     storage.Hash(),
+			CodeHash: crypto.Keccak256(statedb.GetCode(addr)),
+		})
+		if err != nil {
+			return common.Hash{}, err
+		}
+		if err := accounts.TryUpdate(crypto.Keccak256(addr.Bytes()), data); err != nil {
+			return common.Hash{}, err
+		}
+	}
+	return accounts.Hash(), nil
+}
+
+// stateTestLogsHash computes the hash of the rlp encoded logs
+func stateTestLogsHash(logs []*types.Log) common.Hash {
+	encoded := make([]stateTestLog, len(logs))
+	for i, log := range logs {
+		encoded[i] = stateTestLog{log.Address, log.Topics, log.Data}
+	}
+	data, _ := rlp.EncodeToBytes(encoded)
+	return common.BytesToHash(crypto.Keccak256(data))
+}
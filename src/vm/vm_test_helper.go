@@ -44,6 +44,7 @@ type testConfig struct {
 	AccountDatabase account.AccountDatabase
 
 	GetHashFn   func(n uint64) common.Hash
+	GetRandomFn func() common.Hash
 	CanTransfer CanTransferFunc
 	Transfer    TransferFunc
 }
@@ -80,6 +81,12 @@ func setDefaults(cfg *testConfig) {
 		}
 	}
 
+	if cfg.GetRandomFn == nil {
+		cfg.GetRandomFn = func() common.Hash {
+			return common.BigToHash(cfg.Difficulty)
+		}
+	}
+
 	if cfg.CanTransfer == nil {
 		cfg.CanTransfer = CanTransfer
 	}
@@ -224,6 +231,7 @@ func mockEVM(cfg *testConfig) *EVM {
 		CanTransfer: cfg.CanTransfer,
 		Transfer:    cfg.Transfer,
 		GetHash:     cfg.GetHashFn,
+		GetRandom:   cfg.GetRandomFn,
 		Origin:      cfg.Origin,
 		Coinbase:    cfg.Coinbase,
 		BlockNumber: cfg.BlockNumber,